@@ -0,0 +1,90 @@
+package coursesapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// attachmentsPath returns the path of the attachments of the course with the course id given.
+func attachmentsPath(courseID string) string {
+	return coursePath(courseID) + "/attachments"
+}
+
+// attachmentPath returns the path of an attachment of a course.
+func attachmentPath(courseID string, attachmentID int) string {
+	return attachmentsPath(courseID) + "/" + strconv.Itoa(attachmentID)
+}
+
+// ListAttachments retrieves the attachments of the course with the course id given, ordered by
+// upload. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListAttachments(ctx context.Context, courseID string) ([]Attachment, error) {
+	var attachments map[string]Attachment
+	if err := c.do(ctx, http.MethodGet, attachmentsPath(courseID), nil, &attachments); err != nil {
+		return nil, err
+	}
+	return sortAttachments(attachments), nil
+}
+
+// UploadAttachment uploads the contents of r as a PDF or DOCX file attached to the course, and
+// returns the attachment with its ID set. Returns an error matching ErrNotFound if there is no
+// such course, or ErrInvalid if the file is too large or not a PDF or DOCX file.
+func (c *Client) UploadAttachment(ctx context.Context, courseID string, fileName string, r io.Reader) (Attachment, error) {
+	var form bytes.Buffer
+	mw := multipart.NewWriter(&form)
+	part, err := mw.CreateFormFile("file", fileName)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return Attachment{}, err
+	}
+	if err := mw.Close(); err != nil {
+		return Attachment{}, err
+	}
+
+	var attachments map[string]Attachment
+	body := rawBody{contentType: mw.FormDataContentType(), data: form.Bytes()}
+	if err := c.do(ctx, http.MethodPost, attachmentsPath(courseID), body, &attachments); err != nil {
+		return Attachment{}, err
+	}
+
+	for _, a := range sortAttachments(attachments) {
+		return a, nil
+	}
+	return Attachment{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// DownloadAttachment retrieves the file of an attachment of a course.
+// Returns an error matching ErrNotFound if there is no such attachment.
+func (c *Client) DownloadAttachment(ctx context.Context, courseID string, attachmentID int) ([]byte, error) {
+	var file []byte
+	if err := c.do(ctx, http.MethodGet, attachmentPath(courseID, attachmentID), nil, &file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// DeleteAttachment deletes an attachment of a course with its file.
+// Returns an error matching ErrNotFound if there is no such attachment.
+func (c *Client) DeleteAttachment(ctx context.Context, courseID string, attachmentID int) error {
+	return c.do(ctx, http.MethodDelete, attachmentPath(courseID, attachmentID), nil, nil)
+}
+
+// sortAttachments converts the attachments keyed by attachment ID into a slice sorted by ID,
+// which is the order they were uploaded in.
+func sortAttachments(attachments map[string]Attachment) []Attachment {
+	list := make([]Attachment, 0, len(attachments))
+	for id, a := range attachments {
+		a.ID, _ = strconv.Atoi(id)
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
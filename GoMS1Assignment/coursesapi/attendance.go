@@ -0,0 +1,188 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// classesPath returns the path of the classes of the course with the course id given.
+func classesPath(courseID string) string {
+	return coursePath(courseID) + "/classes"
+}
+
+// classPath returns the path of a class of a course.
+func classPath(courseID string, classID int) string {
+	return classesPath(courseID) + "/" + strconv.Itoa(classID)
+}
+
+// ListClasses retrieves the dated classes of the course with the course id given, ordered by
+// date. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListClasses(ctx context.Context, courseID string) ([]Class, error) {
+	var classes map[string]Class
+	if err := c.do(ctx, http.MethodGet, classesPath(courseID), nil, &classes); err != nil {
+		return nil, err
+	}
+	return sortClasses(classes), nil
+}
+
+// AddClass adds the class to its course and returns it with its ID set. Returns an error
+// matching ErrConflict if the course already has a class on the date in the same session,
+// or ErrInvalid if the details are not valid.
+func (c *Client) AddClass(ctx context.Context, class Class) (Class, error) {
+	var classes map[string]Class
+	if err := c.do(ctx, http.MethodPost, classesPath(class.CourseID), class, &classes); err != nil {
+		return Class{}, err
+	}
+
+	for _, cl := range sortClasses(classes) {
+		return cl, nil
+	}
+	return Class{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// UpdateClass updates an existing class. Returns an error matching ErrNotFound if there is
+// no such class, or ErrConflict as for AddClass.
+func (c *Client) UpdateClass(ctx context.Context, class Class) error {
+	return c.do(ctx, http.MethodPut, classPath(class.CourseID, class.ID), class, nil)
+}
+
+// DeleteClass deletes the class of the course with its attendance records.
+// Returns an error matching ErrNotFound if there is no such class.
+func (c *Client) DeleteClass(ctx context.Context, courseID string, classID int) error {
+	return c.do(ctx, http.MethodDelete, classPath(courseID, classID), nil, nil)
+}
+
+// Attendance retrieves the attendance at the class, sorted by student ID, including the
+// students of the course who have not been marked. Returns an error matching ErrNotFound
+// if there is no such class.
+func (c *Client) Attendance(ctx context.Context, courseID string, classID int) ([]AttendanceMark, error) {
+	var marks map[string]AttendanceMark
+	if err := c.do(ctx, http.MethodGet, classPath(courseID, classID)+"/attendance", nil, &marks); err != nil {
+		return nil, err
+	}
+
+	list := make([]AttendanceMark, 0, len(marks))
+	for studentID, m := range marks {
+		m.StudentID = studentID
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StudentID < list[j].StudentID
+	})
+	return list, nil
+}
+
+// MarkAttendance marks the attendance, keyed by student ID, of students at the class,
+// replacing any existing marks of those students. If others is not "", the enrolled students
+// not in marks are marked with that status. Returns an error matching ErrNotFound if there
+// is no such class, or ErrInvalid if a student is not enrolled in the course or a status is
+// not valid.
+func (c *Client) MarkAttendance(ctx context.Context, courseID string, classID int, marks map[string]string, others string) error {
+	query := url.Values{}
+	if others != "" {
+		query.Set("others", others)
+	}
+	if marks == nil {
+		marks = map[string]string{}
+	}
+	return c.doQuery(ctx, http.MethodPut, classPath(courseID, classID)+"/attendance", query, marks, nil)
+}
+
+// CourseAttendance retrieves the attendance of the students of the course enrolled outside
+// any term, sorted by student ID, alerting on those below the threshold. A zero threshold
+// uses the default threshold of the REST API. Returns an error matching ErrNotFound if
+// there is no such course.
+func (c *Client) CourseAttendance(ctx context.Context, courseID string, threshold float64) ([]AttendanceReport, error) {
+	return c.CourseAttendanceInTerm(ctx, courseID, "", threshold)
+}
+
+// CourseAttendanceInTerm is CourseAttendance for the students enrolled in the course in the
+// term given, at the classes held in the weekly sessions of the term. With a termID of ""
+// it is the same as CourseAttendance.
+func (c *Client) CourseAttendanceInTerm(ctx context.Context, courseID, termID string, threshold float64) ([]AttendanceReport, error) {
+	query := thresholdQuery(threshold)
+	if termID != "" {
+		query.Set("term", termID)
+	}
+
+	var reports map[string]AttendanceReport
+	if err := c.doQuery(ctx, http.MethodGet, coursePath(courseID)+"/attendance", query, nil, &reports); err != nil {
+		return nil, err
+	}
+	return sortAttendance(map[string]map[string]AttendanceReport{courseID: reports}), nil
+}
+
+// LearnerAttendance retrieves the attendance of the student at the courses they are enrolled
+// in or have completed, sorted by course ID, as for CourseAttendance. Returns an error
+// matching ErrNotFound if there is no such student.
+func (c *Client) LearnerAttendance(ctx context.Context, studentID string, threshold float64) ([]AttendanceReport, error) {
+	var reports map[string]AttendanceReport
+	path := "/api/v1/learners/" + url.PathEscape(studentID) + "/attendance"
+	if err := c.doQuery(ctx, http.MethodGet, path, thresholdQuery(threshold), nil, &reports); err != nil {
+		return nil, err
+	}
+
+	byCourse := make(map[string]map[string]AttendanceReport, len(reports))
+	for courseID, report := range reports {
+		byCourse[courseID] = map[string]AttendanceReport{studentID: report}
+	}
+	return sortAttendance(byCourse), nil
+}
+
+// AttendanceAlerts retrieves the enrolled students whose attendance at a course is below the
+// threshold, sorted by course ID then student ID. A zero threshold uses the default
+// threshold of the REST API.
+func (c *Client) AttendanceAlerts(ctx context.Context, threshold float64) ([]AttendanceReport, error) {
+	var alerts map[string]map[string]AttendanceReport
+	if err := c.doQuery(ctx, http.MethodGet, "/api/v1/attendance/alerts", thresholdQuery(threshold), nil, &alerts); err != nil {
+		return nil, err
+	}
+	return sortAttendance(alerts), nil
+}
+
+// thresholdQuery returns the query parameters for the threshold of attendance alerts.
+func thresholdQuery(threshold float64) url.Values {
+	query := url.Values{}
+	if threshold != 0 {
+		query.Set("threshold", strconv.FormatFloat(threshold, 'f', -1, 64))
+	}
+	return query
+}
+
+// sortClasses converts the classes keyed by class ID into a slice sorted by date and ID.
+func sortClasses(classes map[string]Class) []Class {
+	list := make([]Class, 0, len(classes))
+	for id, cl := range classes {
+		cl.ID, _ = strconv.Atoi(id)
+		list = append(list, cl)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Date != list[j].Date {
+			return list[i].Date < list[j].Date
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// sortAttendance converts the reports keyed by course ID then student ID into a slice sorted
+// by course ID then student ID.
+func sortAttendance(reports map[string]map[string]AttendanceReport) []AttendanceReport {
+	var list []AttendanceReport
+	for courseID, students := range reports {
+		for studentID, report := range students {
+			report.CourseID, report.StudentID = courseID, studentID
+			list = append(list, report)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CourseID != list[j].CourseID {
+			return list[i].CourseID < list[j].CourseID
+		}
+		return list[i].StudentID < list[j].StudentID
+	})
+	return list
+}
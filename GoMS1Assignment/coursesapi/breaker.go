@@ -0,0 +1,61 @@
+package coursesapi
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker is a circuit breaker which stops requests being sent to the REST API after
+// a number of consecutive failures. Once the cooldown has passed a single trial request
+// is let through; if it succeeds the circuit closes, otherwise it stays open for
+// another cooldown.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool // a trial request is in flight
+}
+
+// allow reports whether a request may be sent.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// record records the outcome of a request which was allowed.
+func (b *breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// abandon releases a request which was allowed without recording its outcome, as when
+// the caller cancelled it.
+func (b *breaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
@@ -0,0 +1,357 @@
+/*
+Package coursesapi is a Go client for the GoSchool REST API.
+
+A Client is created with the base URL of the REST API and an access key:
+
+	c := coursesapi.NewClient("http://localhost:5000", key,
+		coursesapi.WithTimeout(5*time.Second),
+		coursesapi.WithRetries(3, 100*time.Millisecond),
+		coursesapi.WithCircuitBreaker(5, 30*time.Second))
+
+	course, err := c.GetCourse(ctx, "GO101")
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		...
+	}
+
+Errors sending a request, 502, 503 and 504 responses and requests refused by the
+circuit breaker all match ErrUnavailable.
+
+It is separated into 18 .go files to segregate the functionalities of the package.
+
+	client.go: Implements the Client, its options and the sending of requests.
+
+	courses.go: Implements the CRUD operations for courses.
+
+	students.go: Implements the CRUD operations for students.
+
+	enrolments.go: Implements the enrolment of students in courses.
+
+	instructors.go: Implements the CRUD operations for instructors and their assignment to courses.
+
+	prerequisites.go: Implements the prerequisites of courses, study plans and eligibility checks.
+
+	sessions.go: Implements the weekly sessions of courses, timetables and calendar exports.
+
+	terms.go: Implements the CRUD operations for terms and the offerings of courses in them.
+
+	gradebook.go: Implements the assessments of courses, scores, grade scales and gradebooks.
+
+	transcripts.go: Implements the courses students completed and their transcripts.
+
+	attendance.go: Implements the dated classes of courses, attendance and attendance reports.
+
+	departments.go: Implements the CRUD operations for the departments courses belong to.
+
+	tags.go: Implements the tags of courses and their management.
+
+	lifecycle.go: Implements the lifecycle of courses and the review of courses pending approval.
+
+	attachments.go: Implements the files attached to courses, such as syllabi.
+
+	models.go: Defines the request and response models.
+
+	errors.go: Defines the typed errors returned for error responses.
+
+	breaker.go: Implements the circuit breaker which fails fast while the REST API is down.
+*/
+package coursesapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client is a client for the GoSchool REST API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	key        string
+	httpClient *http.Client
+	retries    int
+	retryDelay time.Duration
+	breaker    *breaker
+	editors    []func(*http.Request)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests, e.g. one with an
+// instrumented transport. The timeout of the http.Client given is used as is.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithTimeout sets the timeout for each attempt of a request. Defaults to 10 seconds.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
+// WithRetries sets the number of times an idempotent request (GET, PUT and DELETE) is
+// retried after a network error or a 502, 503 or 504 response. The delay before each
+// retry doubles from the base delay given, up to maxRetryDelay, with random jitter so
+// clients do not retry in lockstep. Defaults to no retries.
+func WithRetries(retries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryDelay = delay
+	}
+}
+
+// WithCircuitBreaker opens the circuit after the number of consecutive failed attempts
+// given, so that requests fail immediately with ErrUnavailable instead of waiting on a
+// REST API which is down. After the cooldown a single request is let through to test
+// whether the REST API has recovered. Defaults to no circuit breaker, as does a number
+// of failures below 1.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if failures < 1 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = &breaker{threshold: failures, cooldown: cooldown}
+	}
+}
+
+// WithRequestEditor adds a function which is called on each request before it is sent,
+// e.g. to set a header from the request context.
+func WithRequestEditor(fn func(*http.Request)) Option {
+	return func(c *Client) {
+		c.editors = append(c.editors, fn)
+	}
+}
+
+// NewClient creates a Client for the REST API at baseURL, e.g. http://localhost:5000,
+// authenticating with the access key given.
+func NewClient(baseURL string, key string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		key:        key,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// BaseURL returns the base URL of the REST API.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Health checks that the REST API is alive. Returns error type.
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/healthz", nil, nil)
+}
+
+// endpoint returns the URL of the path under the REST API with the access key attached.
+func (c *Client) endpoint(path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.key != "" {
+		query.Set("key", c.key)
+	}
+
+	u := c.baseURL + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends a request with in marshalled as the JSON body, if not nil, and unmarshals
+// the JSON response into out, if not nil. If in is a rawBody it is sent as it is, and
+// if out is a *[]byte the response body is stored in it instead. Error responses are
+// returned as *APIError.
+func (c *Client) do(ctx context.Context, method string, path string, in interface{}, out interface{}) error {
+	return c.doQuery(ctx, method, path, nil, in, out)
+}
+
+// doQuery is do with query parameters.
+func (c *Client) doQuery(ctx context.Context, method string, path string, query url.Values, in interface{}, out interface{}) error {
+	var body []byte
+	contentType := "application/json"
+	if raw, ok := in.(rawBody); ok {
+		body, contentType = raw.data, raw.contentType
+	} else if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return err
+		}
+	}
+
+	attempts := 1
+	if idempotent(method) {
+		attempts += c.retries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff(c.retryDelay, attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if c.breaker != nil && !c.breaker.allow() {
+			if err != nil {
+				return err // the circuit opened while retrying
+			}
+			return errCircuitOpen
+		}
+
+		var retry bool
+		retry, err = c.send(ctx, method, c.endpoint(path, query), contentType, body, out)
+		if c.breaker != nil {
+			// An attempt the caller gave up on says nothing about the REST API
+			if err != nil && ctx.Err() != nil {
+				c.breaker.abandon()
+			} else {
+				c.breaker.record(failed(err))
+			}
+		}
+		if !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// rawBody is a request body other than JSON, e.g. a multipart form, sent as it is.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// maxRetryDelay caps the delay between retries.
+const maxRetryDelay = 5 * time.Second
+
+// backoff returns the delay before the retry given, counting from 1. The delay doubles
+// with each retry up to maxRetryDelay and is jittered by up to half so concurrent clients
+// spread out. A base delay of 0 or less retries immediately.
+func backoff(base time.Duration, retry int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	// Doubling stops at maxRetryDelay, before the shift can overflow
+	delay := maxRetryDelay
+	if shift := retry - 1; shift < 63 && base <= maxRetryDelay>>shift {
+		delay = base << shift
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// send makes a single attempt of a request. Returns whether the request may be retried,
+// and error type.
+func (c *Client) send(ctx context.Context, method string, endpoint string, contentType string, body []byte, out interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", contentType)
+	}
+	request.Header.Set("Accept", "application/json")
+	for _, edit := range c.editors {
+		edit(request)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		// Retry on network errors unless the caller has given up
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		// Keep the access key out of error messages which end up in logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactKey(urlErr.URL)
+		}
+		return true, &networkError{err}
+	}
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, 10<<20))
+	if err != nil {
+		return true, err
+	}
+
+	if response.StatusCode >= 400 {
+		apiErr := newAPIError(response.StatusCode, data)
+		return retryable(response.StatusCode), apiErr
+	}
+
+	// Documents other than JSON, e.g. calendars, are returned as they are
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return false, nil
+	}
+
+	if out != nil && len(data) != 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// redactKey replaces the access key in the query of the URL given.
+func redactKey(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	query := u.Query()
+	if query.Has("key") {
+		query.Set("key", "REDACTED")
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// idempotent reports whether a request with the method can safely be sent more than once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether a response with the status code may succeed if retried.
+func retryable(code int) bool {
+	switch code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package coursesapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		retry    int
+		min, max time.Duration
+	}{
+		{"zero base", 0, 3, 0, 0},
+		{"negative base", -time.Second, 1, 0, 0},
+		{"first retry", 100 * time.Millisecond, 1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{"doubles", 100 * time.Millisecond, 3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"capped", time.Second, 10, maxRetryDelay / 2, maxRetryDelay},
+		{"shift overflows", time.Second, 100, maxRetryDelay / 2, maxRetryDelay},
+		{"base above cap", time.Minute, 1, maxRetryDelay / 2, maxRetryDelay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				if got := backoff(tt.base, tt.retry); got < tt.min || got > tt.max {
+					t.Fatalf("backoff(%v, %d) = %v, want between %v and %v", tt.base, tt.retry, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerAbandon(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: time.Hour}
+	b.record(true)
+	if b.allow() {
+		t.Fatal("breaker allowed a request while open")
+	}
+
+	// A trial request abandoned by its caller leaves the circuit open for another trial
+	b.openedAt = time.Now().Add(-2 * time.Hour)
+	if !b.allow() {
+		t.Fatal("breaker refused the trial request after the cooldown")
+	}
+	b.abandon()
+	if b.failures != 1 {
+		t.Errorf("failures = %d after an abandoned trial, want 1", b.failures)
+	}
+	if !b.allow() {
+		t.Error("breaker refused another trial request after an abandoned one")
+	}
+}
+
+func TestWithCircuitBreakerDisabled(t *testing.T) {
+	c := NewClient("http://localhost:5000", "", WithCircuitBreaker(0, time.Second))
+	if c.breaker != nil {
+		t.Error("WithCircuitBreaker(0, ...) set a circuit breaker")
+	}
+}
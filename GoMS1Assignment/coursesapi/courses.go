@@ -0,0 +1,145 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// coursesPath is the path of the courses resource.
+const coursesPath = "/api/v1/courses"
+
+// coursePath returns the path of the course with the course id given.
+func coursePath(id string) string {
+	return coursesPath + "/" + url.PathEscape(id)
+}
+
+// ListCourses retrieves all active courses, sorted by course ID.
+func (c *Client) ListCourses(ctx context.Context) ([]Course, error) {
+	return c.FindCourses(ctx, CourseFilter{})
+}
+
+// FindCourses retrieves the courses matching the filter, sorted by course ID.
+func (c *Client) FindCourses(ctx context.Context, filter CourseFilter) ([]Course, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"instructor": filter.Instructor,
+		"department": filter.Department,
+		"tag":        filter.Tag,
+		"status":     filter.Status,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	var courses map[string]courseInfo
+	if err := c.doQuery(ctx, http.MethodGet, coursesPath, query, nil, &courses); err != nil {
+		return nil, err
+	}
+	return sortCourses(courses), nil
+}
+
+// GetCourse retrieves the course with the course id given, or the course renamed from it.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) GetCourse(ctx context.Context, id string) (Course, error) {
+	var courses map[string]courseInfo
+	if err := c.do(ctx, http.MethodGet, coursePath(id), nil, &courses); err != nil {
+		return Course{}, err
+	}
+
+	for _, course := range sortCourses(courses) {
+		return course, nil
+	}
+	return Course{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// AddCourse adds a new draft course. The capacity defaults to unlimited and the department to
+// none if not given. Returns an error matching ErrConflict if the course id is already
+// in use, or ErrInvalid if there is no such department.
+func (c *Client) AddCourse(ctx context.Context, course Course) error {
+	return c.do(ctx, http.MethodPost, coursePath(course.ID), toCourseInfo(course), nil)
+}
+
+// UpdateCourse updates the title, and the capacity and department if given, of an existing course.
+// Returns an error matching ErrNotFound if there is no such course, or ErrInvalid if there is
+// no such department.
+func (c *Client) UpdateCourse(ctx context.Context, course Course) error {
+	return c.do(ctx, http.MethodPut, coursePath(course.ID), toCourseInfo(course), nil)
+}
+
+// DeleteCourse deletes the course with the course id given.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) DeleteCourse(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, coursePath(id), nil, nil)
+}
+
+// CloneCourse adds a draft course copied from the course with the course id given, with the
+// ID of course. The title of course, and its description, capacity and department if given,
+// override those of the course, and the related data included, such as CopyTags and
+// CopyGradebook, are copied. Returns an error matching ErrNotFound if there is no such
+// course, ErrConflict if the new course id is already in use, or ErrInvalid if the details
+// are not valid or there is no such department.
+func (c *Client) CloneCourse(ctx context.Context, id string, course Course, include ...string) error {
+	in := struct {
+		CourseID    string   `json:"CourseID"`
+		Title       *string  `json:"Title,omitempty"`
+		Description *string  `json:"Description,omitempty"`
+		Capacity    *int     `json:"Capacity,omitempty"`
+		Department  *string  `json:"Department,omitempty"`
+		Include     []string `json:"Include,omitempty"`
+	}{
+		CourseID: course.ID, Description: course.Description, Capacity: course.Capacity,
+		Department: course.Department, Include: include,
+	}
+	if course.Title != "" {
+		in.Title = &course.Title
+	}
+
+	return c.do(ctx, http.MethodPost, coursePath(id)+":clone", in, nil)
+}
+
+// RenameCourse changes the ID of the course with the course id given to newID. The records of
+// the course move to the new ID, and GetCourse with the old ID retrieves the course under its
+// new ID. Returns an error matching ErrNotFound if there is no such course, ErrConflict if
+// newID is in use by another course, or ErrInvalid if newID is not valid.
+func (c *Client) RenameCourse(ctx context.Context, id string, newID string) error {
+	in := struct {
+		CourseID string `json:"CourseID"`
+	}{newID}
+
+	return c.do(ctx, http.MethodPost, coursePath(id)+"/rename", in, nil)
+}
+
+// GetRules retrieves the validation rules the REST API checks the details of courses against.
+func (c *Client) GetRules(ctx context.Context) (Rules, error) {
+	var rules Rules
+	if err := c.do(ctx, http.MethodGet, "/api/v1/rules", nil, &rules); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// toCourseInfo converts the course to the json sent to the REST API.
+func toCourseInfo(course Course) courseInfo {
+	return courseInfo{
+		Title: course.Title, Description: course.Description, Capacity: course.Capacity, Department: course.Department,
+	}
+}
+
+// sortCourses converts the courses keyed by course ID into a slice sorted by course ID.
+func sortCourses(courses map[string]courseInfo) []Course {
+	list := make([]Course, 0, len(courses))
+	for id, info := range courses {
+		list = append(list, Course{
+			ID: id, Title: info.Title, Description: info.Description, Capacity: info.Capacity,
+			Department: info.Department, Tags: info.Tags, Status: info.Status,
+			Instructors: sortAssignments(info.Instructors),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,74 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// departmentsPath is the path of the departments resource.
+const departmentsPath = "/api/v1/departments"
+
+// departmentPath returns the path of the department with the department id given.
+func departmentPath(id string) string {
+	return departmentsPath + "/" + url.PathEscape(id)
+}
+
+// ListDepartments retrieves all departments, sorted by name.
+func (c *Client) ListDepartments(ctx context.Context) ([]Department, error) {
+	var departments map[string]departmentInfo
+	if err := c.do(ctx, http.MethodGet, departmentsPath, nil, &departments); err != nil {
+		return nil, err
+	}
+	return sortDepartments(departments), nil
+}
+
+// GetDepartment retrieves the department with the department id given.
+// Returns an error matching ErrNotFound if there is no such department.
+func (c *Client) GetDepartment(ctx context.Context, id string) (Department, error) {
+	var departments map[string]departmentInfo
+	if err := c.do(ctx, http.MethodGet, departmentPath(id), nil, &departments); err != nil {
+		return Department{}, err
+	}
+
+	for _, department := range sortDepartments(departments) {
+		return department, nil
+	}
+	return Department{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// AddDepartment adds a new department.
+// Returns an error matching ErrConflict if the department id is already in use,
+// or ErrInvalid if the details are not valid.
+func (c *Client) AddDepartment(ctx context.Context, department Department) error {
+	return c.do(ctx, http.MethodPost, departmentPath(department.ID), departmentInfo{Name: department.Name}, nil)
+}
+
+// UpdateDepartment updates the name of an existing department.
+// Returns an error matching ErrNotFound if there is no such department.
+func (c *Client) UpdateDepartment(ctx context.Context, department Department) error {
+	return c.do(ctx, http.MethodPut, departmentPath(department.ID), departmentInfo{Name: department.Name}, nil)
+}
+
+// DeleteDepartment deletes the department with the department id given. Returns an error
+// matching ErrNotFound if there is no such department, or ErrConflict while it has courses.
+func (c *Client) DeleteDepartment(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, departmentPath(id), nil, nil)
+}
+
+// sortDepartments converts the departments keyed by department ID into a slice sorted by
+// name, then department ID.
+func sortDepartments(departments map[string]departmentInfo) []Department {
+	list := make([]Department, 0, len(departments))
+	for id, info := range departments {
+		list = append(list, Department{id, info.Name, info.Courses})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,123 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// enrolmentsPath returns the path of the enrolments of the course with the course id given.
+func enrolmentsPath(courseID string) string {
+	return coursePath(courseID) + "/enrolments"
+}
+
+// enrolmentPath returns the path of the enrolment of a student in a course.
+func enrolmentPath(courseID, studentID string) string {
+	return enrolmentsPath(courseID) + "/" + url.PathEscape(studentID)
+}
+
+// ListEnrolments retrieves the enrolments of the course with the course id given in
+// every term, with enrolled students first and waitlisted students in waitlist order.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListEnrolments(ctx context.Context, courseID string) ([]Enrolment, error) {
+	var enrolments []Enrolment
+	if err := c.do(ctx, http.MethodGet, enrolmentsPath(courseID), nil, &enrolments); err != nil {
+		return nil, err
+	}
+
+	for i := range enrolments {
+		enrolments[i].CourseID = courseID
+	}
+	sortEnrolments(enrolments)
+	return enrolments, nil
+}
+
+// Enrol enrols the student in the course, or waitlists them if the course is full.
+// The returned Enrolment reports which. Returns an error matching ErrNotFound if
+// there is no such course or student, or ErrConflict if the course is not active or the
+// student is already enrolled, waitlisted or has completed the course.
+func (c *Client) Enrol(ctx context.Context, courseID, studentID string) (Enrolment, error) {
+	return c.EnrolInTerm(ctx, courseID, "", studentID)
+}
+
+// EnrolInTerm enrols the student in the offering of the course in the term given, or
+// waitlists them if the offering is full. With a termID of "" it is the same as Enrol.
+// A student may be enrolled in each term the course is offered in. Returns an error
+// matching ErrNotFound if the course is not offered in the term, and otherwise the
+// errors of Enrol.
+func (c *Client) EnrolInTerm(ctx context.Context, courseID, termID, studentID string) (Enrolment, error) {
+	in := struct {
+		StudentID string `json:"StudentID"`
+		Term      string `json:"Term,omitempty"`
+	}{studentID, termID}
+
+	var enrolments map[string]Enrolment
+	if err := c.do(ctx, http.MethodPost, enrolmentsPath(courseID), in, &enrolments); err != nil {
+		return Enrolment{}, err
+	}
+	enrolment := enrolments[studentID]
+	enrolment.CourseID, enrolment.StudentID = courseID, studentID
+	return enrolment, nil
+}
+
+// SetEnrolmentStatus withdraws a student from a course, or marks the course completed.
+// Returns an error matching ErrNotFound if the student is not enrolled in the course,
+// or ErrConflict if the enrolment cannot be changed to the status given.
+func (c *Client) SetEnrolmentStatus(ctx context.Context, courseID, studentID, status string) error {
+	return c.SetEnrolmentStatusInTerm(ctx, courseID, "", studentID, status)
+}
+
+// SetEnrolmentStatusInTerm is SetEnrolmentStatus for the enrolment of the student in the
+// offering of the course in the term given. With a termID of "" it is the same as
+// SetEnrolmentStatus.
+func (c *Client) SetEnrolmentStatusInTerm(ctx context.Context, courseID, termID, studentID, status string) error {
+	in := struct {
+		Status string `json:"Status"`
+	}{status}
+
+	query := url.Values{}
+	if termID != "" {
+		query.Set("term", termID)
+	}
+	return c.doQuery(ctx, http.MethodPut, enrolmentPath(courseID, studentID), query, in, nil)
+}
+
+// LearnerCourses retrieves the courses the student is or was enrolled in, once for each
+// term, sorted by course ID and term. Returns an error matching ErrNotFound if there is
+// no such student.
+func (c *Client) LearnerCourses(ctx context.Context, studentID string) ([]Enrolment, error) {
+	var courses []Enrolment
+	if err := c.do(ctx, http.MethodGet, "/api/v1/learners/"+url.PathEscape(studentID)+"/courses", nil, &courses); err != nil {
+		return nil, err
+	}
+
+	for i := range courses {
+		courses[i].StudentID = studentID
+	}
+	sort.SliceStable(courses, func(i, j int) bool {
+		if courses[i].CourseID != courses[j].CourseID {
+			return courses[i].CourseID < courses[j].CourseID
+		}
+		return courses[i].Term < courses[j].Term
+	})
+	return courses, nil
+}
+
+// sortEnrolments sorts enrolments with waitlisted students last in waitlist order for
+// each term, and the others by student ID and term.
+func sortEnrolments(list []Enrolment) {
+	sort.Slice(list, func(i, j int) bool {
+		wi, wj := list[i].Status == Waitlisted, list[j].Status == Waitlisted
+		if wi != wj {
+			return wj
+		}
+		if wi && list[i].Term == list[j].Term {
+			return list[i].WaitlistPosition < list[j].WaitlistPosition
+		}
+		if !wi && list[i].StudentID != list[j].StudentID {
+			return list[i].StudentID < list[j].StudentID
+		}
+		return list[i].Term < list[j].Term
+	})
+}
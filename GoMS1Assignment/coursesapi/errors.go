@@ -0,0 +1,120 @@
+package coursesapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors which the errors returned by a Client match with errors.Is.
+// An *APIError matches by its status code. REST APIs which predate the OpenAPI document
+// answered an invalid access key with 404 rather than 401, which matches ErrUnauthorized
+// rather than ErrNotFound.
+var (
+	ErrUnauthorized = errors.New("invalid access key")      // 401
+	ErrForbidden    = errors.New("admin key required")      // 403
+	ErrNotFound     = errors.New("not found")               // 404
+	ErrConflict     = errors.New("already exists")          // 409
+	ErrInvalid      = errors.New("invalid request")         // 400, 413, 415 and 422
+	ErrUnavailable  = errors.New("REST API is unavailable") // 502, 503, 504 and network errors
+)
+
+// APIError is returned when the REST API responds with an error status code.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Message    string // Message in the body of the response
+
+	// Fields are the violations of the validation rules by the details sent, keyed by
+	// field, e.g. "Title", when the REST API reports them.
+	Fields map[string]string
+}
+
+// newAPIError creates an APIError from an error response.
+func newAPIError(code int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: code,
+		Message:    strings.TrimSpace(string(body)),
+	}
+
+	var fieldErrors struct {
+		Errors map[string]string `json:"Errors"`
+	}
+	if json.Unmarshal(body, &fieldErrors) == nil {
+		apiErr.Fields = fieldErrors.Errors
+	}
+	return apiErr
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("coursesapi: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("coursesapi: %d %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether the error matches one of the sentinel errors of the package.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.legacyInvalidKey()
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound && !e.legacyInvalidKey()
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrInvalid:
+		return e.StatusCode == http.StatusBadRequest ||
+			e.StatusCode == http.StatusRequestEntityTooLarge ||
+			e.StatusCode == http.StatusUnsupportedMediaType ||
+			e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnavailable:
+		return retryable(e.StatusCode)
+	}
+	return false
+}
+
+// legacyInvalidKey reports whether the error is the 404 with which REST APIs which predate
+// the OpenAPI document answered an invalid access key.
+func (e *APIError) legacyInvalidKey() bool {
+	return e.StatusCode == http.StatusNotFound && e.Message == "401 - Invalid key"
+}
+
+// errCircuitOpen is returned without sending the request while the circuit breaker is open.
+var errCircuitOpen = &networkError{errors.New("circuit breaker is open")}
+
+// networkError wraps an error sending a request to the REST API so it matches ErrUnavailable.
+type networkError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *networkError) Error() string {
+	return "coursesapi: " + e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *networkError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether the error matches ErrUnavailable.
+func (e *networkError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// failed reports whether err from an attempt counts as a failure of the REST API
+// for the circuit breaker. Client errors such as 404 do not.
+func failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	return errors.Is(err, ErrUnavailable)
+}
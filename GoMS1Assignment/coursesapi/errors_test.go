@@ -0,0 +1,32 @@
+package coursesapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         int
+		body         string
+		unauthorized bool
+		notFound     bool
+	}{
+		{"invalid key", 401, "401 - Invalid key", true, false},
+		{"invalid key before 401", 404, "401 - Invalid key", true, false},
+		{"not found", 404, "404 - No course found", false, true},
+		{"conflict", 409, "409 - Duplicate course ID", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(tt.code, []byte(tt.body+"\n"))
+			if got := errors.Is(err, ErrUnauthorized); got != tt.unauthorized {
+				t.Errorf("errors.Is(err, ErrUnauthorized) = %v, want %v", got, tt.unauthorized)
+			}
+			if got := errors.Is(err, ErrNotFound); got != tt.notFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v", got, tt.notFound)
+			}
+		})
+	}
+}
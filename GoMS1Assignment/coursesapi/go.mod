@@ -0,0 +1,3 @@
+module GoMS1Assignment/coursesapi
+
+go 1.21
@@ -0,0 +1,193 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// assessmentsPath returns the path of the assessments of the course with the course id given.
+func assessmentsPath(courseID string) string {
+	return coursePath(courseID) + "/assessments"
+}
+
+// assessmentPath returns the path of an assessment of a course.
+func assessmentPath(courseID string, assessmentID int) string {
+	return assessmentsPath(courseID) + "/" + strconv.Itoa(assessmentID)
+}
+
+// ListAssessments retrieves the assessments of the course with the course id given, in the
+// order they were added. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListAssessments(ctx context.Context, courseID string) ([]Assessment, error) {
+	var assessments map[string]Assessment
+	if err := c.do(ctx, http.MethodGet, assessmentsPath(courseID), nil, &assessments); err != nil {
+		return nil, err
+	}
+	return sortAssessments(assessments), nil
+}
+
+// AddAssessment adds the assessment to its course and returns it with its ID set.
+// Returns an error matching ErrConflict if the name is in use or the weights of the course
+// would total more than 100, or ErrInvalid if the details are not valid.
+func (c *Client) AddAssessment(ctx context.Context, assessment Assessment) (Assessment, error) {
+	var assessments map[string]Assessment
+	if err := c.do(ctx, http.MethodPost, assessmentsPath(assessment.CourseID), assessment, &assessments); err != nil {
+		return Assessment{}, err
+	}
+
+	for _, a := range sortAssessments(assessments) {
+		return a, nil
+	}
+	return Assessment{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// UpdateAssessment updates an existing assessment. Returns an error matching ErrNotFound if
+// there is no such assessment, or ErrConflict as for AddAssessment.
+func (c *Client) UpdateAssessment(ctx context.Context, assessment Assessment) error {
+	return c.do(ctx, http.MethodPut, assessmentPath(assessment.CourseID, assessment.ID), assessment, nil)
+}
+
+// DeleteAssessment deletes the assessment of the course with its scores.
+// Returns an error matching ErrNotFound if there is no such assessment.
+func (c *Client) DeleteAssessment(ctx context.Context, courseID string, assessmentID int) error {
+	return c.do(ctx, http.MethodDelete, assessmentPath(courseID, assessmentID), nil, nil)
+}
+
+// ListScores retrieves the scores of the assessment keyed by student ID, for the enrolments
+// outside any term. Returns an error matching ErrNotFound if there is no such assessment.
+func (c *Client) ListScores(ctx context.Context, courseID string, assessmentID int) (map[string]float64, error) {
+	return c.ListScoresInTerm(ctx, courseID, "", assessmentID)
+}
+
+// ListScoresInTerm is ListScores for the enrolments in the term given. With a termID of ""
+// it is the same as ListScores.
+func (c *Client) ListScoresInTerm(ctx context.Context, courseID, termID string, assessmentID int) (map[string]float64, error) {
+	var scores map[string]float64
+	if err := c.doQuery(ctx, http.MethodGet, assessmentPath(courseID, assessmentID)+"/scores", termQuery(termID), nil, &scores); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// SetScores records the scores, keyed by student ID, of the assessment for the enrolments
+// outside any term, replacing any existing scores of those students. Returns an error
+// matching ErrNotFound if there is no such assessment, or ErrInvalid if a student is not
+// enrolled in the course or a score is not between 0 and the maximum score.
+func (c *Client) SetScores(ctx context.Context, courseID string, assessmentID int, scores map[string]float64) error {
+	return c.SetScoresInTerm(ctx, courseID, "", assessmentID, scores)
+}
+
+// SetScoresInTerm is SetScores for the enrolments in the term given, so a student who
+// retakes a course is scored again. With a termID of "" it is the same as SetScores.
+func (c *Client) SetScoresInTerm(ctx context.Context, courseID, termID string, assessmentID int, scores map[string]float64) error {
+	return c.doQuery(ctx, http.MethodPut, assessmentPath(courseID, assessmentID)+"/scores", termQuery(termID), scores, nil)
+}
+
+// DeleteScore deletes the score of the student in the assessment for their enrolment
+// outside any term. Returns an error matching ErrNotFound if the student has no score.
+func (c *Client) DeleteScore(ctx context.Context, courseID string, assessmentID int, studentID string) error {
+	return c.DeleteScoreInTerm(ctx, courseID, "", assessmentID, studentID)
+}
+
+// DeleteScoreInTerm is DeleteScore for the enrolment of the student in the term given.
+// With a termID of "" it is the same as DeleteScore.
+func (c *Client) DeleteScoreInTerm(ctx context.Context, courseID, termID string, assessmentID int, studentID string) error {
+	path := assessmentPath(courseID, assessmentID) + "/scores/" + url.PathEscape(studentID)
+	return c.doQuery(ctx, http.MethodDelete, path, termQuery(termID), nil, nil)
+}
+
+// GradeScale retrieves the minimum percentage of each letter grade of the course.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) GradeScale(ctx context.Context, courseID string) (map[string]float64, error) {
+	var scale map[string]float64
+	if err := c.do(ctx, http.MethodGet, coursePath(courseID)+"/gradescale", nil, &scale); err != nil {
+		return nil, err
+	}
+	return scale, nil
+}
+
+// SetGradeScale replaces the letter-grade boundaries of the course. An empty scale
+// restores the default boundaries. Returns an error matching ErrNotFound if there is no
+// such course, or ErrInvalid if no grade has a boundary of 0.
+func (c *Client) SetGradeScale(ctx context.Context, courseID string, scale map[string]float64) error {
+	if scale == nil {
+		scale = map[string]float64{}
+	}
+	return c.do(ctx, http.MethodPut, coursePath(courseID)+"/gradescale", scale, nil)
+}
+
+// Gradebook retrieves the assessments of the course and the scores and weighted final
+// grades of its students enrolled outside any term. Returns an error matching ErrNotFound
+// if there is no such course.
+func (c *Client) Gradebook(ctx context.Context, courseID string) (Gradebook, error) {
+	return c.GradebookInTerm(ctx, courseID, "")
+}
+
+// GradebookInTerm is Gradebook for the students enrolled in the course in the term given.
+// With a termID of "" it is the same as Gradebook.
+func (c *Client) GradebookInTerm(ctx context.Context, courseID, termID string) (Gradebook, error) {
+	var info gradebookInfo
+	if err := c.doQuery(ctx, http.MethodGet, coursePath(courseID)+"/gradebook", termQuery(termID), nil, &info); err != nil {
+		return Gradebook{}, err
+	}
+
+	book := Gradebook{
+		Assessments: sortAssessments(info.Assessments),
+		GradeScale:  info.GradeScale,
+		Grades:      make([]Grade, 0, len(info.Students)),
+	}
+	for studentID, g := range info.Students {
+		scores := make(map[int]float64, len(g.Scores))
+		for id, score := range g.Scores {
+			assessmentID, _ := strconv.Atoi(id)
+			scores[assessmentID] = score
+		}
+		book.Grades = append(book.Grades, Grade{studentID, g.Name, scores, g.Percent, g.Grade})
+	}
+	sort.Slice(book.Grades, func(i, j int) bool {
+		return book.Grades[i].StudentID < book.Grades[j].StudentID
+	})
+	return book, nil
+}
+
+// GradebookCSV exports the gradebook of the course as CSV, with a row per student.
+func (c *Client) GradebookCSV(ctx context.Context, courseID string) ([]byte, error) {
+	return c.GradebookCSVInTerm(ctx, courseID, "")
+}
+
+// GradebookCSVInTerm is GradebookCSV for the students enrolled in the course in the term
+// given. With a termID of "" it is the same as GradebookCSV.
+func (c *Client) GradebookCSVInTerm(ctx context.Context, courseID, termID string) ([]byte, error) {
+	query := termQuery(termID)
+	query.Set("format", "csv")
+
+	var data []byte
+	err := c.doQuery(ctx, http.MethodGet, coursePath(courseID)+"/gradebook", query, nil, &data)
+	return data, err
+}
+
+// termQuery returns the query selecting the enrolments in the term given, or those outside
+// any term if termID is "".
+func termQuery(termID string) url.Values {
+	query := url.Values{}
+	if termID != "" {
+		query.Set("term", termID)
+	}
+	return query
+}
+
+// sortAssessments converts the assessments keyed by assessment ID into a slice sorted by ID,
+// the order they were added.
+func sortAssessments(assessments map[string]Assessment) []Assessment {
+	list := make([]Assessment, 0, len(assessments))
+	for id, a := range assessments {
+		a.ID, _ = strconv.Atoi(id)
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
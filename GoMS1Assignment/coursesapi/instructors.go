@@ -0,0 +1,112 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// instructorsPath is the path of the instructors resource.
+const instructorsPath = "/api/v1/instructors"
+
+// instructorPath returns the path of the instructor with the instructor id given.
+func instructorPath(id string) string {
+	return instructorsPath + "/" + url.PathEscape(id)
+}
+
+// courseInstructorPath returns the path of the assignment of an instructor to a course.
+func courseInstructorPath(courseID, instructorID string) string {
+	return coursePath(courseID) + "/instructors/" + url.PathEscape(instructorID)
+}
+
+// ListInstructors retrieves all instructors, sorted by instructor ID.
+func (c *Client) ListInstructors(ctx context.Context) ([]Instructor, error) {
+	var instructors map[string]instructorInfo
+	if err := c.do(ctx, http.MethodGet, instructorsPath, nil, &instructors); err != nil {
+		return nil, err
+	}
+	return sortInstructors(instructors), nil
+}
+
+// GetInstructor retrieves the instructor with the instructor id given.
+// Returns an error matching ErrNotFound if there is no such instructor.
+func (c *Client) GetInstructor(ctx context.Context, id string) (Instructor, error) {
+	var instructors map[string]instructorInfo
+	if err := c.do(ctx, http.MethodGet, instructorPath(id), nil, &instructors); err != nil {
+		return Instructor{}, err
+	}
+
+	for _, instructor := range sortInstructors(instructors) {
+		return instructor, nil
+	}
+	return Instructor{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// AddInstructor adds a new instructor.
+// Returns an error matching ErrConflict if the instructor id is already in use,
+// or ErrInvalid if the details are not valid.
+func (c *Client) AddInstructor(ctx context.Context, instructor Instructor) error {
+	return c.do(ctx, http.MethodPost, instructorPath(instructor.ID), instructorInfo{instructor.Name, instructor.Email}, nil)
+}
+
+// UpdateInstructor updates the details of an existing instructor.
+// Returns an error matching ErrNotFound if there is no such instructor.
+func (c *Client) UpdateInstructor(ctx context.Context, instructor Instructor) error {
+	return c.do(ctx, http.MethodPut, instructorPath(instructor.ID), instructorInfo{instructor.Name, instructor.Email}, nil)
+}
+
+// DeleteInstructor deletes the instructor with the instructor id given, and removes
+// them from their courses. Returns an error matching ErrNotFound if there is no such instructor.
+func (c *Client) DeleteInstructor(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, instructorPath(id), nil, nil)
+}
+
+// AssignInstructor assigns the instructor to the course with the role given, Lead or
+// Assistant, or changes their role if already assigned. Returns an error matching
+// ErrNotFound if there is no such course or instructor, or ErrInvalid if the role is not valid.
+func (c *Client) AssignInstructor(ctx context.Context, courseID, instructorID, role string) error {
+	in := struct {
+		Role string `json:"Role"`
+	}{role}
+
+	return c.do(ctx, http.MethodPut, courseInstructorPath(courseID, instructorID), in, nil)
+}
+
+// UnassignInstructor removes the instructor from the course.
+// Returns an error matching ErrNotFound if the instructor is not assigned to the course.
+func (c *Client) UnassignInstructor(ctx context.Context, courseID, instructorID string) error {
+	return c.do(ctx, http.MethodDelete, courseInstructorPath(courseID, instructorID), nil, nil)
+}
+
+// sortInstructors converts the instructors keyed by instructor ID into a slice sorted by instructor ID.
+func sortInstructors(instructors map[string]instructorInfo) []Instructor {
+	list := make([]Instructor, 0, len(instructors))
+	for id, info := range instructors {
+		list = append(list, Instructor{id, info.Name, info.Email})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// sortAssignments converts the assignments keyed by instructor ID into a slice with
+// the lead instructors first, then sorted by instructor ID.
+func sortAssignments(assignments map[string]assignmentInfo) []Assignment {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	list := make([]Assignment, 0, len(assignments))
+	for id, info := range assignments {
+		list = append(list, Assignment{id, info.Name, info.Role})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].Role == Lead) != (list[j].Role == Lead) {
+			return list[i].Role == Lead
+		}
+		return list[i].InstructorID < list[j].InstructorID
+	})
+	return list
+}
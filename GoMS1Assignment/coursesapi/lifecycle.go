@@ -0,0 +1,44 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+)
+
+// CourseHistory retrieves the changes in the status of the course with the course id given,
+// oldest first. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) CourseHistory(ctx context.Context, courseID string) ([]StatusChange, error) {
+	var history []StatusChange
+	if err := c.do(ctx, http.MethodGet, coursePath(courseID)+"/status", nil, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// SetCourseStatus changes the status of the course: Pending submits a draft for approval,
+// Draft withdraws it from review or reopens a retired course, and Retired retires an active
+// course. Returns an error matching ErrNotFound if there is no such course, ErrConflict if
+// the course cannot be changed to the status from its current status, or ErrInvalid if the
+// comment is longer than 255 characters.
+func (c *Client) SetCourseStatus(ctx context.Context, courseID, status, comment string) error {
+	in := struct {
+		Status  string `json:"Status"`
+		Comment string `json:"Comment"`
+	}{status, comment}
+
+	return c.do(ctx, http.MethodPut, coursePath(courseID)+"/status", in, nil)
+}
+
+// ReviewCourse approves or rejects a course pending approval, with a comment for its author
+// which is required to reject it. An approved course becomes active and a rejected course
+// returns to draft. Returns an error matching ErrForbidden unless the client has an admin
+// key, ErrNotFound if there is no such course, ErrConflict if the course is not pending
+// approval, or ErrInvalid if the decision or comment is not valid.
+func (c *Client) ReviewCourse(ctx context.Context, courseID, decision, comment string) error {
+	in := struct {
+		Decision string `json:"Decision"`
+		Comment  string `json:"Comment"`
+	}{decision, comment}
+
+	return c.do(ctx, http.MethodPost, coursePath(courseID)+"/review", in, nil)
+}
@@ -0,0 +1,384 @@
+package coursesapi
+
+// Course is a course offered by GoSchool.
+type Course struct {
+	ID    string `json:"ID"`
+	Title string `json:"Title"`
+
+	// Description is the description of the course in Markdown, with "" for none.
+	// A nil Description keeps the existing description when updating a course.
+	Description *string `json:"Description,omitempty"`
+
+	// Capacity is the maximum number of students enrolled, with 0 for unlimited.
+	// A nil Capacity keeps the existing capacity when updating a course.
+	Capacity *int `json:"Capacity,omitempty"`
+
+	// Instructors are the instructors assigned to the course, with the lead first.
+	// They are not sent when adding or updating a course; see AssignInstructor.
+	Instructors []Assignment `json:"Instructors,omitempty"`
+
+	// Department is the ID of the department of the course, with "" for none.
+	// A nil Department keeps the existing department when updating a course.
+	Department *string `json:"Department,omitempty"`
+
+	// Tags are the tags of the course in alphabetical order. They are not sent when
+	// adding or updating a course; see SetCourseTags.
+	Tags []string `json:"Tags,omitempty"`
+
+	// Status is the status of the course in its lifecycle. It is not sent when adding or
+	// updating a course; see SetCourseStatus and ReviewCourse.
+	Status string `json:"Status,omitempty"`
+}
+
+// Course statuses. New courses are drafts, which are submitted for approval and become
+// active once approved by an admin. Only active courses are open for enrolment.
+const (
+	Draft   = "draft"
+	Pending = "pending"
+	Active  = "active"
+	Retired = "retired"
+)
+
+// AllStatuses selects courses in any status in a CourseFilter.
+const AllStatuses = "all"
+
+// Review decisions on a course pending approval.
+const (
+	Approve = "approve"
+	Reject  = "reject"
+)
+
+// Related data of a course which CloneCourse may copy to the new course. The gradebook is
+// the assessments and grade scale of the course, without scores.
+const (
+	CopyTags          = "tags"
+	CopyInstructors   = "instructors"
+	CopyPrerequisites = "prerequisites"
+	CopyGradebook     = "gradebook"
+	CopyAttachments   = "attachments"
+)
+
+// StatusChange is a change in the status of a course.
+type StatusChange struct {
+	From      string `json:"From"`
+	To        string `json:"To"`
+	Comment   string `json:"Comment,omitempty"`
+	ChangedBy string `json:"ChangedBy,omitempty"` // label of the access key used
+	Date      string `json:"Date"`                // YYYY-MM-DD HH:MM:SS
+}
+
+// CourseFilter selects the courses retrieved by FindCourses. Empty fields match all courses,
+// except Status, which matches active courses when empty.
+type CourseFilter struct {
+	Instructor string // ID of an instructor assigned to the course
+	Department string // ID of the department of the course
+	Tag        string // tag of the course, matched ignoring case
+	Status     string // status of the course, or AllStatuses
+}
+
+// courseInfo struct for the json sent to and received from the REST API,
+// which keys courses by their course ID.
+type courseInfo struct {
+	Title       string                    `json:"Title"`
+	Description *string                   `json:"Description,omitempty"`
+	Capacity    *int                      `json:"Capacity,omitempty"`
+	Instructors map[string]assignmentInfo `json:"Instructors,omitempty"`
+	Department  *string                   `json:"Department,omitempty"`
+	Tags        []string                  `json:"Tags,omitempty"`
+	Status      string                    `json:"Status,omitempty"`
+}
+
+// Days of the week of a session.
+var Days = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// Session is a weekly class session of a course.
+type Session struct {
+	ID       int    `json:"-"`
+	CourseID string `json:"CourseID"`
+	Term     string `json:"Term"`
+	Day      string `json:"Day"`   // one of Days
+	Start    string `json:"Start"` // HH:MM
+	End      string `json:"End"`   // HH:MM
+	Room     string `json:"Room"`
+}
+
+// SessionFilter selects the sessions retrieved by Timetable. Empty fields match all sessions.
+type SessionFilter struct {
+	Term       string
+	Room       string
+	Instructor string // ID of an instructor assigned to the course
+	Student    string // ID of a student enrolled in the course
+}
+
+// PlanStep is a course in a study plan. Courses in the same stage may be taken together.
+type PlanStep struct {
+	CourseID string `json:"CourseID"`
+	Title    string `json:"Title"`
+	Stage    int    `json:"Stage"` // 1 for courses without prerequisites
+}
+
+// Eligibility reports the courses a learner may take given the courses they have completed.
+type Eligibility struct {
+	Eligible   []string            `json:"Eligible"`
+	Ineligible map[string][]string `json:"Ineligible"` // the missing prerequisites keyed by course ID
+}
+
+// Instructor roles.
+const (
+	Lead      = "lead"
+	Assistant = "assistant"
+)
+
+// Instructor is an instructor who teaches GoSchool courses.
+type Instructor struct {
+	ID    string `json:"ID"`
+	Name  string `json:"Name"`
+	Email string `json:"Email"`
+}
+
+// instructorInfo struct for the json sent to and received from the REST API,
+// which keys instructors by their instructor ID.
+type instructorInfo struct {
+	Name  string `json:"Name"`
+	Email string `json:"Email"`
+}
+
+// Assignment is an instructor assigned to a course.
+type Assignment struct {
+	InstructorID string `json:"InstructorID"`
+	Name         string `json:"Name"`
+	Role         string `json:"Role"` // lead or assistant
+}
+
+// assignmentInfo struct for the json received from the REST API,
+// which keys assignments by instructor ID.
+type assignmentInfo struct {
+	Name string `json:"Name"`
+	Role string `json:"Role"`
+}
+
+// Student is a student of GoSchool.
+type Student struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Email       string `json:"Email"`
+	DateOfBirth string `json:"DateOfBirth"` // YYYY-MM-DD
+	Status      string `json:"Status"`      // active, suspended, graduated or withdrawn
+}
+
+// studentInfo struct for the json sent to and received from the REST API,
+// which keys students by their student ID.
+type studentInfo struct {
+	Name        string `json:"Name"`
+	Email       string `json:"Email"`
+	DateOfBirth string `json:"DateOfBirth"`
+	Status      string `json:"Status"`
+}
+
+// Enrolment statuses. Students are waitlisted once a course reaches its capacity
+// and enrolled in turn as seats are given up.
+const (
+	Enrolled   = "enrolled"
+	Waitlisted = "waitlisted"
+	Withdrawn  = "withdrawn"
+	Completed  = "completed"
+)
+
+// Enrolment is the enrolment of a student in a course.
+type Enrolment struct {
+	CourseID         string `json:"CourseID"`
+	StudentID        string `json:"StudentID"`
+	Name             string `json:"Name,omitempty"`  // name of the student, for the enrolments of a course
+	Title            string `json:"Title,omitempty"` // title of the course, for the courses of a learner
+	Term             string `json:"Term,omitempty"`  // term ID, if enrolled in the offering of a term
+	EnrolledDate     string `json:"EnrolledDate"`    // YYYY-MM-DD
+	Status           string `json:"Status"`
+	WaitlistPosition int    `json:"WaitlistPosition,omitempty"` // from 1, while waitlisted
+}
+
+// Term is an academic term in which courses are offered.
+type Term struct {
+	ID        string `json:"ID"`
+	Name      string `json:"Name"`
+	StartDate string `json:"StartDate"` // YYYY-MM-DD
+	EndDate   string `json:"EndDate"`   // YYYY-MM-DD
+}
+
+// termInfo struct for the json sent to and received from the REST API,
+// which keys terms by their term ID.
+type termInfo struct {
+	Name      string `json:"Name"`
+	StartDate string `json:"StartDate"`
+	EndDate   string `json:"EndDate"`
+}
+
+// Department is a department which courses belong to.
+type Department struct {
+	ID      string `json:"ID"`
+	Name    string `json:"Name"`
+	Courses int    `json:"Courses"` // number of courses, set by the REST API
+}
+
+// departmentInfo struct for the json sent to and received from the REST API,
+// which keys departments by their department ID.
+type departmentInfo struct {
+	Name    string `json:"Name"`
+	Courses int    `json:"Courses,omitempty"`
+}
+
+// Rules are the validation rules the details of courses must meet, as configured for the
+// REST API. Lengths are counted in characters.
+type Rules struct {
+	CourseIDPattern      string `json:"CourseIDPattern"` // regular expression matched against the whole course ID
+	CourseIDAllowed      string `json:"CourseIDAllowed"` // describes what the pattern allows, if not configured
+	CourseIDMaxLength    int    `json:"CourseIDMaxLength"`
+	TitleMaxLength       int    `json:"TitleMaxLength"`
+	DescriptionMaxLength int    `json:"DescriptionMaxLength"`
+	MaxTags              int    `json:"MaxTags"`
+	TagMaxLength         int    `json:"TagMaxLength"`
+}
+
+// TagCount is a tag with the number of courses which have it.
+type TagCount struct {
+	Tag     string `json:"Tag"`
+	Courses int    `json:"Courses"`
+}
+
+// Offering is a course offered in a term, with the capacity of the course in that term.
+type Offering struct {
+	CourseID   string `json:"CourseID"`
+	TermID     string `json:"TermID"`
+	Title      string `json:"Title"` // title of the course
+	Term       string `json:"Term"`  // name of the term
+	Capacity   int    `json:"Capacity"`
+	Enrolled   int    `json:"Enrolled"`
+	Waitlisted int    `json:"Waitlisted"`
+}
+
+// offeringInfo struct for the json received from the REST API, which keys the
+// offerings of a term by course ID and the offerings of a course by term ID.
+type offeringInfo struct {
+	Title      string `json:"Title"`
+	Term       string `json:"Term"`
+	Capacity   int    `json:"Capacity"`
+	Enrolled   int    `json:"Enrolled"`
+	Waitlisted int    `json:"Waitlisted"`
+}
+
+// Assessment is an assessment of a course, weighted towards the final grade.
+type Assessment struct {
+	ID       int     `json:"-"`
+	CourseID string  `json:"CourseID"`
+	Name     string  `json:"Name"`
+	Weight   float64 `json:"Weight"`   // percentage of the final grade
+	MaxScore float64 `json:"MaxScore"` // score for full marks
+}
+
+// Gradebook is the gradebook of a course.
+type Gradebook struct {
+	Assessments []Assessment       // in the order they were added
+	GradeScale  map[string]float64 // minimum percentage keyed by letter grade
+	Grades      []Grade            // sorted by student ID
+}
+
+// Grade is the scores and weighted final grade of a student in a course.
+type Grade struct {
+	StudentID string
+	Name      string
+	Scores    map[int]float64 // keyed by assessment ID; missing scores count as 0
+	Percent   float64
+	Grade     string
+}
+
+// gradebookInfo struct for the json received from the REST API.
+type gradebookInfo struct {
+	Assessments map[string]Assessment `json:"Assessments"`
+	GradeScale  map[string]float64    `json:"GradeScale"`
+	Students    map[string]gradeInfo  `json:"Students"`
+}
+
+// gradeInfo struct for the json received from the REST API, which keys the grades of
+// students by student ID and their scores by assessment ID.
+type gradeInfo struct {
+	Name    string             `json:"Name"`
+	Scores  map[string]float64 `json:"Scores"`
+	Percent float64            `json:"Percent"`
+	Grade   string             `json:"Grade"`
+}
+
+// Transcript is the transcript of a student: the courses they completed by term.
+type Transcript struct {
+	StudentID string       `json:"StudentID"`
+	Name      string       `json:"Name"`
+	Terms     []TermRecord `json:"Terms"`   // ordered by start date
+	Credits   float64      `json:"Credits"` // credits earned in all terms
+	GPA       float64      `json:"GPA"`     // cumulative GPA
+}
+
+// TermRecord is the courses a student completed in a term.
+type TermRecord struct {
+	TermID    string       `json:"TermID"`
+	Name      string       `json:"Name"`
+	StartDate string       `json:"StartDate"` // YYYY-MM-DD
+	EndDate   string       `json:"EndDate"`   // YYYY-MM-DD
+	Courses   []Completion `json:"Courses"`   // ordered by course ID
+	Credits   float64      `json:"Credits"`   // credits earned in the term
+	GPA       float64      `json:"GPA"`       // term GPA
+}
+
+// Completion is a course completed by a student in a term.
+type Completion struct {
+	TermID        string  `json:"TermID"`
+	CourseID      string  `json:"CourseID"`
+	Title         string  `json:"Title,omitempty"` // title of the course
+	Grade         string  `json:"Grade"`           // A+ to F, or P for a pass
+	Credits       float64 `json:"Credits"`
+	CompletedDate string  `json:"CompletedDate,omitempty"` // YYYY-MM-DD
+}
+
+// Statuses of the attendance of a student at a class.
+const (
+	Present = "present"
+	Absent  = "absent"
+	Late    = "late"
+	Excused = "excused"
+)
+
+// Class is a dated class of a course, optionally held in one of its weekly sessions.
+type Class struct {
+	ID        int    `json:"-"`
+	CourseID  string `json:"CourseID"`
+	SessionID int    `json:"SessionID,omitempty"` // the day of the session must be the day of the date
+	Date      string `json:"Date"`                // YYYY-MM-DD
+	Topic     string `json:"Topic"`
+}
+
+// Attachment is a file attached to a course, such as its syllabus.
+type Attachment struct {
+	ID          int    `json:"-"`
+	CourseID    string `json:"CourseID"`
+	FileName    string `json:"FileName"`
+	ContentType string `json:"ContentType"`
+	Size        int64  `json:"Size"`     // bytes
+	Uploaded    string `json:"Uploaded"` // YYYY-MM-DD HH:MM:SS
+}
+
+// AttendanceMark is the attendance of a student at a class.
+type AttendanceMark struct {
+	StudentID string `json:"-"`
+	Name      string `json:"Name"`
+	Status    string `json:"Status"` // empty if not marked
+}
+
+// AttendanceReport is the attendance of a student at the classes of a course.
+type AttendanceReport struct {
+	CourseID  string  `json:"-"`
+	StudentID string  `json:"-"`
+	Name      string  `json:"Name"` // name of the student
+	Present   int     `json:"Present"`
+	Absent    int     `json:"Absent"`
+	Late      int     `json:"Late"`
+	Excused   int     `json:"Excused"`
+	Percent   float64 `json:"Percent"` // classes attended, present or late, of those not excused
+	Alert     bool    `json:"Alert"`   // whether the percentage is below the threshold
+}
@@ -0,0 +1,63 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// prerequisitePath returns the path of a prerequisite of a course.
+func prerequisitePath(courseID, prerequisiteID string) string {
+	return coursePath(courseID) + "/prerequisites/" + url.PathEscape(prerequisiteID)
+}
+
+// ListPrerequisites retrieves the courses which must be completed before the course
+// with the course id given, sorted by course ID.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListPrerequisites(ctx context.Context, courseID string) ([]Course, error) {
+	var courses map[string]courseInfo
+	if err := c.do(ctx, http.MethodGet, coursePath(courseID)+"/prerequisites", nil, &courses); err != nil {
+		return nil, err
+	}
+	return sortCourses(courses), nil
+}
+
+// AddPrerequisite records that the course requires the prerequisite to be completed first.
+// Returns an error matching ErrNotFound if either course does not exist, or ErrConflict if
+// the prerequisite already requires the course, directly or indirectly.
+func (c *Client) AddPrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	return c.do(ctx, http.MethodPut, prerequisitePath(courseID, prerequisiteID), nil, nil)
+}
+
+// RemovePrerequisite removes the prerequisite from the course.
+// Returns an error matching ErrNotFound if it is not a prerequisite of the course.
+func (c *Client) RemovePrerequisite(ctx context.Context, courseID, prerequisiteID string) error {
+	return c.do(ctx, http.MethodDelete, prerequisitePath(courseID, prerequisiteID), nil, nil)
+}
+
+// StudyPlan retrieves the course with the course id given and every course it requires,
+// directly or indirectly, in an order they can be taken.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) StudyPlan(ctx context.Context, courseID string) ([]PlanStep, error) {
+	var plan []PlanStep
+	if err := c.do(ctx, http.MethodGet, coursePath(courseID)+"/studyplan", nil, &plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Eligibility reports which courses a learner who has completed the courses given may take,
+// and the prerequisites missing for the others.
+func (c *Client) Eligibility(ctx context.Context, completed []string) (Eligibility, error) {
+	in := struct {
+		Completed []string `json:"Completed"`
+	}{completed}
+
+	var out Eligibility
+	if err := c.do(ctx, http.MethodPost, "/api/v1/eligibility", in, &out); err != nil {
+		return Eligibility{}, err
+	}
+	sort.Strings(out.Eligible)
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// sessionsPath returns the path of the sessions of the course with the course id given.
+func sessionsPath(courseID string) string {
+	return coursePath(courseID) + "/sessions"
+}
+
+// ListSessions retrieves the weekly sessions of the course with the course id given, in
+// weekly order. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListSessions(ctx context.Context, courseID string) ([]Session, error) {
+	var sessions map[string]Session
+	if err := c.do(ctx, http.MethodGet, sessionsPath(courseID), nil, &sessions); err != nil {
+		return nil, err
+	}
+	return sortSessions(sessions), nil
+}
+
+// Timetable retrieves the sessions of all courses matching the filter, in weekly order.
+func (c *Client) Timetable(ctx context.Context, filter SessionFilter) ([]Session, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"term":       filter.Term,
+		"room":       filter.Room,
+		"instructor": filter.Instructor,
+		"student":    filter.Student,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	var sessions map[string]Session
+	if err := c.doQuery(ctx, http.MethodGet, "/api/v1/sessions", query, nil, &sessions); err != nil {
+		return nil, err
+	}
+	return sortSessions(sessions), nil
+}
+
+// AddSession adds the weekly session to its course and returns it with its ID set.
+// Returns an error matching ErrNotFound if the course is not offered in the term of the
+// session, ErrConflict if the room, or an instructor of the course, is already booked at
+// the time, or ErrInvalid if the details are not valid.
+func (c *Client) AddSession(ctx context.Context, session Session) (Session, error) {
+	var sessions map[string]Session
+	if err := c.do(ctx, http.MethodPost, sessionsPath(session.CourseID), session, &sessions); err != nil {
+		return Session{}, err
+	}
+
+	for _, s := range sortSessions(sessions) {
+		return s, nil
+	}
+	return Session{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// UpdateSession updates an existing session. Returns an error matching ErrNotFound if
+// there is no such session, or ErrConflict as for AddSession.
+func (c *Client) UpdateSession(ctx context.Context, session Session) error {
+	return c.do(ctx, http.MethodPut, sessionsPath(session.CourseID)+"/"+strconv.Itoa(session.ID), session, nil)
+}
+
+// DeleteSession deletes the session of the course.
+// Returns an error matching ErrNotFound if there is no such session.
+func (c *Client) DeleteSession(ctx context.Context, courseID string, sessionID int) error {
+	return c.do(ctx, http.MethodDelete, sessionsPath(courseID)+"/"+strconv.Itoa(sessionID), nil, nil)
+}
+
+// CourseCalendar exports the sessions of the course as an iCalendar document of weekly
+// events within the dates of their terms, limited to the dates given. Zero dates are
+// not applied.
+func (c *Client) CourseCalendar(ctx context.Context, courseID string, from, until time.Time) ([]byte, error) {
+	var ics []byte
+	err := c.doQuery(ctx, http.MethodGet, coursePath(courseID)+"/calendar.ics", calendarQuery(from, until), nil, &ics)
+	return ics, err
+}
+
+// LearnerCalendar exports the sessions of the courses the student is enrolled in as an
+// iCalendar document, as for CourseCalendar.
+func (c *Client) LearnerCalendar(ctx context.Context, studentID string, from, until time.Time) ([]byte, error) {
+	var ics []byte
+	path := "/api/v1/learners/" + url.PathEscape(studentID) + "/calendar.ics"
+	err := c.doQuery(ctx, http.MethodGet, path, calendarQuery(from, until), nil, &ics)
+	return ics, err
+}
+
+// calendarQuery returns the query parameters for the dates of a calendar.
+func calendarQuery(from, until time.Time) url.Values {
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", from.Format("2006-01-02"))
+	}
+	if !until.IsZero() {
+		query.Set("until", until.Format("2006-01-02"))
+	}
+	return query
+}
+
+// sortSessions converts the sessions keyed by session ID into a slice sorted by day,
+// start time and room.
+func sortSessions(sessions map[string]Session) []Session {
+	list := make([]Session, 0, len(sessions))
+	for id, s := range sessions {
+		s.ID, _ = strconv.Atoi(id)
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Day != b.Day {
+			return dayIndex[a.Day] < dayIndex[b.Day]
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.Room < b.Room
+	})
+	return list
+}
+
+// dayIndex orders the days of a session from Monday.
+var dayIndex = map[string]int{"Mon": 0, "Tue": 1, "Wed": 2, "Thu": 3, "Fri": 4, "Sat": 5, "Sun": 6}
@@ -0,0 +1,75 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// studentsPath is the path of the students resource.
+const studentsPath = "/api/v1/students"
+
+// studentPath returns the path of the student with the student id given.
+func studentPath(id string) string {
+	return studentsPath + "/" + url.PathEscape(id)
+}
+
+// ListStudents retrieves all students, sorted by student ID.
+func (c *Client) ListStudents(ctx context.Context) ([]Student, error) {
+	var students map[string]studentInfo
+	if err := c.do(ctx, http.MethodGet, studentsPath, nil, &students); err != nil {
+		return nil, err
+	}
+	return sortStudents(students), nil
+}
+
+// GetStudent retrieves the student with the student id given.
+// Returns an error matching ErrNotFound if there is no such student.
+func (c *Client) GetStudent(ctx context.Context, id string) (Student, error) {
+	var students map[string]studentInfo
+	if err := c.do(ctx, http.MethodGet, studentPath(id), nil, &students); err != nil {
+		return Student{}, err
+	}
+
+	for _, student := range sortStudents(students) {
+		return student, nil
+	}
+	return Student{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// AddStudent adds a new student. The status defaults to active if not given.
+// Returns an error matching ErrConflict if the student id is already in use,
+// or ErrInvalid if the details are not valid.
+func (c *Client) AddStudent(ctx context.Context, student Student) error {
+	return c.do(ctx, http.MethodPost, studentPath(student.ID), toStudentInfo(student), nil)
+}
+
+// UpdateStudent updates the details of an existing student.
+// Returns an error matching ErrNotFound if there is no such student.
+func (c *Client) UpdateStudent(ctx context.Context, student Student) error {
+	return c.do(ctx, http.MethodPut, studentPath(student.ID), toStudentInfo(student), nil)
+}
+
+// DeleteStudent deletes the student with the student id given.
+// Returns an error matching ErrNotFound if there is no such student.
+func (c *Client) DeleteStudent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, studentPath(id), nil, nil)
+}
+
+// toStudentInfo converts a Student to the json sent to the REST API.
+func toStudentInfo(s Student) studentInfo {
+	return studentInfo{s.Name, s.Email, s.DateOfBirth, s.Status}
+}
+
+// sortStudents converts the students keyed by student ID into a slice sorted by student ID.
+func sortStudents(students map[string]studentInfo) []Student {
+	list := make([]Student, 0, len(students))
+	for id, info := range students {
+		list = append(list, Student{id, info.Name, info.Email, info.DateOfBirth, info.Status})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,85 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// tagPath returns the path of the tag given.
+func tagPath(tag string) string {
+	return "/api/v1/tags/" + url.PathEscape(tag)
+}
+
+// courseTagsPath returns the path of the tags of the course with the course id given.
+func courseTagsPath(courseID string) string {
+	return coursePath(courseID) + "/tags"
+}
+
+// ListTags retrieves the tags in use with the number of courses which have each, sorted by tag.
+func (c *Client) ListTags(ctx context.Context) ([]TagCount, error) {
+	var tags map[string]int
+	if err := c.do(ctx, http.MethodGet, "/api/v1/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+
+	list := make([]TagCount, 0, len(tags))
+	for tag, courses := range tags {
+		list = append(list, TagCount{tag, courses})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Tag < list[j].Tag
+	})
+	return list, nil
+}
+
+// RenameTag renames the tag on every course which has it, merging it into the new tag on
+// courses which already have that. Returns an error matching ErrNotFound if no course has
+// the tag, or ErrInvalid if the new tag is not valid.
+func (c *Client) RenameTag(ctx context.Context, tag, newTag string) error {
+	in := struct {
+		Name string `json:"Name"`
+	}{newTag}
+
+	return c.do(ctx, http.MethodPut, tagPath(tag), in, nil)
+}
+
+// DeleteTag removes the tag from every course.
+// Returns an error matching ErrNotFound if no course has the tag.
+func (c *Client) DeleteTag(ctx context.Context, tag string) error {
+	return c.do(ctx, http.MethodDelete, tagPath(tag), nil, nil)
+}
+
+// CourseTags retrieves the tags of the course with the course id given, in alphabetical
+// order. Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) CourseTags(ctx context.Context, courseID string) ([]string, error) {
+	var tags []string
+	if err := c.do(ctx, http.MethodGet, courseTagsPath(courseID), nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// SetCourseTags replaces the tags of the course. Tags are stored in lower case without
+// duplicates. Returns an error matching ErrNotFound if there is no such course, or
+// ErrInvalid if a tag is not valid or there are more than 20.
+func (c *Client) SetCourseTags(ctx context.Context, courseID string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	return c.do(ctx, http.MethodPut, courseTagsPath(courseID), tags, nil)
+}
+
+// AddCourseTag tags the course, doing nothing if the course already has the tag.
+// Returns an error matching ErrNotFound if there is no such course, ErrConflict if the
+// course already has 20 tags, or ErrInvalid if the tag is not valid.
+func (c *Client) AddCourseTag(ctx context.Context, courseID, tag string) error {
+	return c.do(ctx, http.MethodPut, courseTagsPath(courseID)+"/"+url.PathEscape(tag), nil, nil)
+}
+
+// RemoveCourseTag removes the tag from the course.
+// Returns an error matching ErrNotFound if there is no such course, or it lacks the tag.
+func (c *Client) RemoveCourseTag(ctx context.Context, courseID, tag string) error {
+	return c.do(ctx, http.MethodDelete, courseTagsPath(courseID)+"/"+url.PathEscape(tag), nil, nil)
+}
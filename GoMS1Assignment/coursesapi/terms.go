@@ -0,0 +1,138 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+)
+
+// termsPath is the path of the terms resource.
+const termsPath = "/api/v1/terms"
+
+// termPath returns the path of the term with the term id given.
+func termPath(id string) string {
+	return termsPath + "/" + url.PathEscape(id)
+}
+
+// offeringPath returns the path of the offering of a course in a term.
+func offeringPath(termID, courseID string) string {
+	return termPath(termID) + "/offerings/" + url.PathEscape(courseID)
+}
+
+// ListTerms retrieves all terms, sorted by start date.
+func (c *Client) ListTerms(ctx context.Context) ([]Term, error) {
+	var terms map[string]termInfo
+	if err := c.do(ctx, http.MethodGet, termsPath, nil, &terms); err != nil {
+		return nil, err
+	}
+	return sortTerms(terms), nil
+}
+
+// GetTerm retrieves the term with the term id given.
+// Returns an error matching ErrNotFound if there is no such term.
+func (c *Client) GetTerm(ctx context.Context, id string) (Term, error) {
+	var terms map[string]termInfo
+	if err := c.do(ctx, http.MethodGet, termPath(id), nil, &terms); err != nil {
+		return Term{}, err
+	}
+
+	for _, term := range sortTerms(terms) {
+		return term, nil
+	}
+	return Term{}, &APIError{StatusCode: http.StatusNotFound}
+}
+
+// AddTerm adds a new term.
+// Returns an error matching ErrConflict if the term id is already in use,
+// or ErrInvalid if the details are not valid.
+func (c *Client) AddTerm(ctx context.Context, term Term) error {
+	return c.do(ctx, http.MethodPost, termPath(term.ID), termInfo{term.Name, term.StartDate, term.EndDate}, nil)
+}
+
+// UpdateTerm updates the name and dates of an existing term.
+// Returns an error matching ErrNotFound if there is no such term.
+func (c *Client) UpdateTerm(ctx context.Context, term Term) error {
+	return c.do(ctx, http.MethodPut, termPath(term.ID), termInfo{term.Name, term.StartDate, term.EndDate}, nil)
+}
+
+// DeleteTerm deletes the term with the term id given. Returns an error matching
+// ErrNotFound if there is no such term, or ErrConflict while courses are offered in it
+// or recorded on transcripts as completed in it.
+func (c *Client) DeleteTerm(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, termPath(id), nil, nil)
+}
+
+// ListOfferings retrieves the courses offered in the term, sorted by course ID.
+// Returns an error matching ErrNotFound if there is no such term.
+func (c *Client) ListOfferings(ctx context.Context, termID string) ([]Offering, error) {
+	var offerings map[string]offeringInfo
+	if err := c.do(ctx, http.MethodGet, termPath(termID)+"/offerings", nil, &offerings); err != nil {
+		return nil, err
+	}
+
+	list := make([]Offering, 0, len(offerings))
+	for courseID, info := range offerings {
+		list = append(list, toOffering(courseID, termID, info))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CourseID < list[j].CourseID
+	})
+	return list, nil
+}
+
+// ListCourseOfferings retrieves the offerings of the course in each term, sorted by term ID.
+// Returns an error matching ErrNotFound if there is no such course.
+func (c *Client) ListCourseOfferings(ctx context.Context, courseID string) ([]Offering, error) {
+	var offerings map[string]offeringInfo
+	if err := c.do(ctx, http.MethodGet, coursePath(courseID)+"/offerings", nil, &offerings); err != nil {
+		return nil, err
+	}
+
+	list := make([]Offering, 0, len(offerings))
+	for termID, info := range offerings {
+		list = append(list, toOffering(courseID, termID, info))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].TermID < list[j].TermID
+	})
+	return list, nil
+}
+
+// SetOffering offers the course in the term with the capacity given, 0 for unlimited,
+// or changes the capacity of the offering. Waitlisted students are enrolled into any
+// seats added. Returns an error matching ErrNotFound if there is no such course or term.
+func (c *Client) SetOffering(ctx context.Context, courseID, termID string, capacity int) error {
+	in := struct {
+		Capacity int `json:"Capacity"`
+	}{capacity}
+
+	return c.do(ctx, http.MethodPut, offeringPath(termID, courseID), in, nil)
+}
+
+// DeleteOffering withdraws the offering of the course in the term, with its sessions.
+// Returns an error matching ErrNotFound if the course is not offered in the term, or
+// ErrConflict while students are enrolled or waitlisted in it.
+func (c *Client) DeleteOffering(ctx context.Context, courseID, termID string) error {
+	return c.do(ctx, http.MethodDelete, offeringPath(termID, courseID), nil, nil)
+}
+
+// toOffering converts the json received from the REST API to an Offering.
+func toOffering(courseID, termID string, info offeringInfo) Offering {
+	return Offering{courseID, termID, info.Title, info.Term, info.Capacity, info.Enrolled, info.Waitlisted}
+}
+
+// sortTerms converts the terms keyed by term ID into a slice sorted by start date, then term ID.
+func sortTerms(terms map[string]termInfo) []Term {
+	list := make([]Term, 0, len(terms))
+	for id, info := range terms {
+		list = append(list, Term{id, info.Name, info.StartDate, info.EndDate})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].StartDate != list[j].StartDate {
+			return list[i].StartDate < list[j].StartDate
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
@@ -0,0 +1,47 @@
+package coursesapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// transcriptPath returns the path of the transcript of the student with the student id given.
+func transcriptPath(studentID string) string {
+	return "/api/v1/learners/" + url.PathEscape(studentID) + "/transcript"
+}
+
+// completionPath returns the path of the completion of a course by a student in a term.
+func completionPath(studentID string, termID string, courseID string) string {
+	return transcriptPath(studentID) + "/" + url.PathEscape(termID) + "/" + url.PathEscape(courseID)
+}
+
+// Transcript retrieves the transcript of the student with the student id given.
+// Returns an error matching ErrNotFound if there is no such student.
+func (c *Client) Transcript(ctx context.Context, studentID string) (Transcript, error) {
+	var transcript Transcript
+	err := c.do(ctx, http.MethodGet, transcriptPath(studentID), nil, &transcript)
+	return transcript, err
+}
+
+// TranscriptPDF retrieves the transcript of the student as a printable PDF document.
+// Returns an error matching ErrNotFound if there is no such student.
+func (c *Client) TranscriptPDF(ctx context.Context, studentID string) ([]byte, error) {
+	var pdf []byte
+	err := c.doQuery(ctx, http.MethodGet, transcriptPath(studentID), url.Values{"format": {"pdf"}}, nil, &pdf)
+	return pdf, err
+}
+
+// RecordCompletion records that the student completed the course in the term of the
+// completion with its grade and credits, replacing any existing record, and marks their
+// enrolment in the course as completed. Returns an error matching ErrNotFound if there is
+// no such student, term or course, or ErrInvalid if the grade or credits are not valid.
+func (c *Client) RecordCompletion(ctx context.Context, studentID string, completion Completion) error {
+	return c.do(ctx, http.MethodPut, completionPath(studentID, completion.TermID, completion.CourseID), completion, nil)
+}
+
+// DeleteCompletion deletes the record of the course the student completed in the term.
+// Returns an error matching ErrNotFound if there is no such record.
+func (c *Client) DeleteCompletion(ctx context.Context, studentID string, termID string, courseID string) error {
+	return c.do(ctx, http.MethodDelete, completionPath(studentID, termID, courseID), nil, nil)
+}
@@ -0,0 +1,191 @@
+/*
+Package cli implements the goschool command-line tool for managing courses
+against the GoSchool REST API.
+
+	goschool [flags] <command> [arguments]
+
+	Commands:
+		list                      list all active courses
+		get <courseid>            show a course
+		add <courseid> <title>    add a draft course
+		update <courseid> <title> update the title of a course
+		rename <courseid> <new>   change the course ID of a course
+		delete <courseid>         delete a course
+		import <file>             add courses from a CSV or JSON file
+		export [file]             write all courses in any status as CSV or JSON
+
+	Flags:
+		-o table|json|csv  output format (default table)
+		-url URL           base URL of the REST API
+		-key KEY           access key
+		-config FILE       config file (default $HOME/.goschool.env)
+		-timeout DURATION  timeout per request (default 10s)
+
+The base URL and access key are read from the flags, then the GOSCHOOL_BASE_URL and
+GOSCHOOL_API_KEY environment variables, then the config file.
+
+The exit code reports the outcome so scripts can act on it:
+
+	0 success
+	1 other error
+	2 usage error
+	3 course not found (404)
+	4 duplicate course ID (409)
+	5 invalid course information (400, 413, 415, 422)
+	6 invalid access key (401), or not an admin key (403)
+	7 REST API unavailable
+
+It is separated into 4 .go files to segregate the functionalities of the package.
+
+	cli.go: Parses the flags and runs the command.
+
+	config.go: Reads the base URL and access key from the environment and config file.
+
+	commands.go: Implements the commands by invoking the coursesapi client.
+
+	output.go: Writes courses as a table, JSON or CSV.
+*/
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitUsage        = 2
+	ExitNotFound     = 3
+	ExitConflict     = 4
+	ExitInvalid      = 5
+	ExitUnauthorized = 6
+	ExitUnavailable  = 7
+)
+
+// errUsage is returned by a command given the wrong arguments.
+var errUsage = errors.New("usage")
+
+// command struct for a goschool command
+type command struct {
+	Usage string
+	Run   func(ctx context.Context, env *env, args []string) error
+}
+
+// commands maps the command names to their implementation.
+var commands = map[string]command{
+	"list":   {"list", list},
+	"get":    {"get <courseid>", get},
+	"add":    {"add <courseid> <title>", add},
+	"update": {"update <courseid> <title>", update},
+	"rename": {"rename <courseid> <newcourseid>", rename},
+	"delete": {"delete <courseid>", remove},
+	"import": {"import <file.csv|file.json>", importCourses},
+	"export": {"export [file.csv|file.json]", exportCourses},
+}
+
+// env holds what a command needs to run.
+type env struct {
+	api    *coursesapi.Client
+	format string
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Run runs the goschool command given by args, writing output to stdout and errors
+// to stderr. Returns the exit code.
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("goschool", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("o", "table", "output format: table, json or csv")
+	baseURL := flags.String("url", "", "base URL of the REST API")
+	key := flags.String("key", "", "access key")
+	configFile := flags.String("config", defaultConfigFile(), "config file")
+	timeout := flags.Duration("timeout", 10*time.Second, "timeout per request")
+	flags.Usage = func() { usage(flags) }
+
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if flags.NArg() == 0 {
+		usage(flags)
+		return ExitUsage
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "goschool: unknown command %q\n", flags.Arg(0))
+		usage(flags)
+		return ExitUsage
+	}
+
+	switch *format {
+	case "table", "json", "csv":
+	default:
+		fmt.Fprintf(stderr, "goschool: unknown output format %q\n", *format)
+		return ExitUsage
+	}
+
+	config, err := loadConfig(*configFile, *baseURL, *key)
+	if err != nil {
+		fmt.Fprintln(stderr, "goschool:", err)
+		return ExitError
+	}
+
+	e := &env{
+		api: coursesapi.NewClient(config.BaseURL, config.Key,
+			coursesapi.WithTimeout(*timeout),
+			coursesapi.WithRetries(2, 200*time.Millisecond)),
+		format: *format,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	err = cmd.Run(context.Background(), e, flags.Args()[1:])
+	if errors.Is(err, errUsage) {
+		fmt.Fprintln(stderr, "usage: goschool [flags]", cmd.Usage)
+		return ExitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "goschool:", err)
+	}
+	return exitCode(err)
+}
+
+// exitCode maps the error returned by a command to the exit code of the tool.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, coursesapi.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, coursesapi.ErrConflict):
+		return ExitConflict
+	case errors.Is(err, coursesapi.ErrInvalid):
+		return ExitInvalid
+	case errors.Is(err, coursesapi.ErrUnauthorized), errors.Is(err, coursesapi.ErrForbidden):
+		return ExitUnauthorized
+	case errors.Is(err, coursesapi.ErrUnavailable):
+		return ExitUnavailable
+	}
+	return ExitError
+}
+
+// usage writes the usage of the tool.
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "usage: goschool [flags] <command> [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, name := range []string{"list", "get", "add", "update", "rename", "delete", "import", "export"} {
+		fmt.Fprintln(out, "  "+commands[name].Usage)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+}
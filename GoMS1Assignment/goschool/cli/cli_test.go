@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"not found", &coursesapi.APIError{StatusCode: http.StatusNotFound}, ExitNotFound},
+		{"conflict", &coursesapi.APIError{StatusCode: http.StatusConflict}, ExitConflict},
+		{"bad request", &coursesapi.APIError{StatusCode: http.StatusBadRequest}, ExitInvalid},
+		{"too large", &coursesapi.APIError{StatusCode: http.StatusRequestEntityTooLarge}, ExitInvalid},
+		{"unprocessable", &coursesapi.APIError{StatusCode: http.StatusUnprocessableEntity}, ExitInvalid},
+		{"unauthorized", &coursesapi.APIError{StatusCode: http.StatusUnauthorized}, ExitUnauthorized},
+		{"forbidden", &coursesapi.APIError{StatusCode: http.StatusForbidden}, ExitUnauthorized},
+		{"unavailable", &coursesapi.APIError{StatusCode: http.StatusServiceUnavailable}, ExitUnavailable},
+		{"wrapped", fmt.Errorf("reading courses.csv: %w", &coursesapi.APIError{StatusCode: http.StatusConflict}), ExitConflict},
+		{"internal error", &coursesapi.APIError{StatusCode: http.StatusInternalServerError}, ExitError},
+		{"other error", errors.New("disk full"), ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// testAPI starts a REST API which has the course GO101 only, and returns its base URL.
+func testAPI(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("401 - Invalid key"))
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/api/v1/courses" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"GO101":{"Title":"Go Programming","Capacity":0,"Status":"active"}}`))
+		case r.URL.Path == "/api/v1/courses/GO101" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"GO101":{"Title":"Go Programming","Capacity":0,"Status":"active"}}`))
+		case r.URL.Path == "/api/v1/courses/GO101" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Duplicate course ID"))
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("201 - Course added"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No course found"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRun(t *testing.T) {
+	baseURL := testAPI(t)
+	t.Setenv("GOSCHOOL_BASE_URL", "")
+	t.Setenv("GOSCHOOL_API_KEY", "")
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string
+		wantStderr string
+	}{
+		{"no command", nil, ExitUsage, "", "usage: goschool"},
+		{"unknown command", []string{"enrol"}, ExitUsage, "", `unknown command "enrol"`},
+		{"unknown flag", []string{"-verbose", "list"}, ExitUsage, "", "flag provided but not defined"},
+		{"unknown format", []string{"-o", "xml", "list"}, ExitUsage, "", `unknown output format "xml"`},
+		{"wrong arguments", []string{"get"}, ExitUsage, "", "usage: goschool [flags] get <courseid>"},
+		{"list", []string{"list"}, ExitOK, "GO101      Go Programming  active", ""},
+		{"get as csv", []string{"-o", "csv", "get", "GO101"}, ExitOK, "CourseID,CourseTitle\nGO101,Go Programming\n", ""},
+		{"get missing course", []string{"get", "PY201"}, ExitNotFound, "", "404"},
+		{"add joins the title", []string{"add", "PY201", "Python", "Basics"}, ExitOK, "", "PY201 - Python Basics added"},
+		{"add duplicate", []string{"add", "GO101", "Go"}, ExitConflict, "", "409"},
+		{"invalid key", []string{"-key", "wrong", "list"}, ExitUnauthorized, "", "401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-config", "", "-url", baseURL, "-key", "secret"}, tt.args...)
+
+			if got := Run(args, &stdout, &stderr); got != tt.want {
+				t.Errorf("Run(%q) = %d, want %d; stderr: %s", tt.args, got, tt.want, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("Run(%q) stdout = %q, want it to contain %q", tt.args, stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("Run(%q) stderr = %q, want it to contain %q", tt.args, stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GOSCHOOL_BASE_URL", "http://env:5000")
+	t.Setenv("GOSCHOOL_API_KEY", "envkey")
+
+	c, err := loadConfig("", "", "")
+	if err != nil || c.BaseURL != "http://env:5000" || c.Key != "envkey" {
+		t.Errorf("loadConfig() = %+v, %v, want the environment variables", c, err)
+	}
+
+	c, err = loadConfig("", "http://flag:5000", "flagkey")
+	if err != nil || c.BaseURL != "http://flag:5000" || c.Key != "flagkey" {
+		t.Errorf("loadConfig() = %+v, %v, want the flags over the environment variables", c, err)
+	}
+
+	t.Setenv("GOSCHOOL_BASE_URL", "")
+	t.Setenv("GOSCHOOL_API_KEY", "")
+	if _, err := loadConfig(t.TempDir()+"/missing.env", "", ""); err == nil {
+		t.Error("loadConfig() without an access key error = nil")
+	}
+	if c, err := loadConfig("", "", "key"); err != nil || c.BaseURL != defaultBaseURL {
+		t.Errorf("loadConfig() = %+v, %v, want the default base URL", c, err)
+	}
+}
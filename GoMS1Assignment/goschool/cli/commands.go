@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// list writes all active courses.
+func list(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	courses, err := e.api.ListCourses(ctx)
+	if err != nil {
+		return err
+	}
+	return writeCourses(e.stdout, e.format, courses)
+}
+
+// get writes the course with the course id given.
+func get(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	course, err := e.api.GetCourse(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return writeCourses(e.stdout, e.format, []coursesapi.Course{course})
+}
+
+// add adds a course. The title may be given as several arguments.
+func add(ctx context.Context, e *env, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	course := coursesapi.Course{ID: args[0], Title: strings.Join(args[1:], " ")}
+	if err := e.api.AddCourse(ctx, course); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%s - %s added\n", course.ID, course.Title)
+	return nil
+}
+
+// update updates the title of a course. The title may be given as several arguments.
+func update(ctx context.Context, e *env, args []string) error {
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	course := coursesapi.Course{ID: args[0], Title: strings.Join(args[1:], " ")}
+	if err := e.api.UpdateCourse(ctx, course); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%s - %s updated\n", course.ID, course.Title)
+	return nil
+}
+
+// rename changes the course id of a course. The old course id redirects to the new one.
+func rename(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+
+	if err := e.api.RenameCourse(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%s renamed to %s\n", args[0], args[1])
+	return nil
+}
+
+// remove deletes the course with the course id given.
+func remove(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	if err := e.api.DeleteCourse(ctx, args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%s deleted\n", args[0])
+	return nil
+}
+
+// importCourses adds the courses in a CSV or JSON file, chosen by its extension.
+// Courses which already exist are updated. Every course is attempted; the first
+// error is returned so the exit code reflects it.
+func importCourses(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	courses, err := readCourses(f, fileFormat(args[0]))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", args[0], err)
+	}
+
+	var firstErr error
+	added, updated := 0, 0
+	for _, course := range courses {
+		err := e.api.AddCourse(ctx, course)
+		if errors.Is(err, coursesapi.ErrConflict) {
+			err = e.api.UpdateCourse(ctx, course)
+			if err == nil {
+				updated++
+			}
+		} else if err == nil {
+			added++
+		}
+
+		if err != nil {
+			fmt.Fprintf(e.stderr, "%s: %v\n", course.ID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "%d added, %d updated, %d failed\n", added, updated, len(courses)-added-updated)
+	return firstErr
+}
+
+// exportCourses writes all courses in any status to a file, in the format chosen by its extension,
+// or to stdout in the output format if no file is given. Table output is written as CSV.
+func exportCourses(ctx context.Context, e *env, args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+
+	courses, err := e.api.FindCourses(ctx, coursesapi.CourseFilter{Status: coursesapi.AllStatuses})
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		format := e.format
+		if format == "table" {
+			format = "csv"
+		}
+		return writeCourses(e.stdout, format, courses)
+	}
+
+	f, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	if err := writeCourses(f, fileFormat(args[0]), courses); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%d courses exported to %s\n", len(courses), args[0])
+	return nil
+}
+
+// fileFormat returns json for a .json file, otherwise csv.
+func fileFormat(name string) string {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return "json"
+	}
+	return "csv"
+}
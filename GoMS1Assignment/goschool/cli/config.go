@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+)
+
+// defaultBaseURL is used when no base URL is configured.
+const defaultBaseURL = "http://localhost:5000"
+
+// config struct for the settings of the tool
+type config struct {
+	BaseURL string
+	Key     string
+}
+
+// defaultConfigFile returns the path of the default config file, $HOME/.goschool.env.
+func defaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".goschool.env"
+	}
+	return filepath.Join(home, ".goschool.env")
+}
+
+// loadConfig resolves the base URL and access key from the flags given, then the
+// GOSCHOOL_BASE_URL and GOSCHOOL_API_KEY environment variables, then the config file.
+// A missing config file is not an error.
+func loadConfig(file string, baseURL string, key string) (config, error) {
+	values := map[string]string{}
+	if file != "" {
+		var err error
+		values, err = godotenv.Read(file)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return config{}, err
+		}
+	}
+
+	c := config{
+		BaseURL: first(baseURL, os.Getenv("GOSCHOOL_BASE_URL"), values["GOSCHOOL_BASE_URL"], defaultBaseURL),
+		Key:     first(key, os.Getenv("GOSCHOOL_API_KEY"), values["GOSCHOOL_API_KEY"]),
+	}
+	if c.Key == "" {
+		return config{}, errors.New("no access key: set GOSCHOOL_API_KEY or use -key")
+	}
+	return c, nil
+}
+
+// first returns the first non-empty string.
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
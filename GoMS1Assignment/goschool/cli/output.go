@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// writeCourses writes the courses in the format given: table, json or csv.
+func writeCourses(w io.Writer, format string, courses []coursesapi.Course) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(courses)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"CourseID", "CourseTitle"})
+		for _, c := range courses {
+			cw.Write([]string{c.ID, c.Title})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "COURSE ID\tCOURSE TITLE\tSTATUS")
+		for _, c := range courses {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.ID, c.Title, c.Status)
+		}
+		return tw.Flush()
+	}
+}
+
+// readCourses reads courses in the format given, csv or json. A CSV file must have
+// the header CourseID,CourseTitle as written by export.
+func readCourses(r io.Reader, format string) ([]coursesapi.Course, error) {
+	if format == "json" {
+		var courses []coursesapi.Course
+		if err := json.NewDecoder(r).Decode(&courses); err != nil {
+			return nil, err
+		}
+		return courses, nil
+	}
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records[0]) < 2 || records[0][0] != "CourseID" || records[0][1] != "CourseTitle" {
+		return nil, fmt.Errorf("CSV must have the header CourseID,CourseTitle")
+	}
+
+	var courses []coursesapi.Course
+	for _, record := range records[1:] {
+		courses = append(courses, coursesapi.Course{ID: record[0], Title: record[1]})
+	}
+	return courses, nil
+}
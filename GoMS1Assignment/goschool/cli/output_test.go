@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+func TestWriteCourses(t *testing.T) {
+	courses := []coursesapi.Course{
+		{ID: "GO101", Title: "Go Programming", Status: "active"},
+		{ID: "PY201", Title: "Python, Advanced", Status: "draft"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "COURSE ID  COURSE TITLE      STATUS\n" +
+			"GO101      Go Programming    active\n" +
+			"PY201      Python, Advanced  draft\n"},
+		{"csv", "CourseID,CourseTitle\nGO101,Go Programming\nPY201,\"Python, Advanced\"\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeCourses(&buf, tt.format, courses); err != nil {
+			t.Fatalf("writeCourses(%s) error = %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("writeCourses(%s) = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+
+	// JSON is read back as written
+	var buf bytes.Buffer
+	if err := writeCourses(&buf, "json", courses); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readCourses(&buf, "json")
+	if err != nil || !reflect.DeepEqual(got, courses) {
+		t.Errorf("readCourses(json) = %+v, %v, want %+v", got, err, courses)
+	}
+}
+
+func TestReadCourses(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []coursesapi.Course
+		wantErr bool
+	}{
+		{"csv", "CourseID,CourseTitle\nGO101,Go Programming\nPY201,\"Python, Advanced\"\n",
+			[]coursesapi.Course{{ID: "GO101", Title: "Go Programming"}, {ID: "PY201", Title: "Python, Advanced"}}, false},
+		{"header only", "CourseID,CourseTitle\n", nil, false},
+		{"wrong header", "ID,Title\nGO101,Go\n", nil, true},
+		{"empty", "", nil, true},
+		{"uneven rows", "CourseID,CourseTitle\nGO101\n", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCourses(strings.NewReader(tt.input), "csv")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readCourses() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readCourses() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileFormat(t *testing.T) {
+	for name, want := range map[string]string{"courses.json": "json", "COURSES.JSON": "json", "courses.csv": "csv", "courses": "csv"} {
+		if got := fileFormat(name); got != want {
+			t.Errorf("fileFormat(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
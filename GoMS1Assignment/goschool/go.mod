@@ -0,0 +1,10 @@
+module GoMS1Assignment/goschool
+
+go 1.21
+
+require (
+	GoMS1Assignment/coursesapi v0.0.0
+	github.com/joho/godotenv v1.3.0
+)
+
+replace GoMS1Assignment/coursesapi => ../coursesapi
@@ -0,0 +1,13 @@
+// Package main invokes the cli package to run the goschool command-line tool.
+package main
+
+import (
+	"os"
+
+	"GoMS1Assignment/goschool/cli"
+)
+
+// main calls cli.Run() and exits with the exit code it returns.
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,90 @@
+// Package blobstore stores the files uploaded to the REST API, such as course attachments,
+// in a directory on disk. Each file is kept under a random key which is recorded in the database.
+package blobstore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidKey is returned for keys which were not issued by Put.
+var ErrInvalidKey = errors.New("blobstore: invalid key")
+
+// Store is a directory holding the stored files.
+type Store struct {
+	dir string
+}
+
+// New returns a Store in dir, creating the directory if it does not exist.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error creating blob directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Put stores the contents of r and returns its key and size in bytes.
+// The file is only visible under its key once it is completely written.
+func (s *Store) Put(r io.Reader) (string, int64, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", 0, fmt.Errorf("error generating blob key: %w", err)
+	}
+	key := hex.EncodeToString(b)
+
+	tmp, err := os.CreateTemp(s.dir, "upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating blob: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	size, err := io.Copy(tmp, r)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		tmp.Close()
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error writing blob: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, key)); err != nil {
+		return "", 0, fmt.Errorf("error storing blob: %w", err)
+	}
+	return key, size, nil
+}
+
+// Open returns the file stored under key, which the caller must close.
+func (s *Store) Open(key string) (*os.File, error) {
+	if !validKey(key) {
+		return nil, ErrInvalidKey
+	}
+	return os.Open(filepath.Join(s.dir, key))
+}
+
+// Delete removes the file stored under key. Deleting a missing file is not an error.
+func (s *Store) Delete(key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(filepath.Join(s.dir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// validKey reports whether key has the form issued by Put, so it cannot name a file
+// outside the store.
+func validKey(key string) bool {
+	if len(key) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
@@ -0,0 +1,107 @@
+/*
+Command courseids reports the course IDs stored in the database which do not meet the
+canonical form of course IDs, trimmed, upper-cased and Unicode-normalised, as applied by
+the REST API and client since it was introduced.
+
+It is run from the restapi directory so that setup.env is found:
+
+	go run ./cmd/courseids
+
+The REST API renames the courses with non-canonical IDs to their canonical form when it
+starts, except for collisions. Collisions are groups of courses whose IDs have the same
+canonical form, such as go101 and GO101, which must be resolved by hand before the
+courses are found by canonical ID; the REST API gives them canonical IDs once it is
+restarted. Nothing is changed in the database by the command. It exits with status 1 if
+there are collisions.
+*/
+package main
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// main connects to the database configured in setup.env and prints the report.
+func main() {
+	if err := godotenv.Load("setup.env"); err != nil {
+		fmt.Fprintln(os.Stderr, "error loading .env file:", err)
+		os.Exit(2)
+	}
+
+	config := database.Config{
+		ServerName: os.Getenv("SERVER_NAME"),
+		User:       os.Getenv("DB_USERNAME"),
+		Password:   os.Getenv("DB_PASSWORD"),
+		DB:         os.Getenv("DB_NAME"),
+	}
+	if err := database.Connect(database.GetConnectionString(config)); err != nil {
+		fmt.Fprintln(os.Stderr, "error connecting to database:", err)
+		os.Exit(2)
+	}
+	if err := database.DB.Ping(); err != nil {
+		fmt.Fprintln(os.Stderr, "error pinging to database:", err)
+		os.Exit(2)
+	}
+
+	collisions, renames := report(database.GetCourseIDs(context.Background()))
+
+	if len(collisions) == 0 && len(renames) == 0 {
+		fmt.Println("All course IDs are canonical.")
+		return
+	}
+
+	if len(collisions) != 0 {
+		fmt.Printf("%d collisions:\n", len(collisions))
+		for _, canonical := range sortedKeys(collisions) {
+			fmt.Printf("  %s: %s\n", canonical, strings.Join(collisions[canonical], ", "))
+		}
+	}
+	if len(renames) != 0 {
+		fmt.Printf("%d course IDs to be renamed when the REST API starts:\n", len(renames))
+		for _, id := range sortedKeys(renames) {
+			fmt.Printf("  %q -> %s\n", id, renames[id])
+		}
+	}
+
+	if len(collisions) != 0 {
+		os.Exit(1)
+	}
+}
+
+// report groups the course IDs by canonical form. It returns the groups of more than one ID
+// keyed by canonical form, and the other IDs which are not canonical with their canonical form.
+func report(courseIDs []string) (map[string][]string, map[string]string) {
+	groups := make(map[string][]string)
+	for _, id := range courseIDs {
+		canonical := validation.CanonicalCourseID(id)
+		groups[canonical] = append(groups[canonical], id)
+	}
+
+	collisions := make(map[string][]string)
+	renames := make(map[string]string)
+	for canonical, ids := range groups {
+		if len(ids) > 1 {
+			collisions[canonical] = ids
+		} else if ids[0] != canonical {
+			renames[ids[0]] = canonical
+		}
+	}
+	return collisions, renames
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name           string
+		courseIDs      []string
+		wantCollisions map[string][]string
+		wantRenames    map[string]string
+	}{
+		{"canonical", []string{"GO101", "PY201"}, map[string][]string{}, map[string]string{}},
+		{"renames", []string{"GO101", "py201", " JS301 "},
+			map[string][]string{}, map[string]string{"py201": "PY201", " JS301 ": "JS301"}},
+		{"collisions", []string{"GO101", "go101", "ＧＯ１０１", "py201"},
+			map[string][]string{"GO101": {"GO101", "go101", "ＧＯ１０１"}}, map[string]string{"py201": "PY201"}},
+		{"none", nil, map[string][]string{}, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collisions, renames := report(tt.courseIDs)
+			if !reflect.DeepEqual(collisions, tt.wantCollisions) {
+				t.Errorf("report(%q) collisions = %q, want %q", tt.courseIDs, collisions, tt.wantCollisions)
+			}
+			if !reflect.DeepEqual(renames, tt.wantRenames) {
+				t.Errorf("report(%q) renames = %q, want %q", tt.courseIDs, renames, tt.wantRenames)
+			}
+		})
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	got := sortedKeys(map[string]int{"PY201": 1, "GO101": 2, "JS301": 3})
+	if want := []string{"GO101", "JS301", "PY201"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedKeys() = %q, want %q", got, want)
+	}
+}
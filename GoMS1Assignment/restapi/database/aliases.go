@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// courseIDColumns are the columns, other than Courses.CourseID, which hold the ID of a
+// course and are updated by RenameCourse. Every foreign key to Courses must be listed, as
+// checked by TestCourseIDColumns.
+var courseIDColumns = []struct{ table, column string }{
+	{"Enrolments", "CourseID"},
+	{"CourseInstructors", "CourseID"},
+	{"Prerequisites", "CourseID"},
+	{"Prerequisites", "PrerequisiteID"},
+	{"Sessions", "CourseID"},
+	{"Offerings", "CourseID"},
+	{"Assessments", "CourseID"},
+	{"GradeBoundaries", "CourseID"},
+	{"Completions", "CourseID"},
+	{"Classes", "CourseID"},
+	{"CourseTags", "CourseID"},
+	{"CourseHistory", "CourseID"},
+	{"CourseAttachments", "CourseID"},
+	{"CourseAliases", "CourseID"},
+}
+
+// RenameCourse implements the sql operations to change the ID of a course as invoked by the
+// REST API. The records of the course are moved to the new ID, the old ID is kept as an alias
+// of the course unless keepAlias is false, and the rename is recorded in the history of the
+// course. Any alias newID was of the course is removed. newID must be canonical.
+// Returns ErrNoCourse if there is no course with the ID given, ErrDuplicateCourseID if newID is
+// in use by another course or another course was renamed from it, and error type.
+func RenameCourse(ctx context.Context, courseID string, newID string, keepAlias bool, changedBy string) (err error) {
+	defer observeCall("RenameCourse", time.Now())
+
+	query := "UPDATE Courses SET CourseID=?, CanonicalID=?, LastModified_DT=? WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "RenameCourse", query)
+	defer span.End()
+	defer recoverError(ctx, "RenameCourse", &err)
+
+	// Sessions reference both the course and its offerings, so cascading the new ID through
+	// the foreign keys would reach them by two paths. Foreign key checks are turned off on a
+	// connection of its own instead, and every column in courseIDColumns updated explicitly.
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		panic(fmt.Errorf("error getting connection: %w", err))
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS=0"); err != nil {
+		panic(fmt.Errorf("error disabling foreign key checks: %w", err))
+	}
+	defer func() {
+		// A connection which cannot be reset is discarded rather than returned to the pool
+		if _, err := conn.ExecContext(context.Background(), "SET FOREIGN_KEY_CHECKS=1"); err != nil {
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// A course renamed back to a former ID no longer redirects from it
+	if aliasOf := lockCourseAlias(ctx, tx, newID); aliasOf != "" && aliasOf != courseID {
+		return ErrDuplicateCourseID
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM CourseAliases WHERE AliasID=? AND CourseID=?", newID, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	result, err := tx.ExecContext(ctx, query, newID, newID, time.Now(), courseID)
+	if duplicateKey(err) {
+		return ErrDuplicateCourseID
+	} else if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql update: %w", err))
+	} else if n == 0 {
+		return ErrNoCourse
+	}
+
+	for _, c := range courseIDColumns {
+		_, err = tx.ExecContext(ctx, "UPDATE "+c.table+" SET "+c.column+"=? WHERE "+c.column+"=?", newID, courseID)
+		if err != nil {
+			panic(fmt.Errorf("error executing sql update of %s.%s: %w", c.table, c.column, err))
+		}
+	}
+
+	if keepAlias {
+		_, err = tx.ExecContext(ctx, "INSERT INTO CourseAliases (AliasID, CourseID, Created_DT) VALUES (?, ?, ?)",
+			courseID, newID, time.Now())
+		if err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	// The status is unchanged, the history records the rename in its comment
+	_, err = tx.ExecContext(ctx, "INSERT INTO CourseHistory (CourseID, FromStatus, ToStatus, Comment, ChangedBy, Created_DT) "+
+		"SELECT CourseID, Status, Status, ?, ?, ? FROM Courses WHERE CourseID=?",
+		"Renamed from "+courseID, changedBy, time.Now(), newID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return nil
+}
+
+// GetCourseAlias implements the sql operations to retrieve the current ID of a course renamed
+// from the ID given as invoked by the REST API. Returns false if no course was renamed from it.
+func GetCourseAlias(ctx context.Context, aliasID string) (string, bool) {
+	defer observeCall("GetCourseAlias", time.Now())
+
+	query := "SELECT AliasID, CourseID FROM CourseAliases WHERE AliasID=?"
+
+	ctx, span := startSpan(ctx, "GetCourseAlias", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourseAlias")
+
+	for _, courseID := range queryAliases(ctx, query, aliasID) {
+		return courseID, true
+	}
+	return "", false
+}
+
+// lockCourseAlias locks the alias with the ID given in tx, or the gap where it would be, so
+// that no course is renamed from the ID until tx ends. It is called before a course is added
+// or renamed to the ID, as the unique index on the canonical ID only covers current IDs.
+// Returns the ID of the course renamed from the ID, or "" if there is none.
+// It panics on error to be recovered by the calling function.
+func lockCourseAlias(ctx context.Context, tx *sql.Tx, aliasID string) string {
+	var courseID string
+	err := tx.QueryRowContext(ctx, "SELECT CourseID FROM CourseAliases WHERE AliasID=? FOR UPDATE", aliasID).Scan(&courseID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	return courseID
+}
+
+// queryAliases runs a select of alias and course IDs and returns the course IDs keyed by alias.
+// It panics on error to be recovered by the calling function.
+func queryAliases(ctx context.Context, query string, args ...interface{}) map[string]string {
+	aliases := make(map[string]string)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var aliasID, courseID string
+		if err := results.Scan(&aliasID, &courseID); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		aliases[aliasID] = courseID
+	}
+	return aliases
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// TestCourseIDColumns checks that every column with a foreign key to Courses in the
+// migrations is updated by RenameCourse.
+func TestCourseIDColumns(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := regexp.MustCompile(`(?i)^(?:CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?|ALTER\s+TABLE\s+)(\w+)`)
+	foreignKey := regexp.MustCompile(`(?i)FOREIGN\s+KEY\s+\((\w+)\)\s+REFERENCES\s+Courses\s+\(CourseID\)`)
+
+	listed := make(map[string]bool)
+	for _, c := range courseIDColumns {
+		listed[c.table+"."+c.column] = true
+	}
+
+	found := 0
+	for _, m := range migrations {
+		for _, stmt := range splitStatements(m.SQL) {
+			name := table.FindStringSubmatch(stmt)
+			for _, fk := range foreignKey.FindAllStringSubmatch(stmt, -1) {
+				if name == nil {
+					t.Errorf("migration %s has a foreign key to Courses outside CREATE or ALTER TABLE", m.Version)
+					continue
+				}
+				found++
+				if column := name[1] + "." + fk[1]; !listed[column] {
+					t.Errorf("%s references Courses but is not in courseIDColumns", column)
+				}
+			}
+		}
+	}
+	if found != len(courseIDColumns) {
+		t.Errorf("found %d foreign keys to Courses, courseIDColumns lists %d columns", found, len(courseIDColumns))
+	}
+}
+
+func TestRenameCourse(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID, newID, studentID, termID := testID("C"), testID("N"), testID("S"), testID("T")
+
+	AddCourse(ctx, courseID, "Rename test", "", 0, "")
+	t.Cleanup(func() { DeleteCourse(ctx, courseID); DeleteCourse(ctx, newID) })
+	AddStudent(ctx, studentID, StudentInfo{Name: "Test Student", Email: "test@example.com", DateOfBirth: "2000-01-01", Status: "active"})
+	t.Cleanup(func() { DeleteStudent(ctx, studentID) })
+	AddTerm(ctx, termID, TermInfo{Name: termID, StartDate: "2030-01-01", EndDate: "2030-04-30"})
+	t.Cleanup(func() { DeleteTerm(ctx, termID) })
+
+	// Sessions reference both the course and its offering
+	SetOffering(ctx, courseID, termID, 0)
+	sessionID, clashes := AddSession(ctx, SessionInfo{CourseID: courseID, Term: termID, Day: "Mon",
+		StartTime: "09:00", EndTime: "10:00", Room: testID("R")})
+	if sessionID == 0 {
+		t.Fatalf("AddSession failed: %v", clashes)
+	}
+	Enrol(ctx, courseID, termID, studentID)
+	Enrol(ctx, courseID, "", studentID)
+
+	if err := RenameCourse(ctx, courseID, newID, true, "test"); err != nil {
+		t.Fatalf("RenameCourse() error = %v", err)
+	}
+
+	if len(GetCourse(ctx, courseID)) != 0 || len(GetCourse(ctx, newID)) == 0 {
+		t.Errorf("course was not renamed from %s to %s", courseID, newID)
+	}
+	if s := GetSession(ctx, sessionID); len(s) == 0 || s[strconv.FormatInt(sessionID, 10)].CourseID != newID {
+		t.Errorf("session after rename = %+v, want course %s", s, newID)
+	}
+	if _, ok := GetCourseOfferings(ctx, newID)[termID]; !ok {
+		t.Errorf("offering in %s did not move to %s", termID, newID)
+	}
+	if n := len(GetEnrolments(ctx, newID)); n != 2 {
+		t.Errorf("GetEnrolments(%s) returned %d enrolments, want 2", newID, n)
+	}
+	if id, ok := GetCourseAlias(ctx, courseID); !ok || id != newID {
+		t.Errorf("GetCourseAlias(%s) = %q, %v, want %q", courseID, id, ok, newID)
+	}
+
+	if err := RenameCourse(ctx, courseID, testID("X"), false, "test"); !errors.Is(err, ErrNoCourse) {
+		t.Errorf("RenameCourse() of a missing course error = %v, want ErrNoCourse", err)
+	}
+}
+
+func TestDuplicateCourseID(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID, newID, otherID := testID("C"), testID("N"), testID("O")
+
+	if err := AddCourse(ctx, courseID, "Duplicate test", "", 0, ""); err != nil {
+		t.Fatalf("AddCourse() error = %v", err)
+	}
+	t.Cleanup(func() { DeleteCourse(ctx, courseID); DeleteCourse(ctx, newID); DeleteCourse(ctx, otherID) })
+
+	if err := AddCourse(ctx, courseID, "Duplicate test", "", 0, ""); !errors.Is(err, ErrDuplicateCourseID) {
+		t.Errorf("AddCourse() of a course ID in use error = %v, want ErrDuplicateCourseID", err)
+	}
+
+	// The former ID of a renamed course stays in use by it
+	if err := RenameCourse(ctx, courseID, newID, true, "test"); err != nil {
+		t.Fatalf("RenameCourse() error = %v", err)
+	}
+	if err := AddCourse(ctx, courseID, "Duplicate test", "", 0, ""); !errors.Is(err, ErrDuplicateCourseID) {
+		t.Errorf("AddCourse() of a former course ID error = %v, want ErrDuplicateCourseID", err)
+	}
+	if err := CloneCourse(ctx, newID, CloneInfo{CourseID: courseID, Title: "Clone", Include: map[string]bool{}}); !errors.Is(err, ErrDuplicateCourseID) {
+		t.Errorf("CloneCourse() to a former course ID error = %v, want ErrDuplicateCourseID", err)
+	}
+
+	if err := AddCourse(ctx, otherID, "Duplicate test", "", 0, ""); err != nil {
+		t.Fatalf("AddCourse() error = %v", err)
+	}
+	for _, id := range []string{newID, courseID} {
+		if err := RenameCourse(ctx, otherID, id, false, "test"); !errors.Is(err, ErrDuplicateCourseID) {
+			t.Errorf("RenameCourse() to %s error = %v, want ErrDuplicateCourseID", id, err)
+		}
+	}
+
+	// A course may be renamed back to its former ID
+	if err := RenameCourse(ctx, newID, courseID, false, "test"); err != nil {
+		t.Errorf("RenameCourse() back to the former ID error = %v", err)
+	}
+}
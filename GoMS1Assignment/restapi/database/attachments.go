@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AttachmentInfo struct for the json of a file attached to a course, such as its syllabus
+type AttachmentInfo struct {
+	CourseID    string `json:"CourseID"`
+	FileName    string `json:"FileName"`
+	ContentType string `json:"ContentType"`
+	Size        int64  `json:"Size"`     // bytes
+	BlobKey     string `json:"-"`        // key of the file in the blob store
+	Uploaded    string `json:"Uploaded"` // YYYY-MM-DD HH:MM:SS
+}
+
+// attachmentColumns are the columns selected for an AttachmentInfo, in the order scanned by queryAttachments.
+const attachmentColumns = "AttachmentID, CourseID, FileName, ContentType, Size, BlobKey, Created_DT"
+
+// AddAttachment implements the sql operations to insert an attachment of a course as invoked
+// by the REST API. Returns the ID of the attachment, or 0 if the operation failed.
+func AddAttachment(ctx context.Context, attachment AttachmentInfo) int64 {
+	defer observeCall("AddAttachment", time.Now())
+
+	query := "INSERT INTO CourseAttachments (CourseID, FileName, ContentType, Size, BlobKey, Created_DT) VALUES (?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddAttachment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddAttachment")
+
+	result, err := DB.ExecContext(ctx, query, attachment.CourseID, attachment.FileName, attachment.ContentType,
+		attachment.Size, attachment.BlobKey, time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		panic(fmt.Errorf("error getting id of sql insert: %w", err))
+	}
+	return id
+}
+
+// DeleteAttachment implements the sql operations to delete an attachment as invoked by the REST API.
+// Returns error type.
+func DeleteAttachment(ctx context.Context, attachmentID int64) (err error) {
+	defer observeCall("DeleteAttachment", time.Now())
+
+	query := "DELETE FROM CourseAttachments WHERE AttachmentID=?"
+
+	ctx, span := startSpan(ctx, "DeleteAttachment", query)
+	defer span.End()
+	defer recoverError(ctx, "DeleteAttachment", &err)
+
+	_, err = DB.ExecContext(ctx, query, attachmentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+	return nil
+}
+
+// GetAttachment implements the sql operations to retrieve an attachment, keyed by attachment
+// ID, as invoked by the REST API.
+func GetAttachment(ctx context.Context, attachmentID int64) map[string]AttachmentInfo {
+	defer observeCall("GetAttachment", time.Now())
+
+	query := "SELECT " + attachmentColumns + " FROM CourseAttachments WHERE AttachmentID=?"
+
+	ctx, span := startSpan(ctx, "GetAttachment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAttachment")
+
+	return queryAttachments(ctx, query, attachmentID)
+}
+
+// GetAttachments implements the sql operations to retrieve the attachments of a course,
+// keyed by attachment ID, as invoked by the REST API.
+func GetAttachments(ctx context.Context, courseID string) map[string]AttachmentInfo {
+	defer observeCall("GetAttachments", time.Now())
+
+	query := "SELECT " + attachmentColumns + " FROM CourseAttachments WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetAttachments", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAttachments")
+
+	return queryAttachments(ctx, query, courseID)
+}
+
+// queryAttachments runs a select of attachment columns and returns the attachments keyed by
+// attachment ID. It panics on error to be recovered by the calling function.
+func queryAttachments(ctx context.Context, query string, args ...interface{}) map[string]AttachmentInfo {
+	// Instantiate attachments
+	var attachments = make(map[string]AttachmentInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var attachmentID string
+		var a AttachmentInfo
+		err := results.Scan(&attachmentID, &a.CourseID, &a.FileName, &a.ContentType, &a.Size, &a.BlobKey, &a.Uploaded)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		attachments[attachmentID] = a
+	}
+	return attachments
+}
@@ -0,0 +1,268 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Statuses of the attendance of a student at a class. Late students attended the class;
+// excused absences are not counted towards the attendance percentage of a student.
+const (
+	Present = "present"
+	Absent  = "absent"
+	Late    = "late"
+	Excused = "excused"
+)
+
+// ClassInfo struct for the json of a dated class of a course
+type ClassInfo struct {
+	CourseID  string `json:"CourseID"`
+	SessionID int64  `json:"SessionID,omitempty"` // the weekly session the class is held in, if any
+	Date      string `json:"Date"`                // YYYY-MM-DD
+	Topic     string `json:"Topic"`
+}
+
+// AttendanceInfo struct for the json of the attendance of a student at a class
+type AttendanceInfo struct {
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+}
+
+// AttendanceTally is the number of classes of a course a student was marked in with each status.
+type AttendanceTally struct {
+	CourseID        string
+	TermID          string // term of the enrolment, "" if outside any term
+	StudentID       string
+	Name            string
+	EnrolmentStatus string
+	Present         int
+	Absent          int
+	Late            int
+	Excused         int
+}
+
+// classColumns are the columns selected for a ClassInfo, in the order scanned by queryClasses.
+const classColumns = "ClassID, CourseID, COALESCE(SessionID, 0), ClassDate, Topic"
+
+// AddClass implements the sql operations to insert a new class as invoked by the REST API.
+// Returns the ID of the new class.
+func AddClass(ctx context.Context, class ClassInfo) int64 {
+	defer observeCall("AddClass", time.Now())
+
+	query := "INSERT INTO Classes (CourseID, SessionID, ClassDate, Topic, Created_DT, LastModified_DT) " +
+		"VALUES (?, NULLIF(?, 0), ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddClass", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddClass")
+
+	result, err := DB.ExecContext(ctx, query, class.CourseID, class.SessionID, class.Date, class.Topic, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		panic(fmt.Errorf("error getting id of sql insert: %w", err))
+	}
+	return id
+}
+
+// UpdateClass implements the sql operations to update a class as invoked by the REST API.
+func UpdateClass(ctx context.Context, classID int64, class ClassInfo) {
+	defer observeCall("UpdateClass", time.Now())
+
+	query := "UPDATE Classes SET SessionID=NULLIF(?, 0), ClassDate=?, Topic=?, LastModified_DT=? WHERE ClassID=?"
+
+	ctx, span := startSpan(ctx, "UpdateClass", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateClass")
+
+	_, err := DB.ExecContext(ctx, query, class.SessionID, class.Date, class.Topic, time.Now(), classID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
+
+// DeleteClass implements the sql operations to delete a class, with its attendance records,
+// as invoked by the REST API.
+func DeleteClass(ctx context.Context, classID int64) {
+	defer observeCall("DeleteClass", time.Now())
+
+	query := "DELETE FROM Classes WHERE ClassID=?"
+
+	ctx, span := startSpan(ctx, "DeleteClass", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteClass")
+
+	_, err := DB.ExecContext(ctx, query, classID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetClass implements the sql operations to retrieve a class, keyed by class ID, as invoked
+// by the REST API.
+func GetClass(ctx context.Context, classID int64) map[string]ClassInfo {
+	defer observeCall("GetClass", time.Now())
+
+	query := "SELECT " + classColumns + " FROM Classes WHERE ClassID=?"
+
+	ctx, span := startSpan(ctx, "GetClass", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetClass")
+
+	return queryClasses(ctx, query, classID)
+}
+
+// GetClasses implements the sql operations to retrieve the classes of a course, keyed by
+// class ID, as invoked by the REST API.
+func GetClasses(ctx context.Context, courseID string) map[string]ClassInfo {
+	defer observeCall("GetClasses", time.Now())
+
+	query := "SELECT " + classColumns + " FROM Classes WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetClasses", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetClasses")
+
+	return queryClasses(ctx, query, courseID)
+}
+
+// queryClasses runs a select of classColumns and returns the classes keyed by class ID.
+// It panics on error to be recovered by the calling function.
+func queryClasses(ctx context.Context, query string, args ...interface{}) map[string]ClassInfo {
+	// Instantiate classes
+	var classes = make(map[string]ClassInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var classID int64
+		var class ClassInfo
+		err := results.Scan(&classID, &class.CourseID, &class.SessionID, &class.Date, &class.Topic)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		classes[strconv.FormatInt(classID, 10)] = class
+	}
+	return classes
+}
+
+// SetAttendance implements the sql operations to mark the attendance, keyed by student ID,
+// of students at a class, replacing any existing marks of those students, as invoked by
+// the REST API.
+func SetAttendance(ctx context.Context, classID int64, attendance map[string]string) {
+	defer observeCall("SetAttendance", time.Now())
+
+	query := "INSERT INTO Attendance (ClassID, StudentID, Status, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE Status=VALUES(Status), LastModified_DT=VALUES(LastModified_DT)"
+
+	ctx, span := startSpan(ctx, "SetAttendance", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetAttendance")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error preparing sql insert: %w", err))
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for studentID, status := range attendance {
+		if _, err := stmt.ExecContext(ctx, classID, studentID, status, now, now); err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// GetAttendance implements the sql operations to retrieve the attendance of students at a
+// class, keyed by student ID, as invoked by the REST API.
+func GetAttendance(ctx context.Context, classID int64) map[string]AttendanceInfo {
+	defer observeCall("GetAttendance", time.Now())
+
+	query := "SELECT a.StudentID, s.Name, a.Status FROM Attendance a JOIN Students s ON s.StudentID = a.StudentID " +
+		"WHERE a.ClassID=?"
+
+	ctx, span := startSpan(ctx, "GetAttendance", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAttendance")
+
+	// Instantiate attendance
+	var attendance = make(map[string]AttendanceInfo)
+
+	results, err := DB.QueryContext(ctx, query, classID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var studentID string
+		var a AttendanceInfo
+		if err := results.Scan(&studentID, &a.Name, &a.Status); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		attendance[studentID] = a
+	}
+	return attendance
+}
+
+// GetAttendanceTallies implements the sql operations to count the classes each student
+// enrolled in, or who has completed, a course was marked in with each status, as invoked
+// by the REST API. The tallies are limited to the course or student given, if not "".
+// A class held in a weekly session is taken by the enrolments in the term of the session,
+// and other classes by the enrolments outside any term, so a student enrolled in the course
+// in more than one term has a tally for each term.
+func GetAttendanceTallies(ctx context.Context, courseID string, studentID string) []AttendanceTally {
+	defer observeCall("GetAttendanceTallies", time.Now())
+
+	query := "SELECT c.CourseID, e.TermID, a.StudentID, s.Name, e.Status, SUM(a.Status=?), SUM(a.Status=?), " +
+		"SUM(a.Status=?), SUM(a.Status=?) FROM Attendance a JOIN Classes c ON c.ClassID = a.ClassID " +
+		"LEFT JOIN Sessions ss ON ss.SessionID = c.SessionID " +
+		"JOIN Students s ON s.StudentID = a.StudentID " +
+		"JOIN Enrolments e ON e.CourseID = c.CourseID AND e.TermID = COALESCE(ss.Term, '') " +
+		"AND e.StudentID = a.StudentID AND e.Status IN (?, ?) " +
+		"WHERE (c.CourseID=? OR ?='') AND (a.StudentID=? OR ?='') " +
+		"GROUP BY c.CourseID, e.TermID, a.StudentID, s.Name, e.Status ORDER BY c.CourseID, e.TermID, a.StudentID"
+
+	ctx, span := startSpan(ctx, "GetAttendanceTallies", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAttendanceTallies")
+
+	// Instantiate tallies
+	var tallies []AttendanceTally
+
+	results, err := DB.QueryContext(ctx, query, Present, Absent, Late, Excused, Enrolled, Completed,
+		courseID, courseID, studentID, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var t AttendanceTally
+		err := results.Scan(&t.CourseID, &t.TermID, &t.StudentID, &t.Name, &t.EnrolmentStatus, &t.Present, &t.Absent, &t.Late, &t.Excused)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		tallies = append(tallies, t)
+	}
+	return tallies
+}
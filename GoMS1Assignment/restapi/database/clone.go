@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Related data of a course which may be copied to a clone of the course. Enrolments,
+// sessions, offerings, scores and attendance belong to the runs of the course and are
+// never copied.
+const (
+	CopyTags          = "tags"
+	CopyInstructors   = "instructors"
+	CopyPrerequisites = "prerequisites"
+	CopyGradebook     = "gradebook" // assessments and grade scale
+	CopyAttachments   = "attachments"
+)
+
+// CloneInfo struct for a course added by CloneCourse
+type CloneInfo struct {
+	CourseID    string
+	Title       string
+	Description string
+	Capacity    int
+	Department  string           // department ID, "" for none
+	Include     map[string]bool  // related data copied from the course, keyed by CopyTags etc.
+	Attachments []AttachmentInfo // attachments added, with copies of the files of the course
+	ClonedBy    string           // label of the API key used
+}
+
+// CloneCourse implements the sql operations to add a draft course copied from another, with
+// the related data of the course given in clone.Include, as invoked by the REST API. The
+// clone is recorded in the history of the new course.
+// The ID of the clone must be canonical. Returns ErrNoCourse if there is no course with the
+// ID given, ErrDuplicateCourseID if the ID of the clone is in use, and error type.
+func CloneCourse(ctx context.Context, courseID string, clone CloneInfo) (err error) {
+	defer observeCall("CloneCourse", time.Now())
+
+	query := "INSERT INTO Courses (CourseID, CanonicalID, CourseTitle, Description, Capacity, DepartmentID, Status, Created_DT, LastModified_DT) " +
+		"SELECT ?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ? FROM Courses WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "CloneCourse", query)
+	defer span.End()
+	defer recoverError(ctx, "CloneCourse", &err)
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if lockCourseAlias(ctx, tx, clone.CourseID) != "" {
+		return ErrDuplicateCourseID
+	}
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, query, clone.CourseID, clone.CourseID, clone.Title, clone.Description, clone.Capacity,
+		clone.Department, Draft, now, now, courseID)
+	if duplicateKey(err) {
+		return ErrDuplicateCourseID
+	} else if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql insert: %w", err))
+	} else if n == 0 {
+		return ErrNoCourse
+	}
+
+	// The related data copied for each of clone.Include
+	copies := []struct {
+		include string
+		query   string
+		args    []interface{}
+	}{
+		{CopyTags, "INSERT INTO CourseTags (CourseID, Tag, Created_DT) SELECT ?, Tag, ? FROM CourseTags WHERE CourseID=?",
+			[]interface{}{clone.CourseID, now, courseID}},
+		{CopyInstructors, "INSERT INTO CourseInstructors (CourseID, InstructorID, Role, Created_DT, LastModified_DT) " +
+			"SELECT ?, InstructorID, Role, ?, ? FROM CourseInstructors WHERE CourseID=?",
+			[]interface{}{clone.CourseID, now, now, courseID}},
+		{CopyPrerequisites, "INSERT INTO Prerequisites (CourseID, PrerequisiteID, Created_DT) " +
+			"SELECT ?, PrerequisiteID, ? FROM Prerequisites WHERE CourseID=?",
+			[]interface{}{clone.CourseID, now, courseID}},
+		{CopyGradebook, "INSERT INTO Assessments (CourseID, Name, Weight, MaxScore, Created_DT, LastModified_DT) " +
+			"SELECT ?, Name, Weight, MaxScore, ?, ? FROM Assessments WHERE CourseID=?",
+			[]interface{}{clone.CourseID, now, now, courseID}},
+		{CopyGradebook, "INSERT INTO GradeBoundaries (CourseID, Grade, MinPercent) " +
+			"SELECT ?, Grade, MinPercent FROM GradeBoundaries WHERE CourseID=?",
+			[]interface{}{clone.CourseID, courseID}},
+	}
+	for _, c := range copies {
+		if !clone.Include[c.include] {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, c.query, c.args...); err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	for _, attachment := range clone.Attachments {
+		_, err = tx.ExecContext(ctx, "INSERT INTO CourseAttachments (CourseID, FileName, ContentType, Size, BlobKey, Created_DT) "+
+			"VALUES (?, ?, ?, ?, ?, ?)", clone.CourseID, attachment.FileName, attachment.ContentType, attachment.Size,
+			attachment.BlobKey, now)
+		if err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO CourseHistory (CourseID, FromStatus, ToStatus, Comment, ChangedBy, Created_DT) "+
+		"VALUES (?, ?, ?, ?, ?, ?)", clone.CourseID, Draft, Draft, "Cloned from "+courseID, clone.ClonedBy, now)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return nil
+}
@@ -1,186 +1,413 @@
-// Package database implements the connection to the database server at the designated port.
-// It performs the CRUD operations to the database as invoked by the REST API.
-package database
-
-import (
-	"database/sql"
-	"fmt"
-	"time"
-
-	_ "github.com/go-sql-driver/mysql"
-)
-
-// courseInfo struct for the json
-type courseInfo struct {
-	Title string `json:"Title"`
-}
-
-// Config struct to maintain DB configuration properties
-type Config struct {
-	ServerName string
-	User       string
-	Password   string
-	DB         string
-}
-
-// Connector variable used for CRUD operations
-var DB *sql.DB
-
-// Connect creates the database connection
-func Connect(connectionString string) error {
-	var err error
-	DB, err = sql.Open("mysql", connectionString)
-	if err != nil {
-		return err
-	} else {
-		fmt.Println("Database opened")
-	}
-	return nil
-}
-
-// GetConnectionString formats the database connection string and returns it.
-func GetConnectionString(config Config) string {
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s)/%s", config.User, config.Password, config.ServerName, config.DB)
-	return connectionString
-}
-
-// AddCourse implements the sql operations to insert a new course as invoked by the REST API.
-func AddCourse(courseID string, courseTitle string) {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	stmt, err := DB.Prepare("INSERT INTO Courses (CourseID, CourseTitle, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?)")
-	if err != nil {
-		panic("error preparing sql insert")
-	}
-
-	_, err = stmt.Exec(courseID, courseTitle, time.Now(), time.Now())
-	if err != nil {
-		panic("error executing sql insert")
-	}
-}
-
-// UpdateCourse implements the sql operations to update a course as invoked by the REST API.
-func UpdateCourse(courseID string, courseTitle string) {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	stmt, err := DB.Prepare("UPDATE Courses SET CourseTitle=?, LastModified_DT=? WHERE CourseID=?")
-	if err != nil {
-		panic("error preparing sql update")
-	}
-
-	_, err = stmt.Exec(courseTitle, time.Now(), courseID)
-	if err != nil {
-		panic("error executing sql update")
-	}
-}
-
-// DeleteCourse implements the sql operations to delete a course as invoked by the REST API.
-func DeleteCourse(courseID string) {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	stmt, err := DB.Prepare("DELETE FROM Courses WHERE CourseID=?")
-	if err != nil {
-		panic("error preparing sql update")
-	}
-
-	_, err = stmt.Exec(courseID)
-	if err != nil {
-		panic("error executing sql update")
-	}
-}
-
-// GetCourse implements the sql operations to retrieve a course as invoked by the REST API.
-func GetCourse(courseID string) map[string]courseInfo {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	var courseTitle string
-
-	// Instantiate courses
-	var courses = make(map[string]courseInfo)
-
-	query := "SELECT CourseID, CourseTitle FROM Courses WHERE CourseID=?"
-
-	results, err := DB.Query(query, courseID)
-	if err != nil {
-		panic("error executing sql select")
-	} else {
-		if results.Next() {
-			err := results.Scan(&courseID, &courseTitle)
-			if err != nil {
-				panic("error getting results from sql select")
-			}
-			courses[courseID] = courseInfo{courseTitle}
-		}
-		return courses
-	}
-}
-
-// GetAllCourses implements the sql operations to retrieve all courses as invoked by the REST API.
-func GetAllCourses() map[string]courseInfo {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	var courseID string
-	var courseTitle string
-
-	// Instantiate courses
-	var courses = make(map[string]courseInfo)
-
-	query := "SELECT CourseID, CourseTitle FROM Courses"
-
-	results, err := DB.Query(query)
-	if err != nil {
-		panic("error executing sql select")
-	} else {
-		for results.Next() {
-			err := results.Scan(&courseID, &courseTitle)
-			if err != nil {
-				panic("error getting results from sql select")
-			}
-
-			courses[courseID] = courseInfo{courseTitle}
-		}
-		return courses
-	}
-}
-
-// ValidKey implements the sql operations to retrieve the access key and validate if the
-// key provided is valid. Returns a bool.
-func ValidKey(key string) bool {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	query := "SELECT KeyID FROM APIKeys WHERE KeyID=?"
-
-	results, err := DB.Query(query, key)
-	if err != nil {
-		panic("error executing sql select")
-	} else {
-		if results.Next() {
-			return true
-		}
-	}
-	return false
-}
+// Package database implements the connection to the database server at the designated port.
+// It performs the CRUD operations to the database as invoked by the REST API.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// courseInfo struct for the json
+type courseInfo struct {
+	Title       string                    `json:"Title"`
+	Description string                    `json:"Description,omitempty"` // Markdown
+	Capacity    int                       `json:"Capacity"`              // 0 for unlimited
+	Department  string                    `json:"Department,omitempty"`  // department ID
+	Status      string                    `json:"Status"`
+	Tags        []string                  `json:"Tags,omitempty"`
+	Instructors map[string]AssignmentInfo `json:"Instructors,omitempty"`
+}
+
+// CourseFilter selects the courses retrieved by GetAllCourses. Empty fields match all courses.
+type CourseFilter struct {
+	Instructor string // ID of an instructor assigned to the course
+	Department string // ID of the department of the course
+	Tag        string // a tag of the course
+	Status     string // status of the course
+}
+
+// courseColumns are the columns selected for a courseInfo, in the order scanned by queryCourses.
+const courseColumns = "CourseID, CourseTitle, COALESCE(Description, ''), Capacity, COALESCE(DepartmentID, ''), Status"
+
+// Config struct to maintain DB configuration properties
+type Config struct {
+	ServerName string
+	User       string
+	Password   string
+	DB         string
+}
+
+// Connector variable used for CRUD operations
+var DB *sql.DB
+
+// ErrNoCourse is returned by database functions which change a course when there is no
+// course with the ID given, e.g. as it was deleted by another request.
+var ErrNoCourse = errors.New("no course found")
+
+// ErrDuplicateCourseID is returned by database functions which add or rename a course when
+// the course ID is in use by another course, or another course was renamed from it.
+var ErrDuplicateCourseID = errors.New("duplicate course ID")
+
+// ErrNoDepartment is returned by UpdateCourse when there is no department with the ID given,
+// e.g. as it was deleted by another request.
+var ErrNoDepartment = errors.New("no department found")
+
+// Connect creates the database connection
+func Connect(connectionString string) error {
+	var err error
+	DB, err = sql.Open("mysql", connectionString)
+	if err != nil {
+		return err
+	} else {
+		slog.Info("database opened")
+	}
+	return nil
+}
+
+// GetConnectionString formats the database connection string and returns it.
+func GetConnectionString(config Config) string {
+	connectionString := fmt.Sprintf("%s:%s@tcp(%s)/%s", config.User, config.Password, config.ServerName, config.DB)
+	return connectionString
+}
+
+// AddCourse implements the sql operations to insert a new draft course as invoked by the REST API.
+// The course has no department if departmentID is "". The course ID must be canonical.
+// Returns ErrDuplicateCourseID if the course ID is in use, and error type.
+func AddCourse(ctx context.Context, courseID string, courseTitle string, description string, capacity int, departmentID string) (err error) {
+	defer observeCall("AddCourse", time.Now())
+
+	query := "INSERT INTO Courses (CourseID, CanonicalID, CourseTitle, Description, Capacity, DepartmentID, Status, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddCourse", query)
+	defer span.End()
+	defer recoverError(ctx, "AddCourse", &err)
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if lockCourseAlias(ctx, tx, courseID) != "" {
+		return ErrDuplicateCourseID
+	}
+
+	_, err = tx.ExecContext(ctx, query, courseID, courseID, courseTitle, description, capacity, departmentID, Draft,
+		time.Now(), time.Now())
+	if duplicateKey(err) {
+		return ErrDuplicateCourseID
+	} else if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return nil
+}
+
+// UpdateCourse implements the sql operations to update a course as invoked by the REST API.
+// The course has no department if departmentID is "". Returns ErrNoDepartment if there is no
+// such department, and error type.
+func UpdateCourse(ctx context.Context, courseID string, courseTitle string, description string, capacity int, departmentID string) (err error) {
+	defer observeCall("UpdateCourse", time.Now())
+
+	query := "UPDATE Courses SET CourseTitle=?, Description=NULLIF(?, ''), Capacity=?, DepartmentID=NULLIF(?, ''), " +
+		"LastModified_DT=? WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "UpdateCourse", query)
+	defer span.End()
+	defer recoverError(ctx, "UpdateCourse", &err)
+
+	stmt, err := DB.PrepareContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error preparing sql update: %w", err))
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, courseTitle, description, capacity, departmentID, time.Now(), courseID)
+	if missingReference(err) {
+		return ErrNoDepartment
+	} else if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	return nil
+}
+
+// DeleteCourse implements the sql operations to delete a course as invoked by the REST API.
+// Returns error type.
+func DeleteCourse(ctx context.Context, courseID string) (err error) {
+	defer observeCall("DeleteCourse", time.Now())
+
+	query := "DELETE FROM Courses WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "DeleteCourse", query)
+	defer span.End()
+	defer recoverError(ctx, "DeleteCourse", &err)
+
+	stmt, err := DB.PrepareContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error preparing sql update: %w", err))
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	return nil
+}
+
+// GetCourse implements the sql operations to retrieve a course as invoked by the REST API.
+func GetCourse(ctx context.Context, courseID string) map[string]courseInfo {
+	defer observeCall("GetCourse", time.Now())
+
+	query := "SELECT " + courseColumns + " FROM Courses WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetCourse", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourse")
+
+	courses := queryCourses(ctx, query, courseID)
+
+	// Attach the instructors assigned to the course
+	assignments := queryAssignments(ctx, "SELECT ci.CourseID, ci.InstructorID, i.Name, ci.Role FROM CourseInstructors ci "+
+		"JOIN Instructors i ON i.InstructorID = ci.InstructorID WHERE ci.CourseID=?", courseID)
+
+	// Attach the tags of the course
+	tags := queryTags(ctx, "SELECT CourseID, Tag FROM CourseTags WHERE CourseID=? ORDER BY Tag", courseID)
+	return withTags(withInstructors(courses, assignments), tags)
+}
+
+// GetAllCourses implements the sql operations to retrieve all courses matching the filter
+// as invoked by the REST API.
+func GetAllCourses(ctx context.Context, filter CourseFilter) map[string]courseInfo {
+	defer observeCall("GetAllCourses", time.Now())
+
+	query := "SELECT " + courseColumns + " FROM Courses"
+
+	var where []string
+	var args []interface{}
+	if filter.Instructor != "" {
+		where = append(where, "CourseID IN (SELECT CourseID FROM CourseInstructors WHERE InstructorID=?)")
+		args = append(args, filter.Instructor)
+	}
+	if filter.Department != "" {
+		where = append(where, "DepartmentID=?")
+		args = append(args, filter.Department)
+	}
+	if filter.Tag != "" {
+		where = append(where, "CourseID IN (SELECT CourseID FROM CourseTags WHERE Tag=?)")
+		args = append(args, filter.Tag)
+	}
+	if filter.Status != "" {
+		where = append(where, "Status=?")
+		args = append(args, filter.Status)
+	}
+	if len(where) != 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	ctx, span := startSpan(ctx, "GetAllCourses", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllCourses")
+
+	courses := queryCourses(ctx, query, args...)
+
+	// Attach the instructors assigned to each course
+	assignments := queryAssignments(ctx, "SELECT ci.CourseID, ci.InstructorID, i.Name, ci.Role FROM CourseInstructors ci "+
+		"JOIN Instructors i ON i.InstructorID = ci.InstructorID")
+
+	// Attach the tags of each course
+	tags := queryTags(ctx, "SELECT CourseID, Tag FROM CourseTags ORDER BY Tag")
+	return withTags(withInstructors(courses, assignments), tags)
+}
+
+// GetCourseIDs implements the sql operations to retrieve the IDs of all courses in any status,
+// exactly as stored, sorted by course ID.
+func GetCourseIDs(ctx context.Context) []string {
+	defer observeCall("GetCourseIDs", time.Now())
+
+	query := "SELECT CourseID FROM Courses ORDER BY CourseID"
+
+	ctx, span := startSpan(ctx, "GetCourseIDs", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourseIDs")
+
+	return queryCourseIDs(ctx, query)
+}
+
+// GetLegacyCourseIDs implements the sql operations to retrieve the IDs of the courses without
+// a canonical ID, added before course IDs were canonicalised, sorted by course ID.
+func GetLegacyCourseIDs(ctx context.Context) []string {
+	defer observeCall("GetLegacyCourseIDs", time.Now())
+
+	query := "SELECT CourseID FROM Courses WHERE CanonicalID IS NULL ORDER BY CourseID"
+
+	ctx, span := startSpan(ctx, "GetLegacyCourseIDs", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetLegacyCourseIDs")
+
+	return queryCourseIDs(ctx, query)
+}
+
+// SetCanonicalID implements the sql operations to record that the ID of a course added
+// before course IDs were canonicalised is canonical. Returns ErrDuplicateCourseID if
+// another course has the ID as its canonical ID, and error type.
+func SetCanonicalID(ctx context.Context, courseID string) (err error) {
+	defer observeCall("SetCanonicalID", time.Now())
+
+	query := "UPDATE Courses SET CanonicalID=CourseID WHERE CourseID=? AND CanonicalID IS NULL"
+
+	ctx, span := startSpan(ctx, "SetCanonicalID", query)
+	defer span.End()
+	defer recoverError(ctx, "SetCanonicalID", &err)
+
+	_, err = DB.ExecContext(ctx, query, courseID)
+	if duplicateKey(err) {
+		return ErrDuplicateCourseID
+	} else if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	return nil
+}
+
+// queryCourseIDs runs a select of course IDs and returns them in the order selected.
+// It panics on error to be recovered by the calling function.
+func queryCourseIDs(ctx context.Context, query string, args ...interface{}) []string {
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	var courseIDs []string
+	for results.Next() {
+		var courseID string
+		if err := results.Scan(&courseID); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		courseIDs = append(courseIDs, courseID)
+	}
+	return courseIDs
+}
+
+// queryCourses runs a select of course columns and returns the courses keyed by course ID.
+// It panics on error to be recovered by the calling function.
+func queryCourses(ctx context.Context, query string, args ...interface{}) map[string]courseInfo {
+	// Instantiate courses
+	var courses = make(map[string]courseInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var courseID string
+		var course courseInfo
+		err := results.Scan(&courseID, &course.Title, &course.Description, &course.Capacity, &course.Department, &course.Status)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		courses[courseID] = course
+	}
+	return courses
+}
+
+// withInstructors sets the instructors of each course from the assignments keyed by course ID.
+func withInstructors(courses map[string]courseInfo, assignments map[string]map[string]AssignmentInfo) map[string]courseInfo {
+	for courseID, course := range courses {
+		course.Instructors = assignments[courseID]
+		courses[courseID] = course
+	}
+	return courses
+}
+
+// ValidKey implements the sql operations to retrieve the access key and validate if the
+// key provided is valid. Returns the label of the key for logging, and a bool.
+func ValidKey(ctx context.Context, key string) (string, bool) {
+	defer observeCall("ValidKey", time.Now())
+
+	var label string
+
+	query := "SELECT COALESCE(Label, '') FROM APIKeys WHERE KeyID=?"
+
+	ctx, span := startSpan(ctx, "ValidKey", query)
+	defer span.End()
+	defer recoverPanic(ctx, "ValidKey")
+
+	results, err := DB.QueryContext(ctx, query, key)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	} else {
+		defer results.Close()
+		if results.Next() {
+			results.Scan(&label)
+			return label, true
+		}
+	}
+	return "", false
+}
+
+// recoverPanic recovers from a panic raised by a failed sql operation, records the
+// error on the span in ctx and logs it with the name of the database function.
+// It must be deferred directly:
+//
+//	defer recoverPanic(ctx, "AddCourse")
+func recoverPanic(ctx context.Context, function string) {
+	if err := recover(); err != nil {
+		recordFailure(ctx, function, err)
+	}
+}
+
+// recoverError is recoverPanic for database functions which return an error, and sets
+// *err to the error recovered. It must be deferred directly:
+//
+//	defer recoverError(ctx, "DeleteCourse", &err)
+func recoverError(ctx context.Context, function string, err *error) {
+	if r := recover(); r != nil {
+		*err = recordFailure(ctx, function, r)
+	}
+}
+
+// recordFailure records the error recovered from a panic in the database function on the
+// span in ctx, logs it and returns it.
+func recordFailure(ctx context.Context, function string, recovered interface{}) error {
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, function+" failed")
+
+	slog.Error("database operation failed", "function", function, "error", err,
+		"trace_id", span.SpanContext().TraceID().String())
+	return err
+}
+
+// duplicateKey reports whether err is the error of MySQL for a duplicate key of a unique index.
+func duplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// missingReference reports whether err is the error of MySQL for a foreign key which
+// references no row, e.g. as it was deleted by another request.
+func missingReference(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1452
+}
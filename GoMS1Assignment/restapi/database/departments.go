@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DepartmentInfo struct for the json
+type DepartmentInfo struct {
+	Name    string `json:"Name"`
+	Courses int    `json:"Courses"` // number of courses in the department
+}
+
+// AddDepartment implements the sql operations to insert a new department as invoked by the REST API.
+func AddDepartment(ctx context.Context, departmentID string, department DepartmentInfo) {
+	defer observeCall("AddDepartment", time.Now())
+
+	query := "INSERT INTO Departments (DepartmentID, Name, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddDepartment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddDepartment")
+
+	_, err := DB.ExecContext(ctx, query, departmentID, department.Name, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+}
+
+// UpdateDepartment implements the sql operations to update a department as invoked by the REST API.
+func UpdateDepartment(ctx context.Context, departmentID string, department DepartmentInfo) {
+	defer observeCall("UpdateDepartment", time.Now())
+
+	query := "UPDATE Departments SET Name=?, LastModified_DT=? WHERE DepartmentID=?"
+
+	ctx, span := startSpan(ctx, "UpdateDepartment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateDepartment")
+
+	_, err := DB.ExecContext(ctx, query, department.Name, time.Now(), departmentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
+
+// DeleteDepartment implements the sql operations to delete a department as invoked by the REST API.
+func DeleteDepartment(ctx context.Context, departmentID string) {
+	defer observeCall("DeleteDepartment", time.Now())
+
+	query := "DELETE FROM Departments WHERE DepartmentID=?"
+
+	ctx, span := startSpan(ctx, "DeleteDepartment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteDepartment")
+
+	_, err := DB.ExecContext(ctx, query, departmentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetDepartment implements the sql operations to retrieve a department as invoked by the REST API.
+func GetDepartment(ctx context.Context, departmentID string) map[string]DepartmentInfo {
+	defer observeCall("GetDepartment", time.Now())
+
+	query := "SELECT d.DepartmentID, d.Name, COUNT(c.CourseID) FROM Departments d " +
+		"LEFT JOIN Courses c ON c.DepartmentID = d.DepartmentID WHERE d.DepartmentID=? GROUP BY d.DepartmentID, d.Name"
+
+	ctx, span := startSpan(ctx, "GetDepartment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetDepartment")
+
+	return queryDepartments(ctx, query, departmentID)
+}
+
+// GetAllDepartments implements the sql operations to retrieve all departments as invoked by the REST API.
+func GetAllDepartments(ctx context.Context) map[string]DepartmentInfo {
+	defer observeCall("GetAllDepartments", time.Now())
+
+	query := "SELECT d.DepartmentID, d.Name, COUNT(c.CourseID) FROM Departments d " +
+		"LEFT JOIN Courses c ON c.DepartmentID = d.DepartmentID GROUP BY d.DepartmentID, d.Name"
+
+	ctx, span := startSpan(ctx, "GetAllDepartments", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllDepartments")
+
+	return queryDepartments(ctx, query)
+}
+
+// queryDepartments runs a select of department columns and returns the departments keyed by
+// department ID. It panics on error to be recovered by the calling function.
+func queryDepartments(ctx context.Context, query string, args ...interface{}) map[string]DepartmentInfo {
+	// Instantiate departments
+	var departments = make(map[string]DepartmentInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var departmentID string
+		var department DepartmentInfo
+		err := results.Scan(&departmentID, &department.Name, &department.Courses)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		departments[departmentID] = department
+	}
+	return departments
+}
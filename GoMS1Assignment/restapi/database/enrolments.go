@@ -0,0 +1,301 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Statuses of an enrolment. Only enrolled students take up a seat in a course;
+// once a course is full further students are waitlisted. Enrolments in a term take
+// up a seat of the offering of the course in that term, other enrolments take up a
+// seat of the course itself.
+const (
+	Enrolled   = "enrolled"
+	Waitlisted = "waitlisted"
+	Withdrawn  = "withdrawn"
+	Completed  = "completed"
+)
+
+// ErrAlreadyEnrolled is returned by Enrol when the student is enrolled, waitlisted or has
+// completed the course in the term, rather than withdrawn from it.
+var ErrAlreadyEnrolled = errors.New("student is already enrolled")
+
+// EnrolmentInfo struct for the json of the enrolment of a student in a course
+type EnrolmentInfo struct {
+	StudentID        string `json:"StudentID"`
+	Name             string `json:"Name"`
+	Term             string `json:"Term,omitempty"` // term ID, if enrolled in a term
+	EnrolledDate     string `json:"EnrolledDate"`   // YYYY-MM-DD
+	Status           string `json:"Status"`
+	WaitlistPosition int    `json:"WaitlistPosition,omitempty"` // 1 for the next student to get a seat
+}
+
+// LearnerCourseInfo struct for the json of a course a learner is or was enrolled in
+type LearnerCourseInfo struct {
+	CourseID     string `json:"CourseID"`
+	Title        string `json:"Title"`
+	Term         string `json:"Term,omitempty"` // term ID, if enrolled in a term
+	EnrolledDate string `json:"EnrolledDate"`   // YYYY-MM-DD
+	Status       string `json:"Status"`
+}
+
+// GetEnrolments implements the sql operations to retrieve the enrolments of a course in every
+// term, ordered by when their status was last changed, as invoked by the REST API.
+func GetEnrolments(ctx context.Context, courseID string) []EnrolmentInfo {
+	defer observeCall("GetEnrolments", time.Now())
+
+	query := "SELECT e.StudentID, s.Name, e.TermID, e.EnrolledDate, e.Status FROM Enrolments e " +
+		"JOIN Students s ON s.StudentID = e.StudentID WHERE e.CourseID=? ORDER BY e.LastModified_DT"
+
+	ctx, span := startSpan(ctx, "GetEnrolments", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetEnrolments")
+
+	return queryEnrolments(ctx, query, courseID)
+}
+
+// GetEnrolment implements the sql operations to retrieve the enrolment of a student in a
+// course in the term given, or outside any term if termID is "", keyed by student ID, as
+// invoked by the REST API.
+func GetEnrolment(ctx context.Context, courseID string, termID string, studentID string) map[string]EnrolmentInfo {
+	defer observeCall("GetEnrolment", time.Now())
+
+	// All enrolments of the course in the term are needed to number the waitlist
+	query := "SELECT e.StudentID, s.Name, e.TermID, e.EnrolledDate, e.Status FROM Enrolments e " +
+		"JOIN Students s ON s.StudentID = e.StudentID WHERE e.CourseID=? AND e.TermID=? ORDER BY e.LastModified_DT"
+
+	ctx, span := startSpan(ctx, "GetEnrolment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetEnrolment")
+
+	var enrolment = make(map[string]EnrolmentInfo)
+	for _, e := range queryEnrolments(ctx, query, courseID, termID) {
+		if e.StudentID == studentID {
+			enrolment[studentID] = e
+		}
+	}
+	return enrolment
+}
+
+// queryEnrolments runs a select of enrolment columns, ordered by when the status was last
+// changed, and numbers the waitlisted students of each term in that order. It panics on
+// error to be recovered by the calling function.
+func queryEnrolments(ctx context.Context, query string, args ...interface{}) []EnrolmentInfo {
+	// Instantiate enrolments
+	enrolments := []EnrolmentInfo{}
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	positions := make(map[string]int) // waitlist length by term
+	for results.Next() {
+		var enrolment EnrolmentInfo
+		err := results.Scan(&enrolment.StudentID, &enrolment.Name, &enrolment.Term, &enrolment.EnrolledDate, &enrolment.Status)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		if enrolment.Status == Waitlisted {
+			positions[enrolment.Term]++
+			enrolment.WaitlistPosition = positions[enrolment.Term]
+		}
+		enrolments = append(enrolments, enrolment)
+	}
+	return enrolments
+}
+
+// GetLearnerCourses implements the sql operations to retrieve the courses a student is or
+// was enrolled in, in every term, ordered by course and term, as invoked by the REST API.
+func GetLearnerCourses(ctx context.Context, studentID string) []LearnerCourseInfo {
+	defer observeCall("GetLearnerCourses", time.Now())
+
+	query := "SELECT e.CourseID, c.CourseTitle, e.TermID, e.EnrolledDate, e.Status FROM Enrolments e " +
+		"JOIN Courses c ON c.CourseID = e.CourseID WHERE e.StudentID=? ORDER BY e.CourseID, e.TermID"
+
+	ctx, span := startSpan(ctx, "GetLearnerCourses", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetLearnerCourses")
+
+	// Instantiate courses
+	courses := []LearnerCourseInfo{}
+
+	results, err := DB.QueryContext(ctx, query, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var course LearnerCourseInfo
+		err := results.Scan(&course.CourseID, &course.Title, &course.Term, &course.EnrolledDate, &course.Status)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		courses = append(courses, course)
+	}
+	return courses
+}
+
+// Enrol implements the sql operations to enrol a student in a course, in the term given if
+// not "", as invoked by the REST API. The student is enrolled if the course or its offering
+// in the term has a seat free, otherwise waitlisted. A student who had withdrawn from the
+// course in the same term is enrolled again; enrolments in other terms are left as they are.
+// Returns the status given, ErrAlreadyEnrolled if the student has an enrolment in the term
+// other than a withdrawn one, and error type.
+func Enrol(ctx context.Context, courseID string, termID string, studentID string) (status string, err error) {
+	defer observeCall("Enrol", time.Now())
+
+	query := "INSERT INTO Enrolments (CourseID, TermID, StudentID, EnrolledDate, Status, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "Enrol", query)
+	defer span.End()
+	defer recoverError(ctx, "Enrol", &err)
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	capacity, enrolled := lockCourseSeats(ctx, tx, courseID, termID)
+
+	status = Enrolled
+	if capacity > 0 && enrolled >= capacity {
+		status = Waitlisted
+	}
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, "UPDATE Enrolments SET EnrolledDate=?, Status=?, LastModified_DT=? "+
+		"WHERE CourseID=? AND TermID=? AND StudentID=? AND Status=?",
+		now.Format("2006-01-02"), status, now, courseID, termID, studentID, Withdrawn)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql update: %w", err))
+	} else if n == 0 {
+		// The primary key rejects a second enrolment in the term which is not withdrawn
+		_, err = tx.ExecContext(ctx, query, courseID, termID, studentID, now.Format("2006-01-02"), status, now, now)
+		if duplicateKey(err) {
+			return "", ErrAlreadyEnrolled
+		} else if err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return status, nil
+}
+
+// SetEnrolmentStatus implements the sql operations to change the status of the enrolment of
+// a student in a course in the term given, or outside any term if termID is "", as invoked
+// by the REST API. If a student gives up a seat the first waitlisted student is enrolled in
+// their place. Returns whether the operation succeeded.
+func SetEnrolmentStatus(ctx context.Context, courseID string, termID string, studentID string, status string) bool {
+	defer observeCall("SetEnrolmentStatus", time.Now())
+
+	query := "UPDATE Enrolments SET Status=?, LastModified_DT=? WHERE CourseID=? AND TermID=? AND StudentID=?"
+
+	ctx, span := startSpan(ctx, "SetEnrolmentStatus", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetEnrolmentStatus")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// Lock the course so seats are not given away twice
+	lockCourseSeats(ctx, tx, courseID, termID)
+
+	_, err = tx.ExecContext(ctx, query, status, time.Now(), courseID, termID, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+
+	promoteWaitlisted(ctx, tx, courseID, termID)
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true
+}
+
+// PromoteWaitlisted implements the sql operations to enrol waitlisted students into any free
+// seats of a course, e.g. after its capacity is raised, as invoked by the REST API. Students
+// waitlisted for an offering in a term are promoted by SetOffering instead.
+func PromoteWaitlisted(ctx context.Context, courseID string) {
+	defer observeCall("PromoteWaitlisted", time.Now())
+
+	ctx, span := startSpan(ctx, "PromoteWaitlisted", "")
+	defer span.End()
+	defer recoverPanic(ctx, "PromoteWaitlisted")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	promoteWaitlisted(ctx, tx, courseID, "")
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// lockCourseSeats locks the course row, or its offering in the term if termID is not "", for
+// the rest of the transaction and returns the capacity and the number of students enrolled
+// in it. It panics on error to be recovered by the calling function.
+func lockCourseSeats(ctx context.Context, tx *sql.Tx, courseID string, termID string) (int, int) {
+	var capacity, enrolled int
+
+	var err error
+	if termID == "" {
+		err = tx.QueryRowContext(ctx, "SELECT Capacity FROM Courses WHERE CourseID=? FOR UPDATE", courseID).Scan(&capacity)
+	} else {
+		err = tx.QueryRowContext(ctx, "SELECT Capacity FROM Offerings WHERE CourseID=? AND TermID=? FOR UPDATE",
+			courseID, termID).Scan(&capacity)
+	}
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM Enrolments WHERE CourseID=? AND TermID=? AND Status=?",
+		courseID, termID, Enrolled).Scan(&enrolled)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	return capacity, enrolled
+}
+
+// promoteWaitlisted enrols waitlisted students, longest waiting first, into the free seats of
+// a course, or of its offering in the term if termID is not "". It panics on error to be
+// recovered by the calling function.
+func promoteWaitlisted(ctx context.Context, tx *sql.Tx, courseID string, termID string) {
+	capacity, enrolled := lockCourseSeats(ctx, tx, courseID, termID)
+
+	// A capacity of 0 is unlimited
+	free := capacity - enrolled
+	if capacity == 0 {
+		free = 1 << 30
+	}
+	if free <= 0 {
+		return
+	}
+
+	_, err := tx.ExecContext(ctx, "UPDATE Enrolments SET Status=?, LastModified_DT=? WHERE CourseID=? AND TermID=? "+
+		"AND Status=? ORDER BY LastModified_DT LIMIT ?", Enrolled, time.Now(), courseID, termID, Waitlisted, free)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
@@ -0,0 +1,336 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AssessmentInfo struct for the json
+type AssessmentInfo struct {
+	CourseID string  `json:"CourseID"`
+	Name     string  `json:"Name"`
+	Weight   float64 `json:"Weight"`   // percentage of the final grade
+	MaxScore float64 `json:"MaxScore"` // score for full marks
+}
+
+// AddAssessment implements the sql operations to insert a new assessment as invoked by the
+// REST API. The assessment is not added if the weights of the assessments of the course
+// would total more than 100. Returns the ID of the new assessment, or 0 if it was not added,
+// and the total weight of the other assessments of the course.
+func AddAssessment(ctx context.Context, assessment AssessmentInfo) (int64, float64) {
+	defer observeCall("AddAssessment", time.Now())
+
+	query := "INSERT INTO Assessments (CourseID, Name, Weight, MaxScore, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddAssessment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddAssessment")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	other := lockAssessmentWeights(ctx, tx, assessment.CourseID, 0)
+	if other+assessment.Weight > 100 {
+		return 0, other
+	}
+
+	result, err := tx.ExecContext(ctx, query, assessment.CourseID, assessment.Name, assessment.Weight,
+		assessment.MaxScore, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		panic(fmt.Errorf("error getting id of sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return id, other
+}
+
+// UpdateAssessment implements the sql operations to update an assessment as invoked by the
+// REST API. As with AddAssessment, the weights of the course may not total more than 100.
+// Returns whether the assessment was updated, and the total weight of the other assessments.
+func UpdateAssessment(ctx context.Context, assessmentID int64, assessment AssessmentInfo) (bool, float64) {
+	defer observeCall("UpdateAssessment", time.Now())
+
+	query := "UPDATE Assessments SET Name=?, Weight=?, MaxScore=?, LastModified_DT=? WHERE AssessmentID=?"
+
+	ctx, span := startSpan(ctx, "UpdateAssessment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateAssessment")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	other := lockAssessmentWeights(ctx, tx, assessment.CourseID, assessmentID)
+	if other+assessment.Weight > 100 {
+		return false, other
+	}
+
+	_, err = tx.ExecContext(ctx, query, assessment.Name, assessment.Weight, assessment.MaxScore, time.Now(), assessmentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true, other
+}
+
+// DeleteAssessment implements the sql operations to delete an assessment, with its scores,
+// as invoked by the REST API.
+func DeleteAssessment(ctx context.Context, assessmentID int64) {
+	defer observeCall("DeleteAssessment", time.Now())
+
+	query := "DELETE FROM Assessments WHERE AssessmentID=?"
+
+	ctx, span := startSpan(ctx, "DeleteAssessment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteAssessment")
+
+	_, err := DB.ExecContext(ctx, query, assessmentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetAssessment implements the sql operations to retrieve an assessment, keyed by its
+// assessment ID, as invoked by the REST API.
+func GetAssessment(ctx context.Context, assessmentID int64) map[string]AssessmentInfo {
+	defer observeCall("GetAssessment", time.Now())
+
+	query := "SELECT AssessmentID, CourseID, Name, Weight, MaxScore FROM Assessments WHERE AssessmentID=?"
+
+	ctx, span := startSpan(ctx, "GetAssessment", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAssessment")
+
+	return queryAssessments(ctx, query, assessmentID)
+}
+
+// GetAssessments implements the sql operations to retrieve the assessments of a course,
+// keyed by assessment ID, as invoked by the REST API.
+func GetAssessments(ctx context.Context, courseID string) map[string]AssessmentInfo {
+	defer observeCall("GetAssessments", time.Now())
+
+	query := "SELECT AssessmentID, CourseID, Name, Weight, MaxScore FROM Assessments WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetAssessments", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAssessments")
+
+	return queryAssessments(ctx, query, courseID)
+}
+
+// queryAssessments runs a select of assessment columns and returns the assessments keyed
+// by assessment ID. It panics on error to be recovered by the calling function.
+func queryAssessments(ctx context.Context, query string, args ...interface{}) map[string]AssessmentInfo {
+	// Instantiate assessments
+	var assessments = make(map[string]AssessmentInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var assessmentID int64
+		var assessment AssessmentInfo
+		err := results.Scan(&assessmentID, &assessment.CourseID, &assessment.Name, &assessment.Weight, &assessment.MaxScore)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		assessments[strconv.FormatInt(assessmentID, 10)] = assessment
+	}
+	return assessments
+}
+
+// lockAssessmentWeights locks the course row for the rest of the transaction and returns the
+// total weight of its assessments other than the one given. It panics on error to be
+// recovered by the calling function.
+func lockAssessmentWeights(ctx context.Context, tx *sql.Tx, courseID string, assessmentID int64) float64 {
+	var locked string
+	err := tx.QueryRowContext(ctx, "SELECT CourseID FROM Courses WHERE CourseID=? FOR UPDATE", courseID).Scan(&locked)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+
+	var total float64
+	err = tx.QueryRowContext(ctx, "SELECT COALESCE(SUM(Weight), 0) FROM Assessments WHERE CourseID=? AND AssessmentID<>?",
+		courseID, assessmentID).Scan(&total)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	return total
+}
+
+// SetScores implements the sql operations to record the scores of students, keyed by student
+// ID, in an assessment for their enrolment in the term given, or outside any term if termID
+// is "", as invoked by the REST API. Existing scores are replaced.
+func SetScores(ctx context.Context, assessmentID int64, termID string, scores map[string]float64) {
+	defer observeCall("SetScores", time.Now())
+
+	query := "INSERT INTO Scores (AssessmentID, TermID, StudentID, Score, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE Score=VALUES(Score), LastModified_DT=VALUES(LastModified_DT)"
+
+	ctx, span := startSpan(ctx, "SetScores", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetScores")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error preparing sql insert: %w", err))
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for studentID, score := range scores {
+		if _, err := stmt.ExecContext(ctx, assessmentID, termID, studentID, score, now, now); err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// DeleteScore implements the sql operations to delete the score of a student in an
+// assessment for their enrolment in the term given as invoked by the REST API.
+func DeleteScore(ctx context.Context, assessmentID int64, termID string, studentID string) {
+	defer observeCall("DeleteScore", time.Now())
+
+	query := "DELETE FROM Scores WHERE AssessmentID=? AND TermID=? AND StudentID=?"
+
+	ctx, span := startSpan(ctx, "DeleteScore", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteScore")
+
+	_, err := DB.ExecContext(ctx, query, assessmentID, termID, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetScores implements the sql operations to retrieve the scores in the assessments of a
+// course for the enrolments in the term given, or outside any term if termID is "", keyed by
+// student ID and then assessment ID, as invoked by the REST API.
+func GetScores(ctx context.Context, courseID string, termID string) map[string]map[string]float64 {
+	defer observeCall("GetScores", time.Now())
+
+	query := "SELECT sc.StudentID, sc.AssessmentID, sc.Score FROM Scores sc " +
+		"JOIN Assessments a ON a.AssessmentID = sc.AssessmentID WHERE a.CourseID=? AND sc.TermID=?"
+
+	ctx, span := startSpan(ctx, "GetScores", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetScores")
+
+	// Instantiate scores
+	var scores = make(map[string]map[string]float64)
+
+	results, err := DB.QueryContext(ctx, query, courseID, termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var studentID string
+		var assessmentID int64
+		var score float64
+		if err := results.Scan(&studentID, &assessmentID, &score); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		if scores[studentID] == nil {
+			scores[studentID] = make(map[string]float64)
+		}
+		scores[studentID][strconv.FormatInt(assessmentID, 10)] = score
+	}
+	return scores
+}
+
+// GetGradeScale implements the sql operations to retrieve the letter-grade boundaries of a
+// course, the minimum percentage keyed by grade, as invoked by the REST API. It is empty if
+// the course uses the default boundaries.
+func GetGradeScale(ctx context.Context, courseID string) map[string]float64 {
+	defer observeCall("GetGradeScale", time.Now())
+
+	query := "SELECT Grade, MinPercent FROM GradeBoundaries WHERE CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetGradeScale", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetGradeScale")
+
+	// Instantiate scale
+	var scale = make(map[string]float64)
+
+	results, err := DB.QueryContext(ctx, query, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var grade string
+		var minPercent float64
+		if err := results.Scan(&grade, &minPercent); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		scale[grade] = minPercent
+	}
+	return scale
+}
+
+// SetGradeScale implements the sql operations to replace the letter-grade boundaries of a
+// course as invoked by the REST API. An empty scale restores the default boundaries.
+func SetGradeScale(ctx context.Context, courseID string, scale map[string]float64) {
+	defer observeCall("SetGradeScale", time.Now())
+
+	query := "INSERT INTO GradeBoundaries (CourseID, Grade, MinPercent) VALUES (?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "SetGradeScale", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetGradeScale")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM GradeBoundaries WHERE CourseID=?", courseID); err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	for grade, minPercent := range scale {
+		if _, err := tx.ExecContext(ctx, query, courseID, grade, minPercent); err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
@@ -0,0 +1,223 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Roles of an instructor assigned to a course.
+const (
+	Lead      = "lead"
+	Assistant = "assistant"
+)
+
+// InstructorInfo struct for the json
+type InstructorInfo struct {
+	Name  string `json:"Name"`
+	Email string `json:"Email"`
+}
+
+// AssignmentInfo struct for the json of an instructor assigned to a course
+type AssignmentInfo struct {
+	Name string `json:"Name"`
+	Role string `json:"Role"`
+}
+
+// AddInstructor implements the sql operations to insert a new instructor as invoked by the REST API.
+func AddInstructor(ctx context.Context, instructorID string, instructor InstructorInfo) {
+	defer observeCall("AddInstructor", time.Now())
+
+	query := "INSERT INTO Instructors (InstructorID, Name, Email, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddInstructor")
+
+	_, err := DB.ExecContext(ctx, query, instructorID, instructor.Name, instructor.Email, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+}
+
+// UpdateInstructor implements the sql operations to update an instructor as invoked by the REST API.
+func UpdateInstructor(ctx context.Context, instructorID string, instructor InstructorInfo) {
+	defer observeCall("UpdateInstructor", time.Now())
+
+	query := "UPDATE Instructors SET Name=?, Email=?, LastModified_DT=? WHERE InstructorID=?"
+
+	ctx, span := startSpan(ctx, "UpdateInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateInstructor")
+
+	_, err := DB.ExecContext(ctx, query, instructor.Name, instructor.Email, time.Now(), instructorID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
+
+// DeleteInstructor implements the sql operations to delete an instructor, and their course
+// assignments, as invoked by the REST API.
+func DeleteInstructor(ctx context.Context, instructorID string) {
+	defer observeCall("DeleteInstructor", time.Now())
+
+	query := "DELETE FROM Instructors WHERE InstructorID=?"
+
+	ctx, span := startSpan(ctx, "DeleteInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteInstructor")
+
+	_, err := DB.ExecContext(ctx, query, instructorID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetInstructor implements the sql operations to retrieve an instructor as invoked by the REST API.
+func GetInstructor(ctx context.Context, instructorID string) map[string]InstructorInfo {
+	defer observeCall("GetInstructor", time.Now())
+
+	query := "SELECT InstructorID, Name, Email FROM Instructors WHERE InstructorID=?"
+
+	ctx, span := startSpan(ctx, "GetInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetInstructor")
+
+	return queryInstructors(ctx, query, instructorID)
+}
+
+// GetAllInstructors implements the sql operations to retrieve all instructors as invoked by the REST API.
+func GetAllInstructors(ctx context.Context) map[string]InstructorInfo {
+	defer observeCall("GetAllInstructors", time.Now())
+
+	query := "SELECT InstructorID, Name, Email FROM Instructors"
+
+	ctx, span := startSpan(ctx, "GetAllInstructors", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllInstructors")
+
+	return queryInstructors(ctx, query)
+}
+
+// queryInstructors runs a select of instructor columns and returns the instructors keyed by
+// instructor ID. It panics on error to be recovered by the calling function.
+func queryInstructors(ctx context.Context, query string, args ...interface{}) map[string]InstructorInfo {
+	// Instantiate instructors
+	var instructors = make(map[string]InstructorInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var instructorID string
+		var instructor InstructorInfo
+		err := results.Scan(&instructorID, &instructor.Name, &instructor.Email)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		instructors[instructorID] = instructor
+	}
+	return instructors
+}
+
+// AssignInstructor implements the sql operations to assign an instructor to a course with the
+// role given, or change the role of an instructor already assigned, as invoked by the REST API.
+// The instructor is not assigned if they teach sessions of another course at the same time as
+// sessions of the course; the clashes are returned instead. Returns whether the instructor
+// was assigned.
+func AssignInstructor(ctx context.Context, courseID string, instructorID string, role string) (bool, []string) {
+	defer observeCall("AssignInstructor", time.Now())
+
+	query := "INSERT INTO CourseInstructors (CourseID, InstructorID, Role, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE Role=VALUES(Role), LastModified_DT=VALUES(LastModified_DT)"
+
+	ctx, span := startSpan(ctx, "AssignInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AssignInstructor")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if clashes := instructorClashes(ctx, tx, courseID, instructorID); len(clashes) != 0 {
+		return false, clashes
+	}
+
+	_, err = tx.ExecContext(ctx, query, courseID, instructorID, role, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true, nil
+}
+
+// UnassignInstructor implements the sql operations to remove an instructor from a course
+// as invoked by the REST API.
+func UnassignInstructor(ctx context.Context, courseID string, instructorID string) {
+	defer observeCall("UnassignInstructor", time.Now())
+
+	query := "DELETE FROM CourseInstructors WHERE CourseID=? AND InstructorID=?"
+
+	ctx, span := startSpan(ctx, "UnassignInstructor", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UnassignInstructor")
+
+	_, err := DB.ExecContext(ctx, query, courseID, instructorID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetCourseInstructors implements the sql operations to retrieve the instructors assigned to a
+// course, keyed by instructor ID, as invoked by the REST API.
+func GetCourseInstructors(ctx context.Context, courseID string) map[string]AssignmentInfo {
+	defer observeCall("GetCourseInstructors", time.Now())
+
+	query := "SELECT ci.CourseID, ci.InstructorID, i.Name, ci.Role FROM CourseInstructors ci " +
+		"JOIN Instructors i ON i.InstructorID = ci.InstructorID WHERE ci.CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetCourseInstructors", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourseInstructors")
+
+	instructors := queryAssignments(ctx, query, courseID)[courseID]
+	if instructors == nil {
+		instructors = make(map[string]AssignmentInfo)
+	}
+	return instructors
+}
+
+// queryAssignments runs a select of course instructor columns and returns the assignments
+// keyed by course ID then instructor ID. It panics on error to be recovered by the calling function.
+func queryAssignments(ctx context.Context, query string, args ...interface{}) map[string]map[string]AssignmentInfo {
+	// Instantiate assignments
+	var assignments = make(map[string]map[string]AssignmentInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var courseID, instructorID string
+		var assignment AssignmentInfo
+		err := results.Scan(&courseID, &instructorID, &assignment.Name, &assignment.Role)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		if assignments[courseID] == nil {
+			assignments[courseID] = make(map[string]AssignmentInfo)
+		}
+		assignments[courseID][instructorID] = assignment
+	}
+	return assignments
+}
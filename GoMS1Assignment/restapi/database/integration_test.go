@@ -0,0 +1,229 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// testDB connects to the MySQL database given by GOSCHOOL_TEST_DSN, e.g.
+// user:password@tcp(localhost:3306)/dbGoSchoolTest, and migrates it. Tests using it
+// are skipped unless the variable is set. The database should be one for tests only.
+func testDB(t *testing.T) context.Context {
+	t.Helper()
+
+	dsn := os.Getenv("GOSCHOOL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("GOSCHOOL_TEST_DSN is not set")
+	}
+	if err := Connect(dsn); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { DB.Close() })
+
+	ctx := context.Background()
+	if err := Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+// testID returns an ID for a row added by a test, unique to the run.
+func testID(prefix string) string {
+	return prefix + strconv.FormatInt(time.Now().UnixNano()%1e9, 36)
+}
+
+func TestEnrolInEachTerm(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID, studentID := testID("C"), testID("S")
+	terms := []string{testID("T1"), testID("T2")}
+
+	AddCourse(ctx, courseID, "Enrolment test", "", 0, "")
+	t.Cleanup(func() { DeleteCourse(ctx, courseID) })
+	AddStudent(ctx, studentID, StudentInfo{Name: "Test Student", Email: "test@example.com", DateOfBirth: "2000-01-01", Status: "active"})
+	t.Cleanup(func() { DeleteStudent(ctx, studentID) })
+	for _, termID := range terms {
+		termID := termID
+		AddTerm(ctx, termID, TermInfo{Name: termID, StartDate: "2030-01-01", EndDate: "2030-04-30"})
+		t.Cleanup(func() { DeleteTerm(ctx, termID) })
+		SetOffering(ctx, courseID, termID, 1)
+	}
+
+	for _, termID := range terms {
+		if status, err := Enrol(ctx, courseID, termID, studentID); err != nil || status != Enrolled {
+			t.Fatalf("Enrol in %s = %q, %v, want %q", termID, status, err, Enrolled)
+		}
+	}
+	if _, err := Enrol(ctx, courseID, terms[0], studentID); !errors.Is(err, ErrAlreadyEnrolled) {
+		t.Errorf("Enrol in %s again error = %v, want ErrAlreadyEnrolled", terms[0], err)
+	}
+
+	for _, termID := range terms {
+		if e, ok := GetEnrolment(ctx, courseID, termID, studentID)[studentID]; !ok || e.Status != Enrolled {
+			t.Errorf("GetEnrolment in %s = %+v, %v, want enrolled", termID, e, ok)
+		}
+	}
+	if n := len(GetEnrolments(ctx, courseID)); n != len(terms) {
+		t.Errorf("GetEnrolments returned %d enrolments, want %d", n, len(terms))
+	}
+	if n := len(GetLearnerCourses(ctx, studentID)); n != len(terms) {
+		t.Errorf("GetLearnerCourses returned %d courses, want %d", n, len(terms))
+	}
+
+	// Withdrawing from one term leaves the other enrolment as it is
+	if !SetEnrolmentStatus(ctx, courseID, terms[0], studentID, Withdrawn) {
+		t.Fatal("SetEnrolmentStatus failed")
+	}
+	if e := GetEnrolment(ctx, courseID, terms[1], studentID)[studentID]; e.Status != Enrolled {
+		t.Errorf("enrolment in %s is %q after withdrawing from %s, want %q", terms[1], e.Status, terms[0], Enrolled)
+	}
+}
+
+func TestScoresInEachTerm(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID, studentID := testID("C"), testID("S")
+	terms := []string{testID("T1"), testID("T2")}
+
+	AddCourse(ctx, courseID, "Score test", "", 0, "")
+	t.Cleanup(func() { DeleteCourse(ctx, courseID) })
+	AddStudent(ctx, studentID, StudentInfo{Name: "Test Student", Email: "test@example.com", DateOfBirth: "2000-01-01", Status: "active"})
+	t.Cleanup(func() { DeleteStudent(ctx, studentID) })
+	for _, termID := range terms {
+		termID := termID
+		AddTerm(ctx, termID, TermInfo{Name: termID, StartDate: "2030-01-01", EndDate: "2030-04-30"})
+		t.Cleanup(func() { DeleteTerm(ctx, termID) })
+	}
+
+	assessmentID, _ := AddAssessment(ctx, AssessmentInfo{CourseID: courseID, Name: "Exam", Weight: 100, MaxScore: 100})
+	if assessmentID == 0 {
+		t.Fatal("AddAssessment failed")
+	}
+	id := strconv.FormatInt(assessmentID, 10)
+
+	// A student who retakes the course is scored again without replacing the earlier score
+	SetScores(ctx, assessmentID, terms[0], map[string]float64{studentID: 40})
+	SetScores(ctx, assessmentID, terms[1], map[string]float64{studentID: 90})
+	for termID, want := range map[string]float64{terms[0]: 40, terms[1]: 90} {
+		if got, ok := GetScores(ctx, courseID, termID)[studentID][id]; !ok || got != want {
+			t.Errorf("GetScores in %s = %v, %v, want %v", termID, got, ok, want)
+		}
+	}
+	if scores := GetScores(ctx, courseID, ""); len(scores) != 0 {
+		t.Errorf("GetScores outside any term = %v, want none", scores)
+	}
+
+	DeleteScore(ctx, assessmentID, terms[0], studentID)
+	if _, ok := GetScores(ctx, courseID, terms[1])[studentID][id]; !ok {
+		t.Errorf("score in %s deleted with the score in %s", terms[1], terms[0])
+	}
+
+	// Deleting a term deletes its scores
+	DeleteTerm(ctx, terms[1])
+	if scores := GetScores(ctx, courseID, terms[1]); len(scores) != 0 {
+		t.Errorf("GetScores in deleted term %s = %v, want none", terms[1], scores)
+	}
+}
+
+func TestAssignInstructorClashes(t *testing.T) {
+	ctx := testDB(t)
+
+	first, second, instructorID, termID := testID("C"), testID("D"), testID("I"), testID("T")
+
+	AddTerm(ctx, termID, TermInfo{Name: termID, StartDate: "2030-01-01", EndDate: "2030-04-30"})
+	t.Cleanup(func() { DeleteTerm(ctx, termID) })
+	AddInstructor(ctx, instructorID, InstructorInfo{Name: "Test Instructor", Email: "test@example.com"})
+	t.Cleanup(func() { DeleteInstructor(ctx, instructorID) })
+	for i, courseID := range []string{first, second} {
+		courseID := courseID
+		AddCourse(ctx, courseID, "Clash test", "", 0, "")
+		t.Cleanup(func() { DeleteCourse(ctx, courseID) })
+		SetOffering(ctx, courseID, termID, 0)
+
+		// The sessions overlap from 09:30 to 10:00
+		start := []string{"09:00", "09:30"}[i]
+		end := []string{"10:00", "10:30"}[i]
+		if id, clashes := AddSession(ctx, SessionInfo{CourseID: courseID, Term: termID, Day: "Mon",
+			StartTime: start, EndTime: end, Room: testID("R")}); id == 0 {
+			t.Fatalf("AddSession failed: %v", clashes)
+		}
+	}
+
+	if assigned, clashes := AssignInstructor(ctx, first, instructorID, "lead"); !assigned {
+		t.Fatalf("AssignInstructor(%s) failed: %v", first, clashes)
+	}
+	if assigned, clashes := AssignInstructor(ctx, second, instructorID, "lead"); assigned || len(clashes) != 1 {
+		t.Errorf("AssignInstructor(%s) = %v, %v, want one clash", second, assigned, clashes)
+	}
+	if _, ok := GetCourseInstructors(ctx, second)[instructorID]; ok {
+		t.Errorf("instructor %s was assigned to %s despite the clash", instructorID, second)
+	}
+}
+
+func TestAttendanceTalliesByTerm(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID, studentID, termID := testID("C"), testID("S"), testID("T")
+
+	AddCourse(ctx, courseID, "Attendance test", "", 0, "")
+	t.Cleanup(func() { DeleteCourse(ctx, courseID) })
+	AddStudent(ctx, studentID, StudentInfo{Name: "Test Student", Email: "test@example.com", DateOfBirth: "2000-01-01", Status: "active"})
+	t.Cleanup(func() { DeleteStudent(ctx, studentID) })
+	AddTerm(ctx, termID, TermInfo{Name: termID, StartDate: "2030-01-01", EndDate: "2030-04-30"})
+	t.Cleanup(func() { DeleteTerm(ctx, termID) })
+	SetOffering(ctx, courseID, termID, 0)
+
+	sessionID, clashes := AddSession(ctx, SessionInfo{CourseID: courseID, Term: termID, Day: "Mon",
+		StartTime: "09:00", EndTime: "10:00", Room: testID("R")})
+	if sessionID == 0 {
+		t.Fatalf("AddSession failed: %v", clashes)
+	}
+
+	// The student is enrolled both outside any term and in the term
+	for _, term := range []string{"", termID} {
+		if _, err := Enrol(ctx, courseID, term, studentID); err != nil {
+			t.Fatalf("Enrol in %q: %v", term, err)
+		}
+	}
+
+	inTerm := AddClass(ctx, ClassInfo{CourseID: courseID, SessionID: sessionID, Date: "2030-01-07"})
+	outside := AddClass(ctx, ClassInfo{CourseID: courseID, Date: "2030-01-08"})
+	SetAttendance(ctx, inTerm, map[string]string{studentID: Present})
+	SetAttendance(ctx, outside, map[string]string{studentID: Absent})
+
+	want := map[string]AttendanceTally{
+		termID: {CourseID: courseID, TermID: termID, StudentID: studentID, Name: "Test Student", EnrolmentStatus: Enrolled, Present: 1},
+		"":     {CourseID: courseID, TermID: "", StudentID: studentID, Name: "Test Student", EnrolmentStatus: Enrolled, Absent: 1},
+	}
+	tallies := GetAttendanceTallies(ctx, courseID, "")
+	if len(tallies) != len(want) {
+		t.Fatalf("GetAttendanceTallies = %+v, want a tally for each term", tallies)
+	}
+	for _, got := range tallies {
+		if got != want[got.TermID] {
+			t.Errorf("tally in %q = %+v, want %+v", got.TermID, got, want[got.TermID])
+		}
+	}
+}
+
+func TestUpdateCourseMissingDepartment(t *testing.T) {
+	ctx := testDB(t)
+
+	courseID := testID("C")
+	AddCourse(ctx, courseID, "Update test", "", 0, "")
+	t.Cleanup(func() { DeleteCourse(ctx, courseID) })
+
+	if err := UpdateCourse(ctx, courseID, "Update test", "", 0, testID("D")); !errors.Is(err, ErrNoDepartment) {
+		t.Errorf("UpdateCourse() with a missing department error = %v, want ErrNoDepartment", err)
+	}
+	if err := UpdateCourse(ctx, courseID, "Updated", "A course.", 10, ""); err != nil {
+		t.Fatalf("UpdateCourse() error = %v", err)
+	}
+	if c := GetCourse(ctx, courseID)[courseID]; c.Title != "Updated" || c.Description != "A course." || c.Capacity != 10 {
+		t.Errorf("GetCourse() after UpdateCourse = %+v", c)
+	}
+}
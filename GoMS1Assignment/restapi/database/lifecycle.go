@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Statuses of a course. New courses are drafts, which are submitted for approval and
+// become active once approved. Only active courses are listed by default and open for
+// enrolment. Retired courses are kept for the records of their students.
+const (
+	Draft   = "draft"
+	Pending = "pending"
+	Active  = "active"
+	Retired = "retired"
+)
+
+// StatusChangeInfo struct for the json of a change in the status of a course
+type StatusChangeInfo struct {
+	From      string `json:"From"`
+	To        string `json:"To"`
+	Comment   string `json:"Comment,omitempty"`
+	ChangedBy string `json:"ChangedBy,omitempty"` // label of the API key used
+	Date      string `json:"Date"`                // YYYY-MM-DD HH:MM:SS
+}
+
+// SetCourseStatus implements the sql operations to change the status of a course from the
+// status given and record the change in its history, as invoked by the REST API.
+// Returns false if the course is no longer in the status given.
+func SetCourseStatus(ctx context.Context, courseID string, change StatusChangeInfo) bool {
+	defer observeCall("SetCourseStatus", time.Now())
+
+	query := "UPDATE Courses SET Status=?, LastModified_DT=? WHERE CourseID=? AND Status=?"
+
+	ctx, span := startSpan(ctx, "SetCourseStatus", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetCourseStatus")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, change.To, time.Now(), courseID, change.From)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql update: %w", err))
+	} else if n == 0 {
+		return false
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO CourseHistory (CourseID, FromStatus, ToStatus, Comment, ChangedBy, Created_DT) "+
+		"VALUES (?, ?, ?, ?, ?, ?)", courseID, change.From, change.To, change.Comment, change.ChangedBy, time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true
+}
+
+// GetCourseHistory implements the sql operations to retrieve the changes in the status of a
+// course, oldest first, as invoked by the REST API.
+func GetCourseHistory(ctx context.Context, courseID string) []StatusChangeInfo {
+	defer observeCall("GetCourseHistory", time.Now())
+
+	query := "SELECT FromStatus, ToStatus, Comment, ChangedBy, Created_DT " +
+		"FROM CourseHistory WHERE CourseID=? ORDER BY HistoryID"
+
+	ctx, span := startSpan(ctx, "GetCourseHistory", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourseHistory")
+
+	// Instantiate history
+	history := []StatusChangeInfo{}
+
+	results, err := DB.QueryContext(ctx, query, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var change StatusChangeInfo
+		err := results.Scan(&change.From, &change.To, &change.Comment, &change.ChangedBy, &change.Date)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		history = append(history, change)
+	}
+	return history
+}
+
+// AdminKey implements the sql operations to check if the access key given is an admin key,
+// which may approve or reject courses, as invoked by the REST API.
+func AdminKey(ctx context.Context, key string) bool {
+	defer observeCall("AdminKey", time.Now())
+
+	query := "SELECT IsAdmin FROM APIKeys WHERE KeyID=?"
+
+	ctx, span := startSpan(ctx, "AdminKey", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AdminKey")
+
+	var admin bool
+
+	results, err := DB.QueryContext(ctx, query, key)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	if results.Next() {
+		if err := results.Scan(&admin); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+	}
+	return admin
+}
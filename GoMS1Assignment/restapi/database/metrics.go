@@ -0,0 +1,22 @@
+package database
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var dbCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "goschool_db_call_duration_seconds",
+	Help:    "Duration of calls to the database package, by function.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"function"})
+
+// observeCall records the time taken by a database function since start.
+// It is intended to be deferred at the top of the function:
+//
+//	defer observeCall("AddCourse", time.Now())
+func observeCall(function string, start time.Time) {
+	dbCallDuration.WithLabelValues(function).Observe(time.Since(start).Seconds())
+}
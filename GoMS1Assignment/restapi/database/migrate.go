@@ -0,0 +1,232 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the schema migrations. Each file is named with a
+// zero-padded version prefix, e.g. 0001_create_courses.sql, and applied in order.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migration struct for a single schema migration
+type migration struct {
+	Version string
+	SQL     string
+}
+
+// loadMigrations reads the embedded migration files and returns them sorted by version.
+func loadMigrations() ([]migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, entry := range entries {
+		data, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		version := strings.TrimSuffix(entry.Name(), ".sql")
+		migrations = append(migrations, migration{version, string(data)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrate applies any schema migrations which have not been recorded in the
+// SchemaMigrations table. MySQL commits each DDL statement implicitly, so a migration
+// cannot run in a transaction. Instead each statement applied is recorded in the
+// SchemaMigrationSteps table, and a migration which failed part way resumes from the
+// statement which failed when Migrate is run again.
+func Migrate(ctx context.Context) error {
+	defer observeCall("Migrate", time.Now())
+
+	_, err := DB.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS SchemaMigrations (Version VARCHAR(100) NOT NULL PRIMARY KEY, Applied_DT DATETIME NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("creating SchemaMigrations: %w", err)
+	}
+	_, err = DB.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS SchemaMigrationSteps (Version VARCHAR(100) NOT NULL, Step INT NOT NULL, Applied_DT DATETIME NOT NULL, PRIMARY KEY (Version, Step))")
+	if err != nil {
+		return fmt.Errorf("creating SchemaMigrationSteps: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+
+		steps, err := appliedSteps(ctx, m.Version)
+		if err != nil {
+			return err
+		}
+
+		// The driver does not run multiple statements in a single Exec
+		for i, stmt := range splitStatements(m.SQL) {
+			if steps[i] {
+				continue
+			}
+			if _, err := DB.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("applying migration %s statement %d: %w", m.Version, i+1, err)
+			}
+			_, err = DB.ExecContext(ctx, "INSERT INTO SchemaMigrationSteps (Version, Step, Applied_DT) VALUES (?, ?, ?)", m.Version, i, time.Now())
+			if err != nil {
+				return fmt.Errorf("recording migration %s statement %d: %w", m.Version, i+1, err)
+			}
+		}
+
+		_, err = DB.ExecContext(ctx, "INSERT INTO SchemaMigrations (Version, Applied_DT) VALUES (?, ?)", m.Version, time.Now())
+		if err != nil {
+			return fmt.Errorf("recording migration %s: %w", m.Version, err)
+		}
+		slog.Info("applied migration", "version", m.Version)
+	}
+	return nil
+}
+
+// splitStatements splits the SQL of a migration into its statements, which are separated
+// by semicolons. Semicolons in quoted strings and identifiers do not separate statements,
+// and comments are removed.
+func splitStatements(sql string) []string {
+	var statements []string
+	var stmt strings.Builder
+
+	add := func() {
+		if s := strings.TrimSpace(stmt.String()); s != "" {
+			statements = append(statements, s)
+		}
+		stmt.Reset()
+	}
+
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Copy the quoted string up to its closing quote, which is escaped by doubling
+			// it or, except in identifiers, by a backslash
+			end := i + 1
+			for ; end < len(sql); end++ {
+				if sql[end] == '\\' && c != '`' {
+					end++
+				} else if sql[end] == c {
+					if end+1 < len(sql) && sql[end+1] == c {
+						end++
+					} else {
+						break
+					}
+				}
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			stmt.WriteString(sql[i : end+1])
+			i = end
+		case c == '#' || c == '-' && strings.HasPrefix(sql[i:], "-- "):
+			// Skip the comment up to the end of the line
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end
+				stmt.WriteByte('\n')
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sql)
+			}
+			stmt.WriteByte(' ')
+		case c == ';':
+			add()
+		default:
+			stmt.WriteByte(c)
+		}
+	}
+	add()
+	return statements
+}
+
+// PendingMigrations returns the versions of the migrations which have not been applied.
+func PendingMigrations(ctx context.Context) ([]string, error) {
+	defer observeCall("PendingMigrations", time.Now())
+
+	applied, err := appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m.Version)
+		}
+	}
+	return pending, nil
+}
+
+// appliedMigrations retrieves the versions recorded in the SchemaMigrations table.
+func appliedMigrations(ctx context.Context) (map[string]bool, error) {
+	applied := make(map[string]bool)
+
+	results, err := DB.QueryContext(ctx, "SELECT Version FROM SchemaMigrations")
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var version string
+		if err := results.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, results.Err()
+}
+
+// appliedSteps retrieves the statements of a migration recorded in the SchemaMigrationSteps
+// table, keyed by their index in the migration.
+func appliedSteps(ctx context.Context, version string) (map[int]bool, error) {
+	steps := make(map[int]bool)
+
+	results, err := DB.QueryContext(ctx, "SELECT Step FROM SchemaMigrationSteps WHERE Version=?", version)
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var step int
+		if err := results.Scan(&step); err != nil {
+			return nil, err
+		}
+		steps[step] = true
+	}
+	return steps, results.Err()
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"single", "CREATE TABLE t (a INT)", []string{"CREATE TABLE t (a INT)"}},
+		{"trailing semicolon", "CREATE TABLE t (a INT);\n", []string{"CREATE TABLE t (a INT)"}},
+		{"several", "ALTER TABLE t ADD b INT;\n\nALTER TABLE t ADD c INT;", []string{"ALTER TABLE t ADD b INT", "ALTER TABLE t ADD c INT"}},
+		{"line comments", "-- first; not a statement\nSELECT 1; # second; nor this\nSELECT 2", []string{"SELECT 1", "SELECT 2"}},
+		{"block comment", "SELECT /* a; b */ 1", []string{"SELECT   1"}},
+		{"quoted semicolons", "INSERT INTO t VALUES ('a;b', \"c;d\"); SELECT `e;f` FROM t", []string{"INSERT INTO t VALUES ('a;b', \"c;d\")", "SELECT `e;f` FROM t"}},
+		{"escaped quotes", `INSERT INTO t VALUES ('it''s;', 'it\'s;'); SELECT 1`, []string{`INSERT INTO t VALUES ('it''s;', 'it\'s;')`, "SELECT 1"}},
+		{"minus", "SELECT 2-1; SELECT 3", []string{"SELECT 2-1", "SELECT 3"}},
+		{"comment only", "-- nothing to do\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMigrations checks that the embedded migrations are in order and that each of their
+// statements is whole.
+func TestMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, m := range migrations {
+		if i > 0 && m.Version[:4] == migrations[i-1].Version[:4] {
+			t.Errorf("migrations %s and %s have the same version", migrations[i-1].Version, m.Version)
+		}
+		statements := splitStatements(m.SQL)
+		if len(statements) == 0 {
+			t.Errorf("migration %s has no statements", m.Version)
+		}
+		for _, stmt := range statements {
+			keyword := strings.ToUpper(strings.Fields(stmt)[0])
+			switch keyword {
+			case "CREATE", "ALTER", "INSERT", "UPDATE", "DELETE", "DROP":
+			default:
+				t.Errorf("migration %s has a statement starting %q", m.Version, keyword)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS Courses (
+    CourseID        VARCHAR(20) NOT NULL,
+    CourseTitle     VARCHAR(45) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (CourseID)
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS APIKeys (
+    KeyID VARCHAR(36) NOT NULL,
+    PRIMARY KEY (KeyID)
+);
@@ -0,0 +1 @@
+ALTER TABLE APIKeys ADD COLUMN Label VARCHAR(50) NULL;
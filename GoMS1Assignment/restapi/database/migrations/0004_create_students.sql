@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Students (
+    StudentID       VARCHAR(20)  NOT NULL,
+    Name            VARCHAR(100) NOT NULL,
+    Email           VARCHAR(254) NOT NULL,
+    DateOfBirth     DATE         NOT NULL,
+    Status          VARCHAR(20)  NOT NULL DEFAULT 'active',
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (StudentID),
+    INDEX idx_students_email (Email)
+);
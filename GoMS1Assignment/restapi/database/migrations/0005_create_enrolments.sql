@@ -0,0 +1,16 @@
+ALTER TABLE Courses ADD COLUMN Capacity INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS Enrolments (
+    CourseID        VARCHAR(20) NOT NULL,
+    StudentID       VARCHAR(20) NOT NULL,
+    EnrolledDate    DATE        NOT NULL,
+    Status          VARCHAR(20) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME(6) NOT NULL,
+    PRIMARY KEY (CourseID, StudentID),
+    INDEX idx_enrolments_student (StudentID),
+    CONSTRAINT fk_enrolments_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_enrolments_student FOREIGN KEY (StudentID) REFERENCES Students (StudentID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
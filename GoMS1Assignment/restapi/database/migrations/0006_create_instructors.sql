@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS Instructors (
+    InstructorID    VARCHAR(20)  NOT NULL,
+    Name            VARCHAR(100) NOT NULL,
+    Email           VARCHAR(254) NOT NULL,
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (InstructorID)
+);
+
+CREATE TABLE IF NOT EXISTS CourseInstructors (
+    CourseID        VARCHAR(20) NOT NULL,
+    InstructorID    VARCHAR(20) NOT NULL,
+    Role            VARCHAR(20) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (CourseID, InstructorID),
+    INDEX idx_courseinstructors_instructor (InstructorID),
+    CONSTRAINT fk_courseinstructors_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_courseinstructors_instructor FOREIGN KEY (InstructorID) REFERENCES Instructors (InstructorID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
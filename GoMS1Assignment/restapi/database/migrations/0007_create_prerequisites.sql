@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS Prerequisites (
+    CourseID        VARCHAR(20) NOT NULL,
+    PrerequisiteID  VARCHAR(20) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    PRIMARY KEY (CourseID, PrerequisiteID),
+    INDEX idx_prerequisites_prerequisite (PrerequisiteID),
+    CONSTRAINT fk_prerequisites_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_prerequisites_prerequisite FOREIGN KEY (PrerequisiteID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS Sessions (
+    SessionID       INT         NOT NULL AUTO_INCREMENT,
+    CourseID        VARCHAR(20) NOT NULL,
+    Term            VARCHAR(20) NOT NULL,
+    Day             CHAR(3)     NOT NULL,
+    StartTime       TIME        NOT NULL,
+    EndTime         TIME        NOT NULL,
+    Room            VARCHAR(45) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (SessionID),
+    INDEX idx_sessions_course (CourseID),
+    INDEX idx_sessions_room (Term, Day, Room),
+    CONSTRAINT fk_sessions_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
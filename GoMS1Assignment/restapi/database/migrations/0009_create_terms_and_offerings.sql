@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS Terms (
+    TermID          VARCHAR(20) NOT NULL,
+    Name            VARCHAR(45) NOT NULL,
+    StartDate       DATE        NOT NULL,
+    EndDate         DATE        NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (TermID)
+);
+
+CREATE TABLE IF NOT EXISTS Offerings (
+    CourseID        VARCHAR(20) NOT NULL,
+    TermID          VARCHAR(20) NOT NULL,
+    Capacity        INT         NOT NULL DEFAULT 0,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (CourseID, TermID),
+    INDEX idx_offerings_term (TermID),
+    CONSTRAINT fk_offerings_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_offerings_term FOREIGN KEY (TermID) REFERENCES Terms (TermID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- Sessions were scheduled by term name before terms existed. Each of those terms
+-- becomes a 15 week term starting today, to be corrected through the API.
+INSERT IGNORE INTO Terms (TermID, Name, StartDate, EndDate, Created_DT, LastModified_DT)
+    SELECT DISTINCT Term, Term, CURDATE(), CURDATE() + INTERVAL 104 DAY, NOW(), NOW() FROM Sessions;
+
+INSERT IGNORE INTO Offerings (CourseID, TermID, Capacity, Created_DT, LastModified_DT)
+    SELECT DISTINCT s.CourseID, s.Term, c.Capacity, NOW(), NOW()
+    FROM Sessions s JOIN Courses c ON c.CourseID = s.CourseID;
+
+ALTER TABLE Sessions
+    ADD CONSTRAINT fk_sessions_offering FOREIGN KEY (CourseID, Term) REFERENCES Offerings (CourseID, TermID)
+        ON UPDATE CASCADE ON DELETE CASCADE;
+
+-- Enrolments made before terms existed, or without a term, have no TermID and are
+-- limited by the capacity of the course rather than of an offering.
+ALTER TABLE Enrolments
+    ADD COLUMN TermID VARCHAR(20) NULL AFTER StudentID,
+    ADD CONSTRAINT fk_enrolments_offering FOREIGN KEY (CourseID, TermID) REFERENCES Offerings (CourseID, TermID)
+        ON UPDATE CASCADE ON DELETE CASCADE;
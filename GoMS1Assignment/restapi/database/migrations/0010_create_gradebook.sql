@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS Assessments (
+    AssessmentID    INT          NOT NULL AUTO_INCREMENT,
+    CourseID        VARCHAR(20)  NOT NULL,
+    Name            VARCHAR(45)  NOT NULL,
+    Weight          DECIMAL(5,2) NOT NULL,
+    MaxScore        DECIMAL(7,2) NOT NULL,
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (AssessmentID),
+    UNIQUE INDEX idx_assessments_name (CourseID, Name),
+    CONSTRAINT fk_assessments_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Scores (
+    AssessmentID    INT          NOT NULL,
+    StudentID       VARCHAR(20)  NOT NULL,
+    Score           DECIMAL(7,2) NOT NULL,
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (AssessmentID, StudentID),
+    INDEX idx_scores_student (StudentID),
+    CONSTRAINT fk_scores_assessment FOREIGN KEY (AssessmentID) REFERENCES Assessments (AssessmentID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_scores_student FOREIGN KEY (StudentID) REFERENCES Students (StudentID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS GradeBoundaries (
+    CourseID        VARCHAR(20)  NOT NULL,
+    Grade           VARCHAR(2)   NOT NULL,
+    MinPercent      DECIMAL(5,2) NOT NULL,
+    PRIMARY KEY (CourseID, Grade),
+    CONSTRAINT fk_gradeboundaries_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS Completions (
+    StudentID       VARCHAR(20)  NOT NULL,
+    TermID          VARCHAR(20)  NOT NULL,
+    CourseID        VARCHAR(20)  NOT NULL,
+    Grade           VARCHAR(2)   NOT NULL,
+    Credits         DECIMAL(4,1) NOT NULL,
+    CompletedDate   DATE         NOT NULL,
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (StudentID, TermID, CourseID),
+    INDEX idx_completions_course (CourseID),
+    INDEX idx_completions_term (TermID),
+    CONSTRAINT fk_completions_student FOREIGN KEY (StudentID) REFERENCES Students (StudentID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_completions_term FOREIGN KEY (TermID) REFERENCES Terms (TermID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_completions_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
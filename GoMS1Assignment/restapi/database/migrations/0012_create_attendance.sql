@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS Classes (
+    ClassID         INT          NOT NULL AUTO_INCREMENT,
+    CourseID        VARCHAR(20)  NOT NULL,
+    SessionID       INT          NULL,
+    ClassDate       DATE         NOT NULL,
+    Topic           VARCHAR(100) NOT NULL DEFAULT '',
+    Created_DT      DATETIME     NOT NULL,
+    LastModified_DT DATETIME     NOT NULL,
+    PRIMARY KEY (ClassID),
+    INDEX idx_classes_course (CourseID, ClassDate),
+    CONSTRAINT fk_classes_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_classes_session FOREIGN KEY (SessionID) REFERENCES Sessions (SessionID)
+        ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS Attendance (
+    ClassID         INT         NOT NULL,
+    StudentID       VARCHAR(20) NOT NULL,
+    Status          VARCHAR(10) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (ClassID, StudentID),
+    INDEX idx_attendance_student (StudentID),
+    CONSTRAINT fk_attendance_class FOREIGN KEY (ClassID) REFERENCES Classes (ClassID)
+        ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT fk_attendance_student FOREIGN KEY (StudentID) REFERENCES Students (StudentID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
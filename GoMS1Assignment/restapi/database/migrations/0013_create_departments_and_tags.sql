@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS Departments (
+    DepartmentID    VARCHAR(20) NOT NULL,
+    Name            VARCHAR(45) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    LastModified_DT DATETIME    NOT NULL,
+    PRIMARY KEY (DepartmentID)
+);
+
+-- Courses without a department are listed apart from the departments.
+ALTER TABLE Courses
+    ADD COLUMN DepartmentID VARCHAR(20) NULL AFTER Capacity,
+    ADD INDEX idx_courses_department (DepartmentID),
+    ADD CONSTRAINT fk_courses_department FOREIGN KEY (DepartmentID) REFERENCES Departments (DepartmentID)
+        ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS CourseTags (
+    CourseID        VARCHAR(20) NOT NULL,
+    Tag             VARCHAR(30) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    PRIMARY KEY (CourseID, Tag),
+    INDEX idx_coursetags_tag (Tag),
+    CONSTRAINT fk_coursetags_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
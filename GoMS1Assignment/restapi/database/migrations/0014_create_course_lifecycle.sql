@@ -0,0 +1,21 @@
+-- Courses added before the approval workflow stay active.
+ALTER TABLE Courses
+    ADD COLUMN Status VARCHAR(10) NOT NULL DEFAULT 'active' AFTER DepartmentID,
+    ADD INDEX idx_courses_status (Status);
+
+-- Only admin keys may approve or reject courses pending approval.
+ALTER TABLE APIKeys ADD COLUMN IsAdmin BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS CourseHistory (
+    HistoryID       INT          NOT NULL AUTO_INCREMENT,
+    CourseID        VARCHAR(20)  NOT NULL,
+    FromStatus      VARCHAR(10)  NOT NULL,
+    ToStatus        VARCHAR(10)  NOT NULL,
+    Comment         VARCHAR(255) NOT NULL DEFAULT '',
+    ChangedBy       VARCHAR(50)  NOT NULL DEFAULT '',
+    Created_DT      DATETIME     NOT NULL,
+    PRIMARY KEY (HistoryID),
+    INDEX idx_coursehistory_course (CourseID),
+    CONSTRAINT fk_coursehistory_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- Long-form description of a course in Markdown.
+ALTER TABLE Courses ADD COLUMN Description TEXT NULL AFTER CourseTitle;
+
+-- The files themselves are kept in the blob store under their BlobKey.
+CREATE TABLE IF NOT EXISTS CourseAttachments (
+    AttachmentID    INT          NOT NULL AUTO_INCREMENT,
+    CourseID        VARCHAR(20)  NOT NULL,
+    FileName        VARCHAR(255) NOT NULL,
+    ContentType     VARCHAR(100) NOT NULL,
+    Size            BIGINT       NOT NULL,
+    BlobKey         VARCHAR(64)  NOT NULL,
+    Created_DT      DATETIME     NOT NULL,
+    PRIMARY KEY (AttachmentID),
+    INDEX idx_courseattachments_course (CourseID),
+    CONSTRAINT fk_courseattachments_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
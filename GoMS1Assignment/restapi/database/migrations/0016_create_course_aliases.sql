@@ -0,0 +1,11 @@
+-- Former IDs of renamed courses, which redirect to the current ID. Renaming the
+-- course again updates its aliases through the foreign key.
+CREATE TABLE IF NOT EXISTS CourseAliases (
+    AliasID         VARCHAR(20) NOT NULL,
+    CourseID        VARCHAR(20) NOT NULL,
+    Created_DT      DATETIME    NOT NULL,
+    PRIMARY KEY (AliasID),
+    INDEX idx_coursealiases_course (CourseID),
+    CONSTRAINT fk_coursealiases_course FOREIGN KEY (CourseID) REFERENCES Courses (CourseID)
+        ON UPDATE CASCADE ON DELETE CASCADE
+);
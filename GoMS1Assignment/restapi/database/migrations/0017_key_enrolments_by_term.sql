@@ -0,0 +1,15 @@
+-- A student may enrol in a course once in each term it is offered, and once outside any
+-- term. Enrolments outside a term had a NULL TermID, which cannot be part of the primary
+-- key, so they get '' instead. The foreign key to Offerings is dropped as '' matches no
+-- offering, and enrolments are deleted with their offering or term by the REST API.
+ALTER TABLE Enrolments
+    DROP FOREIGN KEY fk_enrolments_offering,
+    DROP INDEX fk_enrolments_offering;
+
+UPDATE Enrolments SET TermID = '' WHERE TermID IS NULL;
+
+ALTER TABLE Enrolments
+    MODIFY COLUMN TermID VARCHAR(20) NOT NULL DEFAULT '',
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (CourseID, TermID, StudentID),
+    ADD INDEX idx_enrolments_term (TermID);
@@ -0,0 +1,7 @@
+-- The canonical form of the course ID, trimmed, upper-cased and Unicode-normalised. It is
+-- compared byte for byte, so two courses cannot have IDs with the same canonical form
+-- whatever the collation of CourseID. The REST API sets it when a course is added or
+-- renamed, and on startup for the courses added before it.
+ALTER TABLE Courses
+    ADD COLUMN CanonicalID VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NULL,
+    ADD UNIQUE INDEX idx_courses_canonical (CanonicalID);
@@ -0,0 +1,14 @@
+-- A student who retakes a course is graded again in the new term, so scores are kept for
+-- each term a student is enrolled in, as enrolments are. Existing scores are given the
+-- term of the student's earliest enrolment in the course, the one they were recorded for.
+ALTER TABLE Scores
+    ADD COLUMN TermID VARCHAR(20) NOT NULL DEFAULT '' AFTER AssessmentID;
+
+UPDATE Scores sc JOIN Assessments a ON a.AssessmentID = sc.AssessmentID
+SET sc.TermID = COALESCE((SELECT e.TermID FROM Enrolments e
+    WHERE e.CourseID = a.CourseID AND e.StudentID = sc.StudentID
+    ORDER BY e.EnrolledDate, e.TermID LIMIT 1), '');
+
+ALTER TABLE Scores
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (AssessmentID, TermID, StudentID);
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AddPrerequisite implements the sql operations to record that a course requires another
+// course to be completed first, as invoked by the REST API. The prerequisite is not added
+// if it already requires the course, directly or indirectly, as the courses could then
+// never be taken; the chain of prerequisites from the prerequisite back to the course is
+// returned instead. Returns whether the prerequisite was added.
+func AddPrerequisite(ctx context.Context, courseID string, prerequisiteID string) (bool, []string) {
+	defer observeCall("AddPrerequisite", time.Now())
+
+	query := "INSERT IGNORE INTO Prerequisites (CourseID, PrerequisiteID, Created_DT) VALUES (?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddPrerequisite", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddPrerequisite")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// Locking every prerequisite, and the gaps between them, holds off any other prerequisite
+	// being added until the transaction ends. Locking the two courses would not do, as two
+	// prerequisites between four different courses can close a cycle between them.
+	graph := queryPrerequisites(ctx, tx, "SELECT CourseID, PrerequisiteID FROM Prerequisites ORDER BY CourseID, PrerequisiteID FOR UPDATE")
+	if path := prerequisitePath(graph, prerequisiteID, courseID); path != nil {
+		return false, path
+	}
+
+	_, err = tx.ExecContext(ctx, query, courseID, prerequisiteID, time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true, nil
+}
+
+// DeletePrerequisite implements the sql operations to remove a prerequisite of a course
+// as invoked by the REST API.
+func DeletePrerequisite(ctx context.Context, courseID string, prerequisiteID string) {
+	defer observeCall("DeletePrerequisite", time.Now())
+
+	query := "DELETE FROM Prerequisites WHERE CourseID=? AND PrerequisiteID=?"
+
+	ctx, span := startSpan(ctx, "DeletePrerequisite", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeletePrerequisite")
+
+	_, err := DB.ExecContext(ctx, query, courseID, prerequisiteID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetPrerequisiteGraph implements the sql operations to retrieve the prerequisites of every
+// course, keyed by course ID, as invoked by the REST API. Courses without prerequisites are
+// not included. Returns nil if the operation failed.
+func GetPrerequisiteGraph(ctx context.Context) map[string][]string {
+	defer observeCall("GetPrerequisiteGraph", time.Now())
+
+	query := "SELECT CourseID, PrerequisiteID FROM Prerequisites ORDER BY CourseID, PrerequisiteID"
+
+	ctx, span := startSpan(ctx, "GetPrerequisiteGraph", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetPrerequisiteGraph")
+
+	return queryPrerequisites(ctx, DB, query)
+}
+
+// queryPrerequisites runs a select of course and prerequisite IDs and returns the
+// prerequisites keyed by course ID. It panics on error to be recovered by the calling function.
+func queryPrerequisites(ctx context.Context, q queryer, query string, args ...interface{}) map[string][]string {
+	// Instantiate graph
+	var graph = make(map[string][]string)
+
+	results, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var courseID, prerequisiteID string
+		err := results.Scan(&courseID, &prerequisiteID)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		graph[courseID] = append(graph[courseID], prerequisiteID)
+	}
+	return graph
+}
+
+// prerequisitePath returns the chain of prerequisites from course from to course to,
+// starting with from, or nil if from does not require to.
+func prerequisitePath(graph map[string][]string, from string, to string) []string {
+	visited := make(map[string]bool)
+
+	var search func(id string) []string
+	search = func(id string) []string {
+		if id == to {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		for _, next := range graph[id] {
+			if path := search(next); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+	return search(from)
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrerequisitePath(t *testing.T) {
+	// GO301 requires GO201, which requires GO101, and DB201 requires DB101 and GO101
+	graph := map[string][]string{
+		"GO301": {"GO201"},
+		"GO201": {"GO101"},
+		"DB201": {"DB101", "GO101"},
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{"direct", "GO201", "GO101", []string{"GO201", "GO101"}},
+		{"indirect", "GO301", "GO101", []string{"GO301", "GO201", "GO101"}},
+		{"second prerequisite", "DB201", "GO101", []string{"DB201", "GO101"}},
+		{"itself", "GO101", "GO101", []string{"GO101"}},
+		{"reverse", "GO101", "GO301", nil},
+		{"unrelated", "DB201", "GO201", nil},
+		{"unknown", "XX101", "GO101", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prerequisitePath(graph, tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("prerequisitePath(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionInfo struct for the json of a weekly class session
+type SessionInfo struct {
+	CourseID  string `json:"CourseID"`
+	Term      string `json:"Term"`
+	Day       string `json:"Day"`   // Mon to Sun
+	StartTime string `json:"Start"` // HH:MM
+	EndTime   string `json:"End"`   // HH:MM
+	Room      string `json:"Room"`
+}
+
+// SessionFilter selects the sessions retrieved by GetSessions. Empty fields match all sessions.
+type SessionFilter struct {
+	CourseID   string
+	Term       string
+	Room       string
+	Instructor string // ID of an instructor assigned to the course
+	Student    string // ID of a student enrolled in the course
+}
+
+// sessionColumns are the columns selected for a SessionInfo, in the order scanned by querySessions.
+const sessionColumns = "s.SessionID, s.CourseID, s.Term, s.Day, TIME_FORMAT(s.StartTime, '%H:%i'), " +
+	"TIME_FORMAT(s.EndTime, '%H:%i'), s.Room"
+
+// AddSession implements the sql operations to insert a new session as invoked by the REST API.
+// The session is not added if it clashes with another session in the same room, or taught by
+// an instructor of the course; the clashes are returned instead. Returns the ID of the new
+// session, or 0 if it was not added.
+func AddSession(ctx context.Context, session SessionInfo) (int64, []string) {
+	defer observeCall("AddSession", time.Now())
+
+	query := "INSERT INTO Sessions (CourseID, Term, Day, StartTime, EndTime, Room, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddSession", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddSession")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if clashes := sessionClashes(ctx, tx, 0, session); len(clashes) != 0 {
+		return 0, clashes
+	}
+
+	result, err := tx.ExecContext(ctx, query, session.CourseID, session.Term, session.Day, session.StartTime,
+		session.EndTime, session.Room, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		panic(fmt.Errorf("error getting id of sql insert: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return id, nil
+}
+
+// UpdateSession implements the sql operations to update a session as invoked by the REST API.
+// As with AddSession, the session is not updated if it would clash with another session, and
+// the clashes are returned. Returns whether the session was updated.
+func UpdateSession(ctx context.Context, sessionID int64, session SessionInfo) (bool, []string) {
+	defer observeCall("UpdateSession", time.Now())
+
+	query := "UPDATE Sessions SET Term=?, Day=?, StartTime=?, EndTime=?, Room=?, LastModified_DT=? WHERE SessionID=?"
+
+	ctx, span := startSpan(ctx, "UpdateSession", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateSession")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if clashes := sessionClashes(ctx, tx, sessionID, session); len(clashes) != 0 {
+		return false, clashes
+	}
+
+	_, err = tx.ExecContext(ctx, query, session.Term, session.Day, session.StartTime, session.EndTime,
+		session.Room, time.Now(), sessionID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+	return true, nil
+}
+
+// DeleteSession implements the sql operations to delete a session as invoked by the REST API.
+func DeleteSession(ctx context.Context, sessionID int64) {
+	defer observeCall("DeleteSession", time.Now())
+
+	query := "DELETE FROM Sessions WHERE SessionID=?"
+
+	ctx, span := startSpan(ctx, "DeleteSession", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteSession")
+
+	_, err := DB.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetSession implements the sql operations to retrieve a session, keyed by session ID,
+// as invoked by the REST API.
+func GetSession(ctx context.Context, sessionID int64) map[string]SessionInfo {
+	defer observeCall("GetSession", time.Now())
+
+	query := "SELECT " + sessionColumns + " FROM Sessions s WHERE s.SessionID=?"
+
+	ctx, span := startSpan(ctx, "GetSession", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetSession")
+
+	return querySessions(ctx, DB, query, sessionID)
+}
+
+// GetSessions implements the sql operations to retrieve the sessions matching the filter,
+// keyed by session ID, as invoked by the REST API.
+func GetSessions(ctx context.Context, filter SessionFilter) map[string]SessionInfo {
+	defer observeCall("GetSessions", time.Now())
+
+	query := "SELECT " + sessionColumns + " FROM Sessions s"
+
+	var where []string
+	var args []interface{}
+	if filter.CourseID != "" {
+		where = append(where, "s.CourseID=?")
+		args = append(args, filter.CourseID)
+	}
+	if filter.Term != "" {
+		where = append(where, "s.Term=?")
+		args = append(args, filter.Term)
+	}
+	if filter.Room != "" {
+		where = append(where, "s.Room=?")
+		args = append(args, filter.Room)
+	}
+	if filter.Instructor != "" {
+		where = append(where, "s.CourseID IN (SELECT CourseID FROM CourseInstructors WHERE InstructorID=?)")
+		args = append(args, filter.Instructor)
+	}
+	if filter.Student != "" {
+		// An enrolment without a term covers the sessions of every term
+		where = append(where, "EXISTS (SELECT 1 FROM Enrolments e WHERE e.CourseID = s.CourseID AND e.StudentID=? "+
+			"AND e.Status=? AND (e.TermID = '' OR e.TermID = s.Term))")
+		args = append(args, filter.Student, Enrolled)
+	}
+	if len(where) != 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	ctx, span := startSpan(ctx, "GetSessions", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetSessions")
+
+	return querySessions(ctx, DB, query, args...)
+}
+
+// instructorClashes returns the sessions of other courses taught by the instructor which
+// clash with the sessions of the course. The course and the terms of its sessions are locked
+// until the end of the transaction, as by sessionClashes, so that clashing sessions cannot
+// be added concurrently. It panics on error to be recovered by the calling function.
+func instructorClashes(ctx context.Context, tx *sql.Tx, courseID string, instructorID string) []string {
+	lockCourse(ctx, tx, courseID)
+
+	// The terms are locked in order, so that two transactions locking several cannot deadlock
+	results, err := tx.QueryContext(ctx, "SELECT Term FROM Sessions WHERE CourseID=? ORDER BY Term FOR UPDATE", courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	var terms []string
+	for results.Next() {
+		var term string
+		if err := results.Scan(&term); err != nil {
+			results.Close()
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		if len(terms) == 0 || terms[len(terms)-1] != term {
+			terms = append(terms, term)
+		}
+	}
+	results.Close()
+	for _, term := range terms {
+		lockTerm(ctx, tx, term)
+	}
+
+	results, err = tx.QueryContext(ctx, "SELECT b.SessionID, b.CourseID, b.Term, b.Day, TIME_FORMAT(b.StartTime, '%H:%i') "+
+		"FROM Sessions a "+
+		"JOIN Sessions b ON b.Term = a.Term AND b.Day = a.Day AND b.StartTime < a.EndTime AND a.StartTime < b.EndTime "+
+		"JOIN CourseInstructors ci ON ci.CourseID = b.CourseID AND ci.InstructorID=? "+
+		"WHERE a.CourseID=? AND b.CourseID <> a.CourseID", instructorID, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	var clashes []string
+	for results.Next() {
+		var sessionID, courseID, term, day, start string
+		if err := results.Scan(&sessionID, &courseID, &term, &day, &start); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		clashes = append(clashes, fmt.Sprintf("instructor %s teaches %s session %s on %s %s at %s",
+			instructorID, courseID, sessionID, term, day, start))
+	}
+	return clashes
+}
+
+// sessionClashes returns the sessions, other than the session with sessionID, which clash
+// with the session given by using the same room, or an instructor of its course, at an
+// overlapping time. The course and the term of the session are locked until the end of the
+// transaction so that clashing sessions cannot be added, nor clashing instructors assigned,
+// concurrently. It panics on error to be recovered by the calling function.
+func sessionClashes(ctx context.Context, tx *sql.Tx, sessionID int64, session SessionInfo) []string {
+	// Sessions only clash within a term. Locking the clashing sessions would not do, as there
+	// are none to lock while the first of two clashing sessions is being added. The course is
+	// locked first, as by instructorClashes, so that the locks are taken in the same order.
+	lockCourse(ctx, tx, session.CourseID)
+	lockTerm(ctx, tx, session.Term)
+
+	overlap := "s.Term=? AND s.Day=? AND s.StartTime < ? AND s.EndTime > ? AND s.SessionID <> ?"
+	args := []interface{}{session.Term, session.Day, session.EndTime, session.StartTime, sessionID}
+
+	var clashes []string
+	for id, s := range querySessions(ctx, tx, "SELECT "+sessionColumns+" FROM Sessions s WHERE "+overlap+
+		" AND s.Room=?", append(args, session.Room)...) {
+		clashes = append(clashes, fmt.Sprintf("room %s is used by %s session %s at %s-%s",
+			s.Room, s.CourseID, id, s.StartTime, s.EndTime))
+	}
+
+	results, err := tx.QueryContext(ctx, "SELECT s.SessionID, s.CourseID, TIME_FORMAT(s.StartTime, '%H:%i'), "+
+		"TIME_FORMAT(s.EndTime, '%H:%i'), ci.InstructorID FROM Sessions s "+
+		"JOIN CourseInstructors ci ON ci.CourseID = s.CourseID "+
+		"WHERE "+overlap+" AND ci.InstructorID IN (SELECT InstructorID FROM CourseInstructors WHERE CourseID=?)",
+		append(args, session.CourseID)...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var id, courseID, start, end, instructorID string
+		if err := results.Scan(&id, &courseID, &start, &end, &instructorID); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		clashes = append(clashes, fmt.Sprintf("instructor %s teaches %s session %s at %s-%s",
+			instructorID, courseID, id, start, end))
+	}
+
+	sort.Strings(clashes)
+	return clashes
+}
+
+// lockCourse locks the row of the course until the end of the transaction. It is locked
+// before any term by the transactions checking for clashes, which read nothing else before
+// their locks, so that their later reads see the sessions committed while they waited.
+// It panics on error to be recovered by the calling function.
+func lockCourse(ctx context.Context, tx *sql.Tx, courseID string) {
+	var id string
+	err := tx.QueryRowContext(ctx, "SELECT CourseID FROM Courses WHERE CourseID=? FOR UPDATE", courseID).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+}
+
+// lockTerm locks the row of the term until the end of the transaction. It panics on error to
+// be recovered by the calling function.
+func lockTerm(ctx context.Context, tx *sql.Tx, termID string) {
+	var id string
+	err := tx.QueryRowContext(ctx, "SELECT TermID FROM Terms WHERE TermID=? FOR UPDATE", termID).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// querySessions runs a select of sessionColumns and returns the sessions keyed by session ID.
+// It panics on error to be recovered by the calling function.
+func querySessions(ctx context.Context, q queryer, query string, args ...interface{}) map[string]SessionInfo {
+	// Instantiate sessions
+	var sessions = make(map[string]SessionInfo)
+
+	results, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var sessionID int64
+		var session SessionInfo
+		err := results.Scan(&sessionID, &session.CourseID, &session.Term, &session.Day,
+			&session.StartTime, &session.EndTime, &session.Room)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		sessions[strconv.FormatInt(sessionID, 10)] = session
+	}
+	return sessions
+}
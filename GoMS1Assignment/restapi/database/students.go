@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StudentInfo struct for the json
+type StudentInfo struct {
+	Name        string `json:"Name"`
+	Email       string `json:"Email"`
+	DateOfBirth string `json:"DateOfBirth"` // YYYY-MM-DD
+	Status      string `json:"Status"`
+}
+
+// AddStudent implements the sql operations to insert a new student as invoked by the REST API.
+func AddStudent(ctx context.Context, studentID string, student StudentInfo) {
+	defer observeCall("AddStudent", time.Now())
+
+	query := "INSERT INTO Students (StudentID, Name, Email, DateOfBirth, Status, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddStudent", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddStudent")
+
+	_, err := DB.ExecContext(ctx, query, studentID, student.Name, student.Email, student.DateOfBirth, student.Status, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+}
+
+// UpdateStudent implements the sql operations to update a student as invoked by the REST API.
+func UpdateStudent(ctx context.Context, studentID string, student StudentInfo) {
+	defer observeCall("UpdateStudent", time.Now())
+
+	query := "UPDATE Students SET Name=?, Email=?, DateOfBirth=?, Status=?, LastModified_DT=? WHERE StudentID=?"
+
+	ctx, span := startSpan(ctx, "UpdateStudent", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateStudent")
+
+	_, err := DB.ExecContext(ctx, query, student.Name, student.Email, student.DateOfBirth, student.Status, time.Now(), studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
+
+// DeleteStudent implements the sql operations to delete a student as invoked by the REST API.
+func DeleteStudent(ctx context.Context, studentID string) {
+	defer observeCall("DeleteStudent", time.Now())
+
+	query := "DELETE FROM Students WHERE StudentID=?"
+
+	ctx, span := startSpan(ctx, "DeleteStudent", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteStudent")
+
+	_, err := DB.ExecContext(ctx, query, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetStudent implements the sql operations to retrieve a student as invoked by the REST API.
+func GetStudent(ctx context.Context, studentID string) map[string]StudentInfo {
+	defer observeCall("GetStudent", time.Now())
+
+	query := "SELECT StudentID, Name, Email, DateOfBirth, Status FROM Students WHERE StudentID=?"
+
+	ctx, span := startSpan(ctx, "GetStudent", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetStudent")
+
+	return queryStudents(ctx, query, studentID)
+}
+
+// GetAllStudents implements the sql operations to retrieve all students as invoked by the REST API.
+func GetAllStudents(ctx context.Context) map[string]StudentInfo {
+	defer observeCall("GetAllStudents", time.Now())
+
+	query := "SELECT StudentID, Name, Email, DateOfBirth, Status FROM Students"
+
+	ctx, span := startSpan(ctx, "GetAllStudents", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllStudents")
+
+	return queryStudents(ctx, query)
+}
+
+// queryStudents runs a select of student columns and returns the students keyed by student ID.
+// It panics on error to be recovered by the calling function.
+func queryStudents(ctx context.Context, query string, args ...interface{}) map[string]StudentInfo {
+	// Instantiate students
+	var students = make(map[string]StudentInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var studentID string
+		var student StudentInfo
+		err := results.Scan(&studentID, &student.Name, &student.Email, &student.DateOfBirth, &student.Status)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		students[studentID] = student
+	}
+	return students
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SetCourseTags implements the sql operations to replace the tags of a course as invoked by
+// the REST API.
+func SetCourseTags(ctx context.Context, courseID string, tags []string) {
+	defer observeCall("SetCourseTags", time.Now())
+
+	query := "INSERT IGNORE INTO CourseTags (CourseID, Tag, Created_DT) VALUES (?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "SetCourseTags", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetCourseTags")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM CourseTags WHERE CourseID=?", courseID); err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error preparing sql insert: %w", err))
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, tag := range tags {
+		if _, err := stmt.ExecContext(ctx, courseID, tag, now); err != nil {
+			panic(fmt.Errorf("error executing sql insert: %w", err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// AddCourseTag implements the sql operations to tag a course as invoked by the REST API.
+// Returns whether the course was not already tagged.
+func AddCourseTag(ctx context.Context, courseID string, tag string) bool {
+	defer observeCall("AddCourseTag", time.Now())
+
+	query := "INSERT IGNORE INTO CourseTags (CourseID, Tag, Created_DT) VALUES (?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddCourseTag", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddCourseTag")
+
+	result, err := DB.ExecContext(ctx, query, courseID, tag, time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	added, err := result.RowsAffected()
+	if err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql insert: %w", err))
+	}
+	return added != 0
+}
+
+// DeleteCourseTag implements the sql operations to remove a tag from a course as invoked by
+// the REST API. Returns whether the course was tagged.
+func DeleteCourseTag(ctx context.Context, courseID string, tag string) bool {
+	defer observeCall("DeleteCourseTag", time.Now())
+
+	query := "DELETE FROM CourseTags WHERE CourseID=? AND Tag=?"
+
+	ctx, span := startSpan(ctx, "DeleteCourseTag", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteCourseTag")
+
+	result, err := DB.ExecContext(ctx, query, courseID, tag)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		panic(fmt.Errorf("error getting rows affected by sql delete: %w", err))
+	}
+	return deleted != 0
+}
+
+// RenameTag implements the sql operations to rename a tag on every course, merging it into
+// the new tag on courses which have both, as invoked by the REST API.
+func RenameTag(ctx context.Context, tag string, newTag string) {
+	defer observeCall("RenameTag", time.Now())
+
+	query := "INSERT IGNORE INTO CourseTags (CourseID, Tag, Created_DT) SELECT CourseID, ?, Created_DT FROM CourseTags WHERE Tag=?"
+
+	ctx, span := startSpan(ctx, "RenameTag", query)
+	defer span.End()
+	defer recoverPanic(ctx, "RenameTag")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, newTag, tag); err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM CourseTags WHERE Tag=? AND Tag<>?", tag, newTag); err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// DeleteTag implements the sql operations to remove a tag from every course as invoked by
+// the REST API.
+func DeleteTag(ctx context.Context, tag string) {
+	defer observeCall("DeleteTag", time.Now())
+
+	query := "DELETE FROM CourseTags WHERE Tag=?"
+
+	ctx, span := startSpan(ctx, "DeleteTag", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteTag")
+
+	_, err := DB.ExecContext(ctx, query, tag)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetAllTags implements the sql operations to retrieve the number of courses with each tag,
+// keyed by tag, as invoked by the REST API.
+func GetAllTags(ctx context.Context) map[string]int {
+	defer observeCall("GetAllTags", time.Now())
+
+	query := "SELECT Tag, COUNT(*) FROM CourseTags GROUP BY Tag"
+
+	ctx, span := startSpan(ctx, "GetAllTags", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllTags")
+
+	// Instantiate tags
+	var tags = make(map[string]int)
+
+	results, err := DB.QueryContext(ctx, query)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var tag string
+		var count int
+		if err := results.Scan(&tag, &count); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		tags[tag] = count
+	}
+	return tags
+}
+
+// queryTags runs a select of course tag columns and returns the tags keyed by course ID,
+// in the order selected. It panics on error to be recovered by the calling function.
+func queryTags(ctx context.Context, query string, args ...interface{}) map[string][]string {
+	// Instantiate tags
+	var tags = make(map[string][]string)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var courseID, tag string
+		if err := results.Scan(&courseID, &tag); err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		tags[courseID] = append(tags[courseID], tag)
+	}
+	return tags
+}
+
+// withTags sets the tags of each course from the tags keyed by course ID.
+func withTags(courses map[string]courseInfo, tags map[string][]string) map[string]courseInfo {
+	for courseID, course := range courses {
+		course.Tags = tags[courseID]
+		courses[courseID] = course
+	}
+	return courses
+}
@@ -0,0 +1,276 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TermInfo struct for the json
+type TermInfo struct {
+	Name      string `json:"Name"`
+	StartDate string `json:"StartDate"` // YYYY-MM-DD
+	EndDate   string `json:"EndDate"`   // YYYY-MM-DD
+}
+
+// OfferingInfo struct for the json of a course offered in a term
+type OfferingInfo struct {
+	Title      string `json:"Title"` // title of the course
+	Term       string `json:"Term"`  // name of the term
+	Capacity   int    `json:"Capacity"`
+	Enrolled   int    `json:"Enrolled"`
+	Waitlisted int    `json:"Waitlisted"`
+}
+
+// AddTerm implements the sql operations to insert a new term as invoked by the REST API.
+func AddTerm(ctx context.Context, termID string, term TermInfo) {
+	defer observeCall("AddTerm", time.Now())
+
+	query := "INSERT INTO Terms (TermID, Name, StartDate, EndDate, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?, ?)"
+
+	ctx, span := startSpan(ctx, "AddTerm", query)
+	defer span.End()
+	defer recoverPanic(ctx, "AddTerm")
+
+	_, err := DB.ExecContext(ctx, query, termID, term.Name, term.StartDate, term.EndDate, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+}
+
+// UpdateTerm implements the sql operations to update a term as invoked by the REST API.
+func UpdateTerm(ctx context.Context, termID string, term TermInfo) {
+	defer observeCall("UpdateTerm", time.Now())
+
+	query := "UPDATE Terms SET Name=?, StartDate=?, EndDate=?, LastModified_DT=? WHERE TermID=?"
+
+	ctx, span := startSpan(ctx, "UpdateTerm", query)
+	defer span.End()
+	defer recoverPanic(ctx, "UpdateTerm")
+
+	_, err := DB.ExecContext(ctx, query, term.Name, term.StartDate, term.EndDate, time.Now(), termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql update: %w", err))
+	}
+}
+
+// DeleteTerm implements the sql operations to delete a term, with the offerings, sessions,
+// enrolments and scores in it, as invoked by the REST API.
+func DeleteTerm(ctx context.Context, termID string) {
+	defer observeCall("DeleteTerm", time.Now())
+
+	query := "DELETE FROM Terms WHERE TermID=?"
+
+	ctx, span := startSpan(ctx, "DeleteTerm", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteTerm")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// Enrolments have no foreign key to the term, as those outside any term have a TermID of ''
+	_, err = tx.ExecContext(ctx, "DELETE FROM Enrolments WHERE TermID=?", termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM Scores WHERE TermID=?", termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	_, err = tx.ExecContext(ctx, query, termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// GetTerm implements the sql operations to retrieve a term as invoked by the REST API.
+func GetTerm(ctx context.Context, termID string) map[string]TermInfo {
+	defer observeCall("GetTerm", time.Now())
+
+	query := "SELECT TermID, Name, StartDate, EndDate FROM Terms WHERE TermID=?"
+
+	ctx, span := startSpan(ctx, "GetTerm", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetTerm")
+
+	return queryTerms(ctx, query, termID)
+}
+
+// GetAllTerms implements the sql operations to retrieve all terms as invoked by the REST API.
+func GetAllTerms(ctx context.Context) map[string]TermInfo {
+	defer observeCall("GetAllTerms", time.Now())
+
+	query := "SELECT TermID, Name, StartDate, EndDate FROM Terms"
+
+	ctx, span := startSpan(ctx, "GetAllTerms", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetAllTerms")
+
+	return queryTerms(ctx, query)
+}
+
+// queryTerms runs a select of term columns and returns the terms keyed by term ID.
+// It panics on error to be recovered by the calling function.
+func queryTerms(ctx context.Context, query string, args ...interface{}) map[string]TermInfo {
+	// Instantiate terms
+	var terms = make(map[string]TermInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var termID string
+		var term TermInfo
+		err := results.Scan(&termID, &term.Name, &term.StartDate, &term.EndDate)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		terms[termID] = term
+	}
+	return terms
+}
+
+// SetOffering implements the sql operations to offer a course in a term with the capacity
+// given, or change the capacity of an existing offering, as invoked by the REST API.
+// Waitlisted students are enrolled into any seats added.
+func SetOffering(ctx context.Context, courseID string, termID string, capacity int) {
+	defer observeCall("SetOffering", time.Now())
+
+	query := "INSERT INTO Offerings (CourseID, TermID, Capacity, Created_DT, LastModified_DT) VALUES (?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE Capacity=VALUES(Capacity), LastModified_DT=VALUES(LastModified_DT)"
+
+	ctx, span := startSpan(ctx, "SetOffering", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetOffering")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query, courseID, termID, capacity, time.Now(), time.Now())
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+
+	promoteWaitlisted(ctx, tx, courseID, termID)
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// DeleteOffering implements the sql operations to withdraw the offering of a course in a term,
+// with its sessions, enrolments and scores, as invoked by the REST API.
+func DeleteOffering(ctx context.Context, courseID string, termID string) {
+	defer observeCall("DeleteOffering", time.Now())
+
+	query := "DELETE FROM Offerings WHERE CourseID=? AND TermID=?"
+
+	ctx, span := startSpan(ctx, "DeleteOffering", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteOffering")
+
+	tx, err := DB.BeginTx(ctx, nil)
+	if err != nil {
+		panic(fmt.Errorf("error beginning transaction: %w", err))
+	}
+	defer tx.Rollback()
+
+	// Enrolments have no foreign key to the offering, as those outside any term have a TermID of ''
+	_, err = tx.ExecContext(ctx, "DELETE FROM Enrolments WHERE CourseID=? AND TermID=?", courseID, termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE sc FROM Scores sc JOIN Assessments a ON a.AssessmentID = sc.AssessmentID "+
+		"WHERE a.CourseID=? AND sc.TermID=?", courseID, termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	_, err = tx.ExecContext(ctx, query, courseID, termID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		panic(fmt.Errorf("error committing transaction: %w", err))
+	}
+}
+
+// GetTermOfferings implements the sql operations to retrieve the courses offered in a term,
+// keyed by course ID, as invoked by the REST API.
+func GetTermOfferings(ctx context.Context, termID string) map[string]OfferingInfo {
+	defer observeCall("GetTermOfferings", time.Now())
+
+	query := offeringSelect + " WHERE o.TermID=?"
+
+	ctx, span := startSpan(ctx, "GetTermOfferings", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetTermOfferings")
+
+	return queryOfferings(ctx, query, true, termID)
+}
+
+// GetCourseOfferings implements the sql operations to retrieve the terms a course is offered
+// in, keyed by term ID, as invoked by the REST API.
+func GetCourseOfferings(ctx context.Context, courseID string) map[string]OfferingInfo {
+	defer observeCall("GetCourseOfferings", time.Now())
+
+	query := offeringSelect + " WHERE o.CourseID=?"
+
+	ctx, span := startSpan(ctx, "GetCourseOfferings", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCourseOfferings")
+
+	return queryOfferings(ctx, query, false, courseID)
+}
+
+// offeringSelect selects the columns of an offering scanned by queryOfferings.
+const offeringSelect = "SELECT o.CourseID, o.TermID, c.CourseTitle, t.Name, o.Capacity, " +
+	"(SELECT COUNT(*) FROM Enrolments e WHERE e.CourseID = o.CourseID AND e.TermID = o.TermID AND e.Status='" + Enrolled + "'), " +
+	"(SELECT COUNT(*) FROM Enrolments e WHERE e.CourseID = o.CourseID AND e.TermID = o.TermID AND e.Status='" + Waitlisted + "') " +
+	"FROM Offerings o JOIN Courses c ON c.CourseID = o.CourseID JOIN Terms t ON t.TermID = o.TermID"
+
+// queryOfferings runs a select of offeringSelect and returns the offerings keyed by course ID,
+// or by term ID if byCourse is false. It panics on error to be recovered by the calling function.
+func queryOfferings(ctx context.Context, query string, byCourse bool, args ...interface{}) map[string]OfferingInfo {
+	// Instantiate offerings
+	var offerings = make(map[string]OfferingInfo)
+
+	results, err := DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var courseID, termID string
+		var offering OfferingInfo
+		err := results.Scan(&courseID, &termID, &offering.Title, &offering.Term, &offering.Capacity,
+			&offering.Enrolled, &offering.Waitlisted)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		if byCourse {
+			offerings[courseID] = offering
+		} else {
+			offerings[termID] = offering
+		}
+	}
+	return offerings
+}
@@ -0,0 +1,22 @@
+package database
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("GoMS1Assignment/restapi/database")
+
+// startSpan starts a client span for the database function, as a child of any span in ctx.
+// The returned context carries the new span and must be used for the sql operations.
+func startSpan(ctx context.Context, function string, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "database."+function,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			attribute.String("db.statement", query)))
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CompletionInfo struct for the json of a course completed by a student in a term
+type CompletionInfo struct {
+	TermID        string  `json:"TermID"`
+	CourseID      string  `json:"CourseID"`
+	Title         string  `json:"Title"`
+	Grade         string  `json:"Grade"`
+	Credits       float64 `json:"Credits"`
+	CompletedDate string  `json:"CompletedDate"` // YYYY-MM-DD
+}
+
+// SetCompletion implements the sql operations to record that a student completed a course in
+// a term with the grade and credits given, or to correct the record, as invoked by the REST API.
+func SetCompletion(ctx context.Context, studentID string, completion CompletionInfo) {
+	defer observeCall("SetCompletion", time.Now())
+
+	query := "INSERT INTO Completions (StudentID, TermID, CourseID, Grade, Credits, CompletedDate, Created_DT, LastModified_DT) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE Grade=VALUES(Grade), Credits=VALUES(Credits), " +
+		"LastModified_DT=VALUES(LastModified_DT)"
+
+	ctx, span := startSpan(ctx, "SetCompletion", query)
+	defer span.End()
+	defer recoverPanic(ctx, "SetCompletion")
+
+	now := time.Now()
+	_, err := DB.ExecContext(ctx, query, studentID, completion.TermID, completion.CourseID, completion.Grade,
+		completion.Credits, now.Format("2006-01-02"), now, now)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql insert: %w", err))
+	}
+}
+
+// DeleteCompletion implements the sql operations to delete the record of a course completed
+// by a student in a term as invoked by the REST API.
+func DeleteCompletion(ctx context.Context, studentID string, termID string, courseID string) {
+	defer observeCall("DeleteCompletion", time.Now())
+
+	query := "DELETE FROM Completions WHERE StudentID=? AND TermID=? AND CourseID=?"
+
+	ctx, span := startSpan(ctx, "DeleteCompletion", query)
+	defer span.End()
+	defer recoverPanic(ctx, "DeleteCompletion")
+
+	_, err := DB.ExecContext(ctx, query, studentID, termID, courseID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql delete: %w", err))
+	}
+}
+
+// GetCompletions implements the sql operations to retrieve the courses completed by a student,
+// ordered by the start date of their term and then course ID, as invoked by the REST API.
+func GetCompletions(ctx context.Context, studentID string) []CompletionInfo {
+	defer observeCall("GetCompletions", time.Now())
+
+	query := "SELECT cp.TermID, cp.CourseID, c.CourseTitle, cp.Grade, cp.Credits, cp.CompletedDate FROM Completions cp " +
+		"JOIN Courses c ON c.CourseID = cp.CourseID JOIN Terms t ON t.TermID = cp.TermID " +
+		"WHERE cp.StudentID=? ORDER BY t.StartDate, cp.TermID, cp.CourseID"
+
+	ctx, span := startSpan(ctx, "GetCompletions", query)
+	defer span.End()
+	defer recoverPanic(ctx, "GetCompletions")
+
+	// Instantiate completions
+	var completions []CompletionInfo
+
+	results, err := DB.QueryContext(ctx, query, studentID)
+	if err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	defer results.Close()
+
+	for results.Next() {
+		var completion CompletionInfo
+		err := results.Scan(&completion.TermID, &completion.CourseID, &completion.Title, &completion.Grade,
+			&completion.Credits, &completion.CompletedDate)
+		if err != nil {
+			panic(fmt.Errorf("error getting results from sql select: %w", err))
+		}
+		completions = append(completions, completion)
+	}
+	return completions
+}
+
+// CountTermCompletions implements the sql operations to count the courses completed in a
+// term as invoked by the REST API.
+func CountTermCompletions(ctx context.Context, termID string) int {
+	defer observeCall("CountTermCompletions", time.Now())
+
+	query := "SELECT COUNT(*) FROM Completions WHERE TermID=?"
+
+	ctx, span := startSpan(ctx, "CountTermCompletions", query)
+	defer span.End()
+	defer recoverPanic(ctx, "CountTermCompletions")
+
+	var count int
+	if err := DB.QueryRowContext(ctx, query, termID).Scan(&count); err != nil {
+		panic(fmt.Errorf("error executing sql select: %w", err))
+	}
+	return count
+}
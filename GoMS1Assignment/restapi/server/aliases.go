@@ -0,0 +1,95 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+)
+
+// renameInfo struct for the json of a rename of a course
+type renameInfo struct {
+	CourseID string `json:"CourseID"` // the new course ID
+}
+
+// rename is the handler function to change the ID of a course with POST. The records of the
+// course, such as its enrolments, sessions and gradebook, move to the new ID, and GET on the
+// old ID redirects to the new one.
+func rename(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	// Check if course exists, and get its ID as stored, which may predate canonical IDs
+	courseID := ""
+	for id := range database.GetCourse(r.Context(), params["courseid"]) {
+		courseID = id
+	}
+	if courseID == "" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	var req renameInfo
+
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Check the new course ID against the rules
+	newID := validation.CanonicalCourseID(req.CourseID)
+	if errs := rules.ValidateCourseID(newID); len(errs) != 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+	if newID == courseID {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply a new CourseID"))
+		return
+	}
+
+	// The new ID may be a former ID of the course, but not the ID or former ID of another,
+	// which the database rejects. A course only renamed to the canonical form of its ID needs
+	// no alias.
+	keepAlias := validation.CanonicalCourseID(courseID) != newID
+	if err := database.RenameCourse(r.Context(), courseID, newID, keepAlias, keyLabel(r)); errors.Is(err, database.ErrDuplicateCourseID) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Course ID is in use by another course"))
+		return
+	} else if errors.Is(err, database.ErrNoCourse) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Course was changed by another request"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Error renaming course"))
+		return
+	}
+
+	w.Header().Set("Location", coursePath(newID))
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Course renamed: " + newID))
+}
+
+// redirectRenamed redirects a request for a course which was renamed to the course under
+// its new ID, keeping the query string so the access key is passed on.
+func redirectRenamed(w http.ResponseWriter, r *http.Request, newID string) {
+	location := coursePath(newID)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, location, http.StatusMovedPermanently)
+}
+
+// coursePath returns the path of the course with the course ID given.
+func coursePath(courseID string) string {
+	return "/api/v1/courses/" + url.PathEscape(courseID)
+}
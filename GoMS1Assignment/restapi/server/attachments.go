@@ -0,0 +1,227 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/blobstore"
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kennygrant/sanitize"
+)
+
+// defaultMaxAttachmentMB is the maximum size in MiB of an uploaded attachment, unless
+// MAX_ATTACHMENT_MB is set in setup.env.
+const defaultMaxAttachmentMB = 8
+
+// attachmentTypes are the content types of the files which may be attached to a course,
+// keyed by file extension, with the type sniffed from the contents of such a file.
+var attachmentTypes = map[string]struct{ contentType, sniffed string }{
+	".pdf":  {"application/pdf", "application/pdf"},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip"},
+}
+
+// blobs stores the files of attachments.
+var blobs *blobstore.Store
+
+// initBlobs opens the blob store in BLOB_DIR from setup.env, or in blobs if it is not set.
+func initBlobs() {
+	dir := os.Getenv("BLOB_DIR")
+	if dir == "" {
+		dir = "blobs"
+	}
+
+	var err error
+	blobs, err = blobstore.New(dir)
+	if err != nil {
+		slog.Error("error initialising blob store", "error", err)
+		os.Exit(1)
+	}
+}
+
+// attachments is the handler function to retrieve the attachments of a course with GET,
+// keyed by attachment ID, and to upload a PDF or DOCX file as the "file" field of a
+// multipart form with POST.
+func attachments(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		json.NewEncoder(w).Encode(database.GetAttachments(r.Context(), params["courseid"]))
+	case "POST":
+		uploadAttachment(params, w, r)
+	}
+}
+
+// attachment is the handler function to download an attachment of a course with GET,
+// and to delete it with DELETE.
+func attachment(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	attachmentID, _ := strconv.ParseInt(params["attachmentid"], 10, 64)
+	a, ok := database.GetAttachment(r.Context(), attachmentID)[params["attachmentid"]]
+	if !ok || a.CourseID != params["courseid"] {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No attachment found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		f, err := blobs.Open(a.BlobKey)
+		if err != nil {
+			logger(r.Context()).Error("error opening attachment file", "attachmentid", attachmentID, "error", err)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No attachment found"))
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", a.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+		http.ServeContent(w, r, a.FileName, time.Time{}, f)
+	case "DELETE":
+		// The file is kept unless the attachment is deleted, so no attachment is left without one
+		if err := database.DeleteAttachment(r.Context(), attachmentID); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Error deleting attachment"))
+			return
+		}
+		if err := blobs.Delete(a.BlobKey); err != nil {
+			logger(r.Context()).Error("error deleting attachment file", "attachmentid", attachmentID, "error", err)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Attachment deleted"))
+	}
+}
+
+// uploadAttachment implements the POST method invoked by the client and stores the uploaded
+// file as an attachment of the course. Returns the attachment keyed by its new attachment ID.
+func uploadAttachment(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	maxSize := maxAttachmentSize()
+
+	// Allow for the boundaries and headers of the multipart form around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+64<<10)
+	err := r.ParseMultipartForm(1 << 20)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			writeTooLarge(w, maxSize)
+		case errors.Is(err, http.ErrNotMultipart):
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			w.Write([]byte("415 - Please upload the file as multipart/form-data"))
+		default:
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - Please upload the file as multipart/form-data"))
+		}
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply the file in the file field"))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		writeTooLarge(w, maxSize)
+		return
+	}
+
+	fileName := sanitize.Name(filepath.Base(header.Filename))
+	fileType, ok := attachmentTypes[strings.ToLower(filepath.Ext(fileName))]
+	if !ok || sniffType(file) != fileType.sniffed {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte("415 - Please upload a PDF or DOCX file"))
+		return
+	}
+
+	key, size, err := blobs.Put(file)
+	if err != nil {
+		logger(r.Context()).Error("error storing attachment file", "courseid", params["courseid"], "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Attachment could not be stored"))
+		return
+	}
+
+	newAttachment := database.AttachmentInfo{
+		CourseID:    params["courseid"],
+		FileName:    fileName,
+		ContentType: fileType.contentType,
+		Size:        size,
+		BlobKey:     key,
+		Uploaded:    time.Now().Format("2006-01-02 15:04:05"),
+	}
+	id := database.AddAttachment(r.Context(), newAttachment)
+	if id == 0 {
+		// The file is of no use without the attachment
+		if err := blobs.Delete(key); err != nil {
+			logger(r.Context()).Error("error deleting attachment file", "courseid", params["courseid"], "error", err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Attachment could not be stored"))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]database.AttachmentInfo{strconv.FormatInt(id, 10): newAttachment})
+}
+
+// sniffType returns the content type of the file sniffed from its first 512 bytes,
+// and rewinds it for the file to be stored.
+func sniffType(file io.ReadSeeker) string {
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ""
+	}
+	return http.DetectContentType(head[:n])
+}
+
+// writeTooLarge writes the 413 response for an upload larger than maxSize bytes.
+func writeTooLarge(w http.ResponseWriter, maxSize int64) {
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	w.Write([]byte("413 - Please upload a file of at most " + strconv.FormatInt(maxSize>>20, 10) + " MB"))
+}
+
+// maxAttachmentSize returns the maximum size in bytes of an attachment from MAX_ATTACHMENT_MB
+// in setup.env, or defaultMaxAttachmentMB if it is not set to a positive number.
+func maxAttachmentSize() int64 {
+	mb, err := strconv.ParseInt(os.Getenv("MAX_ATTACHMENT_MB"), 10, 64)
+	if err != nil || mb <= 0 {
+		mb = defaultMaxAttachmentMB
+	}
+	return mb << 20
+}
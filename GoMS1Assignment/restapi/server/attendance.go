@@ -0,0 +1,441 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultAttendanceThreshold is the attendance percentage below which students are alerted
+// on, unless ATTENDANCE_THRESHOLD is set in setup.env.
+const defaultAttendanceThreshold = 75
+
+// attendanceReportInfo struct for the json of the attendance of a student at the classes of a course
+type attendanceReportInfo struct {
+	Name    string  `json:"Name"`
+	Present int     `json:"Present"`
+	Absent  int     `json:"Absent"`
+	Late    int     `json:"Late"`
+	Excused int     `json:"Excused"`
+	Percent float64 `json:"Percent"` // classes attended, present or late, of those not excused
+	Alert   bool    `json:"Alert"`   // whether the percentage is below the threshold
+}
+
+// classes is the handler function to retrieve the dated classes of a course with GET and
+// add a class with POST.
+func classes(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving classes
+		json.NewEncoder(w).Encode(database.GetClasses(r.Context(), params["courseid"]))
+	case "POST": // POST is for creating new class
+		newClass, ok := convertClassJSON(w, r, params["courseid"])
+		if !ok {
+			return
+		}
+
+		if duplicateClass(r, newClass, "") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Class already held on " + newClass.Date))
+			return
+		}
+
+		id := database.AddClass(r.Context(), newClass)
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]database.ClassInfo{strconv.FormatInt(id, 10): newClass})
+	}
+}
+
+// class is the handler function to retrieve, update and delete a class of a course.
+// Deleting a class deletes its attendance records.
+func class(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	classID, current, ok := findClass(w, r, params)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving class
+		json.NewEncoder(w).Encode(map[string]database.ClassInfo{params["classid"]: current})
+	case "PUT": //---PUT is for updating class
+		newClass, ok := convertClassJSON(w, r, params["courseid"])
+		if !ok {
+			return
+		}
+
+		if duplicateClass(r, newClass, params["classid"]) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Class already held on " + newClass.Date))
+			return
+		}
+
+		database.UpdateClass(r.Context(), classID, newClass)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Class updated"))
+	case "DELETE": // DELETE is for deleting class
+		database.DeleteClass(r.Context(), classID)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Class deleted"))
+	}
+}
+
+// attendance is the handler function to retrieve the attendance at a class with GET, keyed
+// by student ID, and to mark attendance with PUT. GET includes the students enrolled in, or
+// who have completed, the course in the term of the class who have not been marked, with an
+// empty status. With ?others=<status>, PUT also marks the enrolled students not in the request.
+func attendance(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	classID, current, ok := findClass(w, r, params)
+	if !ok {
+		return
+	}
+	termID := classTerm(r, current)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the attendance
+		roll := database.GetAttendance(r.Context(), classID)
+		for studentID, name := range gradedStudents(r, params["courseid"], termID) {
+			if _, ok := roll[studentID]; !ok {
+				roll[studentID] = database.AttendanceInfo{Name: name}
+			}
+		}
+		json.NewEncoder(w).Encode(roll)
+	case "PUT": // PUT is for marking the attendance
+		var marks map[string]string
+
+		if !decodeJSON(w, r, &marks) {
+			return
+		}
+
+		err := validateAttendance(marks, gradedStudents(r, params["courseid"], termID))
+		if others := r.URL.Query().Get("others"); err == nil && others != "" {
+			if !validAttendanceStatus(others) {
+				err = errors.New("Please supply others as present, absent, late or excused")
+			} else {
+				if marks == nil {
+					marks = make(map[string]string)
+				}
+				for _, e := range database.GetEnrolments(r.Context(), params["courseid"]) {
+					if _, ok := marks[e.StudentID]; !ok && e.Term == termID && e.Status == database.Enrolled {
+						marks[e.StudentID] = others
+					}
+				}
+			}
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+
+		database.SetAttendance(r.Context(), classID, marks)
+		logAttendanceAlerts(r, params["courseid"], termID, marks)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Attendance marked: " + strconv.Itoa(len(marks))))
+	}
+}
+
+// courseAttendance is the handler function to retrieve the attendance report of a course,
+// keyed by student ID, for the students enrolled in, or who have completed, the course in
+// the term given with ?term=, or else outside any term. The threshold of the alerts is set
+// with ?threshold=.
+func courseAttendance(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	threshold, ok := attendanceThreshold(w, r)
+	if !ok {
+		return
+	}
+
+	termID := r.URL.Query().Get("term")
+	json.NewEncoder(w).Encode(courseAttendanceReport(gradedStudents(r, params["courseid"], termID),
+		database.GetAttendanceTallies(r.Context(), params["courseid"], ""), termID, threshold))
+}
+
+// courseAttendanceReport returns the attendance report of the students given, keyed by
+// student ID, from their tallies in the term given. Tallies of other terms are left out,
+// and students with no tally have attended 100%.
+func courseAttendanceReport(students map[string]string, tallies []database.AttendanceTally, termID string, threshold float64) map[string]attendanceReportInfo {
+	var report = make(map[string]attendanceReportInfo)
+	for studentID, name := range students {
+		report[studentID] = attendanceReport(database.AttendanceTally{Name: name}, threshold)
+	}
+	for _, t := range tallies {
+		if t.TermID == termID {
+			report[t.StudentID] = attendanceReport(t, threshold)
+		}
+	}
+	return report
+}
+
+// learnerAttendance is the handler function to retrieve the attendance report of a learner,
+// keyed by course ID, for the courses they are enrolled in or have completed. A course
+// taken in more than one term is reported for the last term, by term ID. The threshold of
+// the alerts is set with ?threshold=.
+func learnerAttendance(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	student, ok := database.GetStudent(r.Context(), params["id"])[params["id"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	threshold, ok := attendanceThreshold(w, r)
+	if !ok {
+		return
+	}
+
+	// The learner's courses are ordered by course ID and term ID
+	var report = make(map[string]attendanceReportInfo)
+	var terms = make(map[string]string) // term reported, keyed by course ID
+	for _, c := range database.GetLearnerCourses(r.Context(), params["id"]) {
+		if c.Status == database.Enrolled || c.Status == database.Completed {
+			report[c.CourseID] = attendanceReport(database.AttendanceTally{Name: student.Name}, threshold)
+			terms[c.CourseID] = c.Term
+		}
+	}
+	for _, t := range database.GetAttendanceTallies(r.Context(), "", params["id"]) {
+		if termID, ok := terms[t.CourseID]; ok && t.TermID == termID {
+			report[t.CourseID] = attendanceReport(t, threshold)
+		}
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// attendanceAlerts is the handler function to retrieve the students enrolled in courses
+// whose attendance is below the threshold, keyed by course ID then student ID.
+// The threshold is set with ?threshold=.
+func attendanceAlerts(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	threshold, ok := attendanceThreshold(w, r)
+	if !ok {
+		return
+	}
+
+	var alerts = make(map[string]map[string]attendanceReportInfo)
+	for _, t := range database.GetAttendanceTallies(r.Context(), "", "") {
+		report := attendanceReport(t, threshold)
+		if !report.Alert || t.EnrolmentStatus != database.Enrolled {
+			continue
+		}
+		if alerts[t.CourseID] == nil {
+			alerts[t.CourseID] = make(map[string]attendanceReportInfo)
+		}
+		alerts[t.CourseID][t.StudentID] = report
+	}
+
+	json.NewEncoder(w).Encode(alerts)
+}
+
+// logAttendanceAlerts logs a warning for each enrolled student just marked whose attendance
+// at the course in the term is now below the default threshold.
+func logAttendanceAlerts(r *http.Request, courseID string, termID string, marks map[string]string) {
+	threshold := defaultThreshold()
+	for _, t := range database.GetAttendanceTallies(r.Context(), courseID, "") {
+		if _, marked := marks[t.StudentID]; !marked || t.TermID != termID || t.EnrolmentStatus != database.Enrolled {
+			continue
+		}
+		if report := attendanceReport(t, threshold); report.Alert {
+			logger(r.Context()).Warn("low attendance", "courseid", courseID, "termid", termID, "studentid", t.StudentID,
+				"percent", report.Percent, "threshold", threshold)
+		}
+	}
+}
+
+// attendanceReport returns the report of the tally, with the percentage of the classes
+// attended rounded to 2 decimal places. A student with no classes counted has attended 100%.
+func attendanceReport(t database.AttendanceTally, threshold float64) attendanceReportInfo {
+	report := attendanceReportInfo{Name: t.Name, Present: t.Present, Absent: t.Absent, Late: t.Late, Excused: t.Excused, Percent: 100}
+	if counted := t.Present + t.Late + t.Absent; counted > 0 {
+		report.Percent = math.Round(float64(t.Present+t.Late)/float64(counted)*100*100) / 100
+	}
+	report.Alert = report.Percent < threshold
+	return report
+}
+
+// attendanceThreshold returns the threshold of ?threshold=, or the default threshold.
+// If the threshold is not valid a 422 response is written and false is returned.
+func attendanceThreshold(w http.ResponseWriter, r *http.Request) (float64, bool) {
+	value := r.URL.Query().Get("threshold")
+	if value == "" {
+		return defaultThreshold(), true
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply a threshold between 0 and 100"))
+		return 0, false
+	}
+	return threshold, true
+}
+
+// defaultThreshold returns ATTENDANCE_THRESHOLD from setup.env, or defaultAttendanceThreshold
+// if it is not set to a percentage.
+func defaultThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("ATTENDANCE_THRESHOLD"), 64)
+	if err != nil || threshold < 0 || threshold > 100 {
+		return defaultAttendanceThreshold
+	}
+	return threshold
+}
+
+// classTerm returns the term of the weekly session the class is held in, whose enrolments
+// take the class, or "" for a class outside any session or term.
+func classTerm(r *http.Request, class database.ClassInfo) string {
+	if class.SessionID == 0 {
+		return ""
+	}
+	return database.GetSession(r.Context(), class.SessionID)[strconv.FormatInt(class.SessionID, 10)].Term
+}
+
+// findClass returns the class in the path, which must belong to the course in the path.
+// If there is no such class a 404 response is written and false is returned.
+func findClass(w http.ResponseWriter, r *http.Request, params map[string]string) (int64, database.ClassInfo, bool) {
+	classID, _ := strconv.ParseInt(params["classid"], 10, 64)
+	c, ok := database.GetClass(r.Context(), classID)[params["classid"]]
+	if !ok || c.CourseID != params["courseid"] {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No class found"))
+		return 0, c, false
+	}
+	return classID, c, true
+}
+
+// duplicateClass reports whether another class of the course, other than the one with the
+// ID given, is held on the same date in the same weekly session.
+func duplicateClass(r *http.Request, class database.ClassInfo, classID string) bool {
+	for id, c := range database.GetClasses(r.Context(), class.CourseID) {
+		if id != classID && c.Date == class.Date && c.SessionID == class.SessionID {
+			return true
+		}
+	}
+	return false
+}
+
+// convertClassJSON converts the client JSON to a class of the course and validates it.
+// If the class is not valid a 422 response is written and false is returned.
+func convertClassJSON(w http.ResponseWriter, r *http.Request, courseID string) (database.ClassInfo, bool) {
+	var newClass database.ClassInfo
+
+	if !decodeJSON(w, r, &newClass) {
+		return newClass, false
+	}
+	newClass.CourseID = courseID
+
+	if err := validateClass(r, newClass); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newClass, false
+	}
+	return newClass, true
+}
+
+// validateClass checks that the class details are valid, and that a class held in a weekly
+// session is on the day of the session. Returns error type.
+func validateClass(r *http.Request, class database.ClassInfo) error {
+	date, err := time.Parse("2006-01-02", class.Date)
+	if err != nil {
+		return errors.New("Please supply the date in the format YYYY-MM-DD")
+	}
+	if len(class.Topic) > 100 {
+		return errors.New("Please supply a topic of up to 100 characters")
+	}
+	if class.SessionID != 0 {
+		s, ok := database.GetSession(r.Context(), class.SessionID)[strconv.FormatInt(class.SessionID, 10)]
+		if !ok || s.CourseID != class.CourseID {
+			return errors.New("Please supply a session of the course")
+		}
+		if date.Format("Mon") != s.Day {
+			return errors.New("Please supply a date on the day of the session, " + s.Day)
+		}
+	}
+	return nil
+}
+
+// validateAttendance checks that the marks are valid statuses of students enrolled in, or
+// who have completed, the course. Returns error type.
+func validateAttendance(marks map[string]string, students map[string]string) error {
+	for studentID, status := range marks {
+		if _, ok := students[studentID]; !ok {
+			return errors.New("Student " + studentID + " is not enrolled in the course")
+		}
+		if !validAttendanceStatus(status) {
+			return errors.New("Please supply the status of " + studentID + " as present, absent, late or excused")
+		}
+	}
+	return nil
+}
+
+// validAttendanceStatus reports whether the status is a status of attendance.
+func validAttendanceStatus(status string) bool {
+	switch status {
+	case database.Present, database.Absent, database.Late, database.Excused:
+		return true
+	}
+	return false
+}
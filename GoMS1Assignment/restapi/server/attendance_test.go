@@ -0,0 +1,96 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"reflect"
+	"testing"
+)
+
+func TestAttendanceReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		tally     database.AttendanceTally
+		threshold float64
+		percent   float64
+		alert     bool
+	}{
+		{"no classes", database.AttendanceTally{}, 75, 100, false},
+		{"only excused", database.AttendanceTally{Excused: 3}, 75, 100, false},
+		{"all present", database.AttendanceTally{Present: 10}, 75, 100, false},
+		{"late counts as attended", database.AttendanceTally{Present: 2, Late: 2}, 75, 100, false},
+		{"excused not counted", database.AttendanceTally{Present: 3, Absent: 1, Excused: 6}, 75, 75, false},
+		{"on the threshold", database.AttendanceTally{Present: 3, Absent: 1}, 75, 75, false},
+		{"below the threshold", database.AttendanceTally{Present: 2, Absent: 1}, 75, 66.67, true},
+		{"all absent", database.AttendanceTally{Absent: 4}, 75, 0, true},
+		{"zero threshold", database.AttendanceTally{Absent: 4}, 0, 0, false},
+		{"full threshold", database.AttendanceTally{Present: 9, Absent: 1}, 100, 90, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attendanceReport(tt.tally, tt.threshold)
+			if got.Percent != tt.percent || got.Alert != tt.alert {
+				t.Errorf("attendanceReport = %v%%, alert %v, want %v%%, alert %v", got.Percent, got.Alert, tt.percent, tt.alert)
+			}
+			if got.Present != tt.tally.Present || got.Absent != tt.tally.Absent || got.Late != tt.tally.Late || got.Excused != tt.tally.Excused {
+				t.Errorf("attendanceReport counts = %+v, want those of %+v", got, tt.tally)
+			}
+		})
+	}
+}
+
+func TestCourseAttendanceReport(t *testing.T) {
+	students := map[string]string{"S1": "Ann", "S2": "Bob"}
+	tallies := []database.AttendanceTally{
+		{CourseID: "GO101", TermID: "", StudentID: "S1", Name: "Ann", Absent: 5},
+		{CourseID: "GO101", TermID: "2030S1", StudentID: "S1", Name: "Ann", Present: 3, Absent: 1},
+		{CourseID: "GO101", TermID: "2030S2", StudentID: "S2", Name: "Bob", Absent: 2},
+	}
+
+	tests := []struct {
+		name   string
+		termID string
+		want   map[string]attendanceReportInfo
+	}{
+		{"tallies of the term", "2030S1", map[string]attendanceReportInfo{
+			"S1": {Name: "Ann", Present: 3, Absent: 1, Percent: 75},
+			"S2": {Name: "Bob", Percent: 100},
+		}},
+		{"other term", "2030S2", map[string]attendanceReportInfo{
+			"S1": {Name: "Ann", Percent: 100},
+			"S2": {Name: "Bob", Absent: 2, Percent: 0, Alert: true},
+		}},
+		{"outside any term", "", map[string]attendanceReportInfo{
+			"S1": {Name: "Ann", Absent: 5, Percent: 0, Alert: true},
+			"S2": {Name: "Bob", Percent: 100},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := courseAttendanceReport(students, tallies, tt.termID, 75); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("courseAttendanceReport(%q) =\n%+v\nwant\n%+v", tt.termID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAttendance(t *testing.T) {
+	students := map[string]string{"S1": "Ann", "S2": "Bob"}
+	tests := []struct {
+		name  string
+		marks map[string]string
+		valid bool
+	}{
+		{"none", nil, true},
+		{"every status", map[string]string{"S1": database.Present, "S2": database.Excused}, true},
+		{"student not enrolled", map[string]string{"S3": database.Present}, false},
+		{"unknown status", map[string]string{"S1": "sick"}, false},
+		{"empty status", map[string]string{"S1": ""}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAttendance(tt.marks, students); (err == nil) != tt.valid {
+				t.Errorf("validateAttendance(%v) = %v, want valid %v", tt.marks, err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// calendarEvent is a weekly recurring event of an iCalendar document.
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time // first occurrence, in floating local time
+	End         time.Time
+	Until       time.Time // last day of the recurrence
+}
+
+// icsDateTime is the layout of a floating local date-time in iCalendar.
+const icsDateTime = "20060102T150405"
+
+// writeCalendar writes the events as an iCalendar (RFC 5545) document named name.
+func writeCalendar(w http.ResponseWriter, name string, events []calendarEvent) {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(foldLine(fmt.Sprintf(format, args...)))
+		b.WriteString("\r\n")
+	}
+
+	stamp := time.Now().UTC().Format(icsDateTime) + "Z"
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//GoSchool//REST API//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", escapeText(name))
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:%s", e.UID)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART:%s", e.Start.Format(icsDateTime))
+		line("DTEND:%s", e.End.Format(icsDateTime))
+		line("RRULE:FREQ=WEEKLY;UNTIL=%s", e.Until.Format("20060102")+"T235959")
+		line("SUMMARY:%s", escapeText(e.Summary))
+		line("LOCATION:%s", escapeText(e.Location))
+		if e.Description != "" {
+			line("DESCRIPTION:%s", escapeText(e.Description))
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ".ics"}))
+	w.Write([]byte(b.String()))
+}
+
+// escapeText escapes a TEXT value of an iCalendar property.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldLine folds a content line longer than 75 octets onto continuation lines, which
+// start with a space, without splitting a UTF-8 character.
+func foldLine(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func mustDate(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	// 2030-01-07 is a Monday
+	tests := []struct {
+		name      string
+		from      string
+		day       string
+		wantStart string
+		wantEnd   string
+	}{
+		{"same day", "2030-01-07", "Mon", "2030-01-07 09:00", "2030-01-07 10:30"},
+		{"later in the week", "2030-01-07", "Fri", "2030-01-11 09:00", "2030-01-11 10:30"},
+		{"Sunday after Monday", "2030-01-07", "Sun", "2030-01-13 09:00", "2030-01-13 10:30"},
+		{"wraps to next week", "2030-01-09", "Mon", "2030-01-14 09:00", "2030-01-14 10:30"},
+		{"across a month", "2030-01-31", "Tue", "2030-02-05 09:00", "2030-02-05 10:30"},
+		{"across a year", "2029-12-29", "Wed", "2030-01-02 09:00", "2030-01-02 10:30"},
+		{"leap day", "2028-02-27", "Tue", "2028-02-29 09:00", "2028-02-29 10:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := database.SessionInfo{Day: tt.day, StartTime: "09:00", EndTime: "10:30"}
+			start, end := firstOccurrence(mustDate(tt.from), s)
+			if got := start.Format("2006-01-02 15:04"); got != tt.wantStart {
+				t.Errorf("firstOccurrence(%s, %s) start = %s, want %s", tt.from, tt.day, got, tt.wantStart)
+			}
+			if got := end.Format("2006-01-02 15:04"); got != tt.wantEnd {
+				t.Errorf("firstOccurrence(%s, %s) end = %s, want %s", tt.from, tt.day, got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestTermDates(t *testing.T) {
+	term := database.TermInfo{StartDate: "2030-01-07", EndDate: "2030-04-26"}
+
+	tests := []struct {
+		name            string
+		term            database.TermInfo
+		from, until     string
+		wantFirst, want string
+	}{
+		{"whole term", term, "", "", "2030-01-07", "2030-04-26"},
+		{"from within term", term, "2030-02-01", "", "2030-02-01", "2030-04-26"},
+		{"until within term", term, "", "2030-03-01", "2030-01-07", "2030-03-01"},
+		{"dates around term", term, "2029-12-01", "2030-06-01", "2030-01-07", "2030-04-26"},
+		{"after term", term, "2030-05-01", "", "2030-05-01", "2030-04-26"},
+		{"term without dates", database.TermInfo{}, "2030-01-07", "", "2030-01-07", "2030-04-21"}, // termWeeks later
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var from, until time.Time
+			if tt.from != "" {
+				from = mustDate(tt.from)
+			}
+			if tt.until != "" {
+				until = mustDate(tt.until)
+			}
+			first, last := termDates(tt.term, from, until)
+			if got := first.Format("2006-01-02"); got != tt.wantFirst {
+				t.Errorf("termDates() first = %s, want %s", got, tt.wantFirst)
+			}
+			if got := last.Format("2006-01-02"); got != tt.want {
+				t.Errorf("termDates() last = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarDates(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr bool
+	}{
+		{"", false},
+		{"from=2030-01-07&until=2030-04-26", false},
+		{"from=2030-01-07&until=2030-01-07", false},
+		{"from=07/01/2030", true},
+		{"until=2030-13-01", true},
+		{"from=2030-04-26&until=2030-01-07", true},
+	}
+	for _, tt := range tests {
+		_, _, err := calendarDates(httptest.NewRequest("GET", "/calendar.ics?"+tt.query, nil))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("calendarDates(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeCalendar(rec, "GO101", []calendarEvent{{
+		UID:      "session-1@goschool",
+		Summary:  "GO101 Go; Programming, Basics",
+		Location: "Room 1",
+		Start:    time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC),
+		End:      time.Date(2030, 1, 7, 10, 30, 0, 0, time.UTC),
+		Until:    mustDate("2030-04-26"),
+	}})
+
+	if got, want := rec.Header().Get("Content-Disposition"), `attachment; filename=GO101.ics`; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTART:20300107T090000\r\n",
+		"DTEND:20300107T103000\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20300426T235959\r\n",
+		`SUMMARY:GO101 Go\; Programming\, Basics` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestCalendarContentDispositionQuoting(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeCalendar(rec, `Term "1"`, nil)
+
+	// A quoted-string escapes quotes with a backslash, not as a Go string would
+	if got, want := rec.Header().Get("Content-Disposition"), `attachment; filename="Term \"1\".ics"`; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	if got := foldLine("SUMMARY:short"); got != "SUMMARY:short" {
+		t.Errorf("foldLine() of a short line = %q", got)
+	}
+
+	long := "DESCRIPTION:" + strings.Repeat("é", 40)
+	for i, part := range strings.Split(foldLine(long), "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line %d is %d octets, want at most 75", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("continuation line %d does not start with a space: %q", i, part)
+		}
+		if !utf8.ValidString(part) {
+			t.Errorf("folded line %d splits a UTF-8 character: %q", i, part)
+		}
+	}
+	if got := strings.ReplaceAll(foldLine(long), "\r\n ", ""); got != long {
+		t.Errorf("unfolded line = %q, want %q", got, long)
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// cloneInfo struct for the json of a clone of a course. Details not given are copied from
+// the course.
+type cloneInfo struct {
+	CourseID    string   `json:"CourseID"` // ID of the new course
+	Title       *string  `json:"Title,omitempty"`
+	Description *string  `json:"Description,omitempty"`
+	Capacity    *int     `json:"Capacity,omitempty"`
+	Department  *string  `json:"Department,omitempty"` // Department ID; "" for none
+	Include     []string `json:"Include,omitempty"`    // related data to copy, see cloneIncludes
+}
+
+// cloneIncludes are the related data of a course which may be copied to its clone.
+var cloneIncludes = map[string]bool{
+	database.CopyTags:          true,
+	database.CopyInstructors:   true,
+	database.CopyPrerequisites: true,
+	database.CopyGradebook:     true,
+	database.CopyAttachments:   true,
+}
+
+// clone is the handler function to add a draft course copied from the course with POST,
+// as a template for a similar course. The details of the course may be overridden, and its
+// tags, instructors, prerequisites, gradebook and attachments copied if included.
+func clone(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	// Check if course exists
+	course, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	var req cloneInfo
+
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Copy the details not overridden from the course
+	newCourse := database.CloneInfo{
+		CourseID:    validation.CanonicalCourseID(req.CourseID),
+		Title:       course.Title,
+		Description: course.Description,
+		Capacity:    course.Capacity,
+		Department:  course.Department,
+		Include:     make(map[string]bool),
+		ClonedBy:    keyLabel(r),
+	}
+	if req.Title != nil {
+		newCourse.Title = *req.Title
+	}
+	if req.Description != nil {
+		newCourse.Description = *req.Description
+	}
+	if req.Capacity != nil {
+		newCourse.Capacity = *req.Capacity
+	}
+	if req.Department != nil {
+		newCourse.Department = *req.Department
+	}
+	for _, include := range req.Include {
+		if !cloneIncludes[include] {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - Please supply Include of tags, instructors, prerequisites, gradebook or attachments"))
+			return
+		}
+		newCourse.Include[include] = true
+	}
+
+	// Check the details against the rules
+	errs := rules.ValidateNewCourse(validation.Course{
+		ID: newCourse.CourseID, Title: newCourse.Title, Description: newCourse.Description, Capacity: newCourse.Capacity,
+	})
+	if len(errs) != 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+
+	if !checkDepartment(w, r, courseInfo{Department: &newCourse.Department}) {
+		return
+	}
+
+	// Each course has its own copies of the files, which are deleted with the course
+	if newCourse.Include[database.CopyAttachments] {
+		attachments, err := copyAttachments(r.Context(), database.GetAttachments(r.Context(), params["courseid"]))
+		if err != nil {
+			logger(r.Context()).Error("error copying attachment files", "course", params["courseid"], "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Attachments could not be copied"))
+			return
+		}
+		newCourse.Attachments = attachments
+	}
+
+	if err := database.CloneCourse(r.Context(), params["courseid"], newCourse); err != nil {
+		deleteBlobs(r.Context(), newCourse.Attachments)
+		if errors.Is(err, database.ErrDuplicateCourseID) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Duplicate course ID"))
+		} else if errors.Is(err, database.ErrNoCourse) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Course was changed by another request"))
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Error cloning course"))
+		}
+		return
+	}
+
+	w.Header().Set("Location", coursePath(newCourse.CourseID))
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("201 - Course cloned: " + newCourse.CourseID))
+}
+
+// copyAttachments copies the files of the attachments in the blob store, and returns the
+// attachments with the keys of the copies. Any copies made are deleted if one fails.
+func copyAttachments(ctx context.Context, attachments map[string]database.AttachmentInfo) ([]database.AttachmentInfo, error) {
+	var copies []database.AttachmentInfo
+	for _, attachment := range attachments {
+		f, err := blobs.Open(attachment.BlobKey)
+		if err != nil {
+			deleteBlobs(ctx, copies)
+			return nil, err
+		}
+		attachment.BlobKey, attachment.Size, err = blobs.Put(f)
+		f.Close()
+		if err != nil {
+			deleteBlobs(ctx, copies)
+			return nil, err
+		}
+		copies = append(copies, attachment)
+	}
+	return copies, nil
+}
+
+// deleteBlobs deletes the files of the attachments from the blob store, logging any error.
+func deleteBlobs(ctx context.Context, attachments []database.AttachmentInfo) {
+	for _, attachment := range attachments {
+		if err := blobs.Delete(attachment.BlobKey); err != nil {
+			logger(ctx).Error("deleting attachment file", "error", err)
+		}
+	}
+}
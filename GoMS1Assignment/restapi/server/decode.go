@@ -0,0 +1,95 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxBodySize is the maximum size in bytes of a JSON request body.
+const maxBodySize = 1 << 20
+
+// decodeJSON decodes the JSON body of the request into v. The body must be sent as
+// application/json, be at most maxBodySize bytes and hold a single JSON value with no fields
+// other than those of v. Otherwise a 415, 413 or 422 response is written and false is
+// returned, and the handler must not write another response.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		w.Write([]byte("415 - Please supply the body as application/json"))
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodySize)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(v)
+	if err == nil {
+		// Anything after the value, even another value, is not valid
+		var extra json.RawMessage
+		if err = decoder.Decode(&extra); err == io.EOF {
+			return true
+		} else if err == nil {
+			err = errors.New("body must hold a single JSON value")
+		}
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte("413 - Please supply a body of at most " + strconv.Itoa(maxBodySize>>10) + " KB"))
+		return false
+	}
+
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	w.Write([]byte("422 - " + decodeErrorMessage(err)))
+	return false
+}
+
+// decodeErrorMessage returns the reason for a JSON body not being decoded, for the client.
+func decodeErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == io.EOF:
+		return "Please supply the body in JSON format"
+	case errors.As(err, &syntaxErr):
+		return "Malformed JSON at offset " + strconv.FormatInt(syntaxErr.Offset, 10)
+	case err == io.ErrUnexpectedEOF:
+		return "Malformed JSON: unexpected end of body"
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return "Invalid value for " + typeErr.Field + ": expected " + jsonType(typeErr.Type)
+	case errors.As(err, &typeErr):
+		return "Invalid JSON value: expected " + jsonType(typeErr.Type)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return "Unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	}
+	return "Malformed JSON: " + strings.TrimPrefix(err.Error(), "json: ")
+}
+
+// jsonType describes the JSON value a Go type is decoded from.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
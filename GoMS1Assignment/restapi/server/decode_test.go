@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// countingRecorder counts the status codes written, to catch handlers writing more than
+// one response.
+type countingRecorder struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+// WriteHeader records the status code and counts the write.
+func (c *countingRecorder) WriteHeader(code int) {
+	c.writes++
+	c.ResponseRecorder.WriteHeader(code)
+}
+
+// newJSONRequest returns a PUT request with the body and Content-Type given.
+func newJSONRequest(body string, contentType string) *http.Request {
+	r := httptest.NewRequest(http.MethodPut, "/api/v1/departments/CS", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// TestDecodeJSON checks that bodies are decoded only if they are a single JSON value of
+// the expected fields, sent as application/json within the size limit.
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		code        int    // status written, or 0 if the body is decoded
+		message     string // part of the message written
+	}{
+		{"valid", `{"Name": "Computing"}`, "application/json", 0, ""},
+		{"charset", `{"Name": "Computing"}`, "application/json; charset=utf-8", 0, ""},
+		{"surrounding whitespace", " \n{\"Name\": \"Computing\"}\n", "application/json", 0, ""},
+		{"no content type", `{"Name": "Computing"}`, "", http.StatusUnsupportedMediaType, "application/json"},
+		{"wrong content type", `{"Name": "Computing"}`, "text/plain", http.StatusUnsupportedMediaType, "application/json"},
+		{"form content type", `Name=Computing`, "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType, "application/json"},
+		{"empty", ``, "application/json", http.StatusUnprocessableEntity, "JSON format"},
+		{"truncated", `{"Name": "Comp`, "application/json", http.StatusUnprocessableEntity, "unexpected end"},
+		{"syntax error", `{"Name" "Computing"}`, "application/json", http.StatusUnprocessableEntity, "Malformed JSON at offset"},
+		{"unknown field", `{"Name": "Computing", "Head": "T01"}`, "application/json", http.StatusUnprocessableEntity, `Unknown field "Head"`},
+		{"wrong field type", `{"Name": 42}`, "application/json", http.StatusUnprocessableEntity, "Name: expected a string"},
+		{"wrong value type", `["Computing"]`, "application/json", http.StatusUnprocessableEntity, "expected an object"},
+		{"two values", `{"Name": "Computing"}{"Name": "Maths"}`, "application/json", http.StatusUnprocessableEntity, "single JSON value"},
+		{"trailing garbage", `{"Name": "Computing"} x`, "application/json", http.StatusUnprocessableEntity, "Malformed JSON"},
+		{"oversized", `{"Name": "` + strings.Repeat("x", maxBodySize) + `"}`, "application/json", http.StatusRequestEntityTooLarge, "at most 1024 KB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+			var department struct {
+				Name string `json:"Name"`
+			}
+
+			ok := decodeJSON(w, newJSONRequest(test.body, test.contentType), &department)
+
+			if test.code == 0 {
+				if !ok || w.writes != 0 {
+					t.Fatalf("decodeJSON() = %v with %d writes, want true with none: %s", ok, w.writes, w.Body)
+				}
+				if department.Name != "Computing" {
+					t.Errorf("Name = %q, want %q", department.Name, "Computing")
+				}
+				return
+			}
+
+			if ok {
+				t.Fatal("decodeJSON() = true, want false")
+			}
+			if w.writes != 1 || w.Code != test.code {
+				t.Errorf("wrote %d responses with status %d, want 1 with %d", w.writes, w.Code, test.code)
+			}
+			if !strings.Contains(w.Body.String(), test.message) {
+				t.Errorf("message %q does not contain %q", w.Body, test.message)
+			}
+		})
+	}
+}
+
+// TestConvertJSONWritesOnce checks that an invalid course body is answered with a single
+// response, and a valid one with none so the handler can write its own.
+func TestConvertJSONWritesOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		ok          bool
+		code        int
+	}{
+		{"valid", `{"Title": "Go Programming", "Capacity": 30}`, "application/json", true, 0},
+		{"malformed", `{"Title": `, "application/json", false, http.StatusUnprocessableEntity},
+		{"not json", `<course/>`, "application/xml", false, http.StatusUnsupportedMediaType},
+		{"unknown field", `{"Title": "Go", "Credits": 4}`, "application/json", false, http.StatusUnprocessableEntity},
+		{"blank title", `{"Title": ""}`, "application/json", false, http.StatusUnprocessableEntity},
+		{"negative capacity", `{"Title": "Go", "Capacity": -1}`, "application/json", false, http.StatusUnprocessableEntity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+			_, ok := convertJSON(w, newJSONRequest(test.body, test.contentType))
+
+			if ok != test.ok {
+				t.Fatalf("convertJSON() = %v, want %v", ok, test.ok)
+			}
+			wantWrites := 1
+			if test.ok {
+				wantWrites = 0
+			}
+			if w.writes != wantWrites {
+				t.Errorf("wrote %d responses, want %d: %s", w.writes, wantWrites, w.Body)
+			}
+			if !test.ok && w.Code != test.code {
+				t.Errorf("status = %d, want %d", w.Code, test.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// alldepartments is the handler function to retrieve all departments.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func alldepartments(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	// Get all departments from the database
+	departments := database.GetAllDepartments(r.Context())
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(departments)
+}
+
+// department is the handler function for CRUD operations on departments sent by the client.
+// The operations for GET, POST, PUT and DELETE will be determined by switch
+// and its respective functions will be called.
+func department(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving department
+		getDepartment(params, w, r)
+	case "POST": // POST is for creating new department
+		addDepartment(params, w, r)
+	case "PUT": //---PUT is for updating department
+		updateDepartment(params, w, r)
+	case "DELETE": // DELETE is for deleting department
+		deleteDepartment(params, w, r)
+	}
+}
+
+// getDepartment implements the GET method invoked by the client and
+// retrieves the department with the department id given.
+func getDepartment(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Get department from the database
+	departments := database.GetDepartment(r.Context(), params["departmentid"])
+
+	// Department exists
+	if len(departments) != 0 {
+		// convert the map object to JSON, and pass it back to the client
+		json.NewEncoder(w).Encode(departments)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No department found"))
+	}
+}
+
+// addDepartment implements the POST method invoked by the client and
+// adds a department with the department id and name given.
+func addDepartment(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	if len(params["departmentid"]) > 20 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Department ID cannot be greater than 20 characters"))
+		return
+	}
+
+	newDepartment, ok := convertDepartmentJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if department exists
+	departments := database.GetDepartment(r.Context(), params["departmentid"])
+
+	// Department does not exist
+	if len(departments) == 0 {
+		// Add department information into the database
+		database.AddDepartment(r.Context(), params["departmentid"], newDepartment)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("201 - Department added: " + params["departmentid"]))
+	} else {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Duplicate department ID"))
+	}
+}
+
+// updateDepartment implements the PUT method invoked by the client and
+// updates the name of a department with the department id given.
+func updateDepartment(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newDepartment, ok := convertDepartmentJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if department exists
+	departments := database.GetDepartment(r.Context(), params["departmentid"])
+
+	// Department exists
+	if len(departments) != 0 {
+		// Update department in the database
+		database.UpdateDepartment(r.Context(), params["departmentid"], newDepartment)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Department updated"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No department found"))
+	}
+}
+
+// deleteDepartment implements the DELETE method invoked by the client and deletes a
+// department with the department id given. A department with courses cannot be deleted.
+func deleteDepartment(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check if department exists
+	departments := database.GetDepartment(r.Context(), params["departmentid"])
+
+	// Department does not exist
+	current, ok := departments[params["departmentid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No department found"))
+		return
+	}
+
+	if current.Courses != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Department has " + strconv.Itoa(current.Courses) + " courses"))
+		return
+	}
+
+	// Delete department from the database
+	database.DeleteDepartment(r.Context(), params["departmentid"])
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Department deleted"))
+}
+
+// convertDepartmentJSON converts the client JSON to a department and validates it.
+// If the department is not valid a 422 response is written and false is returned.
+func convertDepartmentJSON(w http.ResponseWriter, r *http.Request) (database.DepartmentInfo, bool) {
+	var newDepartment database.DepartmentInfo
+
+	if !decodeJSON(w, r, &newDepartment) {
+		return newDepartment, false
+	}
+
+	if newDepartment.Name == "" || len(newDepartment.Name) > 45 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply a department name of up to 45 characters"))
+		return newDepartment, false
+	}
+	return newDepartment, true
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// enrolmentInfo struct for the json
+type enrolmentInfo struct {
+	StudentID string `json:"StudentID"`
+	Term      string `json:"Term"` // optional term ID to enrol in the offering of the course in that term
+	Status    string `json:"Status"`
+}
+
+// enrolmentTransitions lists the statuses an enrolment may be changed to from each status.
+// A withdrawn student enrols again with POST, which waitlists them if the course is full.
+var enrolmentTransitions = map[string][]string{
+	database.Enrolled:   {database.Withdrawn, database.Completed},
+	database.Waitlisted: {database.Withdrawn},
+}
+
+// enrolments is the handler function for the enrolments of a course.
+// GET retrieves the enrolments in every term and POST enrols a student.
+func enrolments(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	// Check if course exists
+	course, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the enrolments of the course
+		json.NewEncoder(w).Encode(database.GetEnrolments(r.Context(), params["courseid"]))
+	case "POST": // POST is for enrolling a student in the course
+		// Only active courses are open for enrolment
+		if course.Status != database.Active {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Course is " + course.Status + ", not open for enrolment"))
+			return
+		}
+		enrolStudent(params, w, r)
+	}
+}
+
+// enrolStudent implements the POST method invoked by the client and enrols the student
+// given in the course, or in its offering in the term given. The student is waitlisted if
+// the course or offering is full. A student may be enrolled in each term the course is
+// offered in, as well as outside any term.
+func enrolStudent(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	var newEnrolment enrolmentInfo
+
+	if !decodeJSON(w, r, &newEnrolment) {
+		return
+	}
+
+	if newEnrolment.StudentID == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply the StudentID in JSON format"))
+		return
+	}
+
+	// Check if student exists
+	if len(database.GetStudent(r.Context(), newEnrolment.StudentID)) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	// Check if the course is offered in the term
+	if newEnrolment.Term != "" && !checkOffered(w, r, params["courseid"], newEnrolment.Term) {
+		return
+	}
+
+	// A student who withdrew may enrol again, but not one already enrolled in the term
+	_, err := database.Enrol(r.Context(), params["courseid"], newEnrolment.Term, newEnrolment.StudentID)
+	if errors.Is(err, database.ErrAlreadyEnrolled) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Student is already enrolled in the course"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Error enrolling student"))
+		return
+	}
+
+	// Return the enrolment so the client can tell if the student was waitlisted
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(database.GetEnrolment(r.Context(), params["courseid"], newEnrolment.Term, newEnrolment.StudentID))
+}
+
+// enrolment is the handler function for the enrolment of a student in a course, in the
+// term given with ?term= or else outside any term. GET retrieves the enrolment and PUT
+// changes its status.
+func enrolment(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	existing := database.GetEnrolment(r.Context(), params["courseid"], r.URL.Query().Get("term"), params["studentid"])
+	current, ok := existing[params["studentid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No enrolment found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the enrolment
+		json.NewEncoder(w).Encode(existing)
+	case "PUT": // PUT is for changing the status of the enrolment
+		updateEnrolment(params, current, w, r)
+	}
+}
+
+// updateEnrolment implements the PUT method invoked by the client and changes the status of
+// an enrolment, following enrolmentTransitions.
+func updateEnrolment(params map[string]string, current database.EnrolmentInfo, w http.ResponseWriter, r *http.Request) {
+	var newEnrolment enrolmentInfo
+
+	if !decodeJSON(w, r, &newEnrolment) {
+		return
+	}
+
+	if newEnrolment.Status == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply the Status in JSON format"))
+		return
+	}
+
+	allowed := false
+	for _, status := range enrolmentTransitions[current.Status] {
+		if status == newEnrolment.Status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Enrolment cannot be changed from " + current.Status + " to " + newEnrolment.Status))
+		return
+	}
+
+	if !database.SetEnrolmentStatus(r.Context(), params["courseid"], current.Term, params["studentid"], newEnrolment.Status) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Error updating enrolment"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Enrolment " + newEnrolment.Status))
+}
+
+// learnerCourses is the handler function to retrieve the courses a learner is or was
+// enrolled in, once for each term. Learners are identified by their student ID.
+func learnerCourses(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	// Check if student exists
+	if len(database.GetStudent(r.Context(), params["id"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(database.GetLearnerCourses(r.Context(), params["id"]))
+}
@@ -0,0 +1,495 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultGradeScale is the minimum percentage of each letter grade for courses which have
+// not set their own boundaries.
+var defaultGradeScale = map[string]float64{"A": 80, "B": 70, "C": 60, "D": 50, "F": 0}
+
+// gradebookInfo struct for the json of the gradebook of a course
+type gradebookInfo struct {
+	Assessments map[string]database.AssessmentInfo `json:"Assessments"`
+	GradeScale  map[string]float64                 `json:"GradeScale"`
+	Students    map[string]gradeInfo               `json:"Students"`
+}
+
+// gradeInfo struct for the json of the scores and final grade of a student
+type gradeInfo struct {
+	Name    string             `json:"Name"`
+	Scores  map[string]float64 `json:"Scores"`  // keyed by assessment ID
+	Percent float64            `json:"Percent"` // weighted final percentage, with missing scores as 0
+	Grade   string             `json:"Grade"`
+}
+
+// assessments is the handler function to retrieve the assessments of a course with GET
+// and add an assessment with POST.
+func assessments(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving assessments
+		json.NewEncoder(w).Encode(database.GetAssessments(r.Context(), params["courseid"]))
+	case "POST": // POST is for creating new assessment
+		newAssessment, ok := convertAssessmentJSON(w, r)
+		if !ok {
+			return
+		}
+		newAssessment.CourseID = params["courseid"]
+
+		if duplicateAssessment(r, newAssessment, "") {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Duplicate assessment name"))
+			return
+		}
+
+		id, other := database.AddAssessment(r.Context(), newAssessment)
+		if id == 0 {
+			if other+newAssessment.Weight > 100 {
+				w.WriteHeader(http.StatusConflict)
+				w.Write([]byte(fmt.Sprintf("409 - Weights would total %g, more than 100", other+newAssessment.Weight)))
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte("500 - Assessment could not be added"))
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(database.GetAssessment(r.Context(), id))
+	}
+}
+
+// assessment is the handler function for the GET, PUT and DELETE operations on an assessment
+// of a course.
+func assessment(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	assessmentID, current, ok := findAssessment(w, r, params)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving assessment
+		json.NewEncoder(w).Encode(map[string]database.AssessmentInfo{params["assessmentid"]: current})
+	case "PUT": //---PUT is for updating assessment
+		newAssessment, ok := convertAssessmentJSON(w, r)
+		if !ok {
+			return
+		}
+		newAssessment.CourseID = params["courseid"]
+
+		if duplicateAssessment(r, newAssessment, params["assessmentid"]) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Duplicate assessment name"))
+			return
+		}
+
+		updated, other := database.UpdateAssessment(r.Context(), assessmentID, newAssessment)
+		if !updated && other+newAssessment.Weight > 100 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(fmt.Sprintf("409 - Weights would total %g, more than 100", other+newAssessment.Weight)))
+		} else if !updated {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Assessment could not be updated"))
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Assessment updated"))
+		}
+	case "DELETE": // DELETE is for deleting assessment
+		database.DeleteAssessment(r.Context(), assessmentID)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Assessment deleted"))
+	}
+}
+
+// scores is the handler function to retrieve the scores of an assessment, keyed by student
+// ID, with GET and to record scores with PUT. The scores are those of the enrolments in the
+// term given with ?term=, or else outside any term. Only students enrolled in, or who have
+// completed, the course in the term may be scored.
+func scores(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	assessmentID, current, ok := findAssessment(w, r, params)
+	if !ok {
+		return
+	}
+	termID := r.URL.Query().Get("term")
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the scores
+		var assessmentScores = make(map[string]float64)
+		for studentID, scores := range database.GetScores(r.Context(), params["courseid"], termID) {
+			if score, ok := scores[params["assessmentid"]]; ok {
+				assessmentScores[studentID] = score
+			}
+		}
+		json.NewEncoder(w).Encode(assessmentScores)
+	case "PUT": // PUT is for recording scores
+		var newScores map[string]float64
+
+		if !decodeJSON(w, r, &newScores) {
+			return
+		}
+
+		if err := validateScores(newScores, current, gradedStudents(r, params["courseid"], termID)); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+
+		database.SetScores(r.Context(), assessmentID, termID, newScores)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Scores recorded: " + strconv.Itoa(len(newScores))))
+	}
+}
+
+// score is the handler function to delete the score of a student in an assessment for their
+// enrolment in the term given with ?term=, or else outside any term.
+func score(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	assessmentID, _, ok := findAssessment(w, r, params)
+	if !ok {
+		return
+	}
+
+	termID := r.URL.Query().Get("term")
+	if _, ok := database.GetScores(r.Context(), params["courseid"], termID)[params["studentid"]][params["assessmentid"]]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No score found"))
+		return
+	}
+
+	database.DeleteScore(r.Context(), assessmentID, termID, params["studentid"])
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Score deleted"))
+}
+
+// gradescale is the handler function to retrieve the letter-grade boundaries of a course
+// with GET and replace them with PUT. An empty scale restores the default boundaries.
+func gradescale(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the boundaries
+		json.NewEncoder(w).Encode(courseGradeScale(r, params["courseid"]))
+	case "PUT": // PUT is for replacing the boundaries
+		var newScale map[string]float64
+
+		if !decodeJSON(w, r, &newScale) {
+			return
+		}
+
+		// An empty scale restores the default scale
+		var err error
+		if len(newScale) != 0 {
+			err = validateGradeScale(newScale)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+
+		database.SetGradeScale(r.Context(), params["courseid"], newScale)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Grade scale updated"))
+	}
+}
+
+// gradebook is the handler function to retrieve the gradebook of a course: its assessments,
+// the scores of the students enrolled in, or who have completed, the course in the term
+// given with ?term=, or else outside any term, and their weighted final grades. The
+// gradebook is exported as CSV with ?format=csv.
+func gradebook(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	book := gradebookInfo{
+		Assessments: database.GetAssessments(r.Context(), params["courseid"]),
+		GradeScale:  courseGradeScale(r, params["courseid"]),
+		Students:    make(map[string]gradeInfo),
+	}
+
+	termID := r.URL.Query().Get("term")
+	scores := database.GetScores(r.Context(), params["courseid"], termID)
+	for studentID, name := range gradedStudents(r, params["courseid"], termID) {
+		grade := gradeInfo{Name: name, Scores: make(map[string]float64)}
+		for assessmentID := range book.Assessments {
+			if score, ok := scores[studentID][assessmentID]; ok {
+				grade.Scores[assessmentID] = score
+			}
+		}
+		grade.Percent = finalPercent(book.Assessments, grade.Scores)
+		if len(book.Assessments) != 0 {
+			grade.Grade = letterGrade(book.GradeScale, grade.Percent)
+		}
+		book.Students[studentID] = grade
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		writeGradebookCSV(w, params["courseid"], book)
+		return
+	}
+
+	json.NewEncoder(w).Encode(book)
+}
+
+// writeGradebookCSV writes the gradebook as CSV with a row for each student, sorted by
+// student ID, and a column for each assessment in the order they were added.
+func writeGradebookCSV(w http.ResponseWriter, courseID string, book gradebookInfo) {
+	assessmentIDs := make([]string, 0, len(book.Assessments))
+	for id := range book.Assessments {
+		assessmentIDs = append(assessmentIDs, id)
+	}
+	sort.Slice(assessmentIDs, func(i, j int) bool {
+		a, _ := strconv.Atoi(assessmentIDs[i])
+		b, _ := strconv.Atoi(assessmentIDs[j])
+		return a < b
+	})
+
+	studentIDs := make([]string, 0, len(book.Students))
+	for id := range book.Students {
+		studentIDs = append(studentIDs, id)
+	}
+	sort.Strings(studentIDs)
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": courseID + "-gradebook.csv"}))
+
+	out := csv.NewWriter(w)
+
+	header := []string{"StudentID", "Name"}
+	for _, id := range assessmentIDs {
+		a := book.Assessments[id]
+		header = append(header, fmt.Sprintf("%s (%g%% of %g)", a.Name, a.Weight, a.MaxScore))
+	}
+	out.Write(append(header, "Percent", "Grade"))
+
+	for _, studentID := range studentIDs {
+		grade := book.Students[studentID]
+		row := []string{studentID, grade.Name}
+		for _, id := range assessmentIDs {
+			if score, ok := grade.Scores[id]; ok {
+				row = append(row, strconv.FormatFloat(score, 'f', -1, 64))
+			} else {
+				row = append(row, "")
+			}
+		}
+		out.Write(append(row, strconv.FormatFloat(grade.Percent, 'f', 2, 64), grade.Grade))
+	}
+	out.Flush()
+}
+
+// finalPercent returns the weighted percentage of the scores, keyed by assessment ID, out of
+// the total weight of the assessments, rounded to 2 decimal places. Missing scores count as 0.
+func finalPercent(assessments map[string]database.AssessmentInfo, scores map[string]float64) float64 {
+	var weighted, total float64
+	for id, a := range assessments {
+		total += a.Weight
+		if a.MaxScore > 0 {
+			weighted += a.Weight * scores[id] / a.MaxScore
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return math.Round(weighted/total*100*100) / 100
+}
+
+// letterGrade returns the grade of the scale with the highest boundary the percentage reaches.
+func letterGrade(scale map[string]float64, percent float64) string {
+	grade, best := "", -1.0
+	for g, min := range scale {
+		if percent >= min && min > best {
+			grade, best = g, min
+		}
+	}
+	return grade
+}
+
+// courseGradeScale returns the letter-grade boundaries of the course, or the default boundaries.
+func courseGradeScale(r *http.Request, courseID string) map[string]float64 {
+	if scale := database.GetGradeScale(r.Context(), courseID); len(scale) != 0 {
+		return scale
+	}
+	return defaultGradeScale
+}
+
+// gradedStudents returns the names of the students enrolled in, or who have completed, the
+// course in the term given, or outside any term if termID is "", keyed by student ID.
+func gradedStudents(r *http.Request, courseID string, termID string) map[string]string {
+	var students = make(map[string]string)
+	for _, e := range database.GetEnrolments(r.Context(), courseID) {
+		if e.Term == termID && (e.Status == database.Enrolled || e.Status == database.Completed) {
+			students[e.StudentID] = e.Name
+		}
+	}
+	return students
+}
+
+// findAssessment returns the assessment in the path, which must belong to the course in the
+// path. If there is no such assessment a 404 response is written and false is returned.
+func findAssessment(w http.ResponseWriter, r *http.Request, params map[string]string) (int64, database.AssessmentInfo, bool) {
+	assessmentID, _ := strconv.ParseInt(params["assessmentid"], 10, 64)
+	a, ok := database.GetAssessment(r.Context(), assessmentID)[params["assessmentid"]]
+	if !ok || a.CourseID != params["courseid"] {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No assessment found"))
+		return 0, a, false
+	}
+	return assessmentID, a, true
+}
+
+// duplicateAssessment reports whether another assessment of the course, other than the one
+// with the ID given, has the name of the assessment.
+func duplicateAssessment(r *http.Request, assessment database.AssessmentInfo, assessmentID string) bool {
+	for id, a := range database.GetAssessments(r.Context(), assessment.CourseID) {
+		if id != assessmentID && a.Name == assessment.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// convertAssessmentJSON converts the client JSON to an assessment and validates it.
+// If the assessment is not valid a 422 response is written and false is returned.
+func convertAssessmentJSON(w http.ResponseWriter, r *http.Request) (database.AssessmentInfo, bool) {
+	var newAssessment database.AssessmentInfo
+
+	if !decodeJSON(w, r, &newAssessment) {
+		return newAssessment, false
+	}
+
+	if err := validateAssessment(newAssessment); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newAssessment, false
+	}
+	return newAssessment, true
+}
+
+// validateAssessment checks that the assessment details are valid. Returns error type.
+func validateAssessment(assessment database.AssessmentInfo) error {
+	if assessment.Name == "" || len(assessment.Name) > 45 {
+		return errors.New("Please supply an assessment name of up to 45 characters")
+	}
+	if assessment.Weight <= 0 || assessment.Weight > 100 {
+		return errors.New("Weight must be more than 0 and at most 100")
+	}
+	if assessment.MaxScore <= 0 || assessment.MaxScore >= 100000 {
+		return errors.New("MaxScore must be more than 0 and less than 100000")
+	}
+	return nil
+}
+
+// validateScores checks that the scores are for the students given and within the maximum
+// score of the assessment. Returns error type.
+func validateScores(scores map[string]float64, assessment database.AssessmentInfo, students map[string]string) error {
+	if len(scores) == 0 {
+		return errors.New("Please supply the scores keyed by student ID in JSON format")
+	}
+	for studentID, score := range scores {
+		if _, ok := students[studentID]; !ok {
+			return errors.New("Student " + studentID + " is not enrolled in the course")
+		}
+		if score < 0 || score > assessment.MaxScore {
+			return fmt.Errorf("Score of student %s must be between 0 and %g", studentID, assessment.MaxScore)
+		}
+	}
+	return nil
+}
+
+// validateGradeScale checks that the letter-grade boundaries are valid and that every
+// percentage has a grade. Returns error type.
+func validateGradeScale(scale map[string]float64) error {
+	seen := make(map[float64]bool)
+	for grade, min := range scale {
+		if grade == "" || len(grade) > 2 {
+			return errors.New("Grades must be of 1 or 2 characters")
+		}
+		if min < 0 || min > 100 {
+			return errors.New("Boundary of grade " + grade + " must be between 0 and 100")
+		}
+		if seen[min] {
+			return fmt.Errorf("More than one grade has the boundary %g", min)
+		}
+		seen[min] = true
+	}
+	if !seen[0] {
+		return errors.New("The lowest grade must have a boundary of 0")
+	}
+	return nil
+}
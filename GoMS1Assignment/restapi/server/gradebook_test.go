@@ -0,0 +1,128 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFinalPercent(t *testing.T) {
+	assessments := map[string]database.AssessmentInfo{
+		"1": {Name: "Quiz", Weight: 20, MaxScore: 10},
+		"2": {Name: "Project", Weight: 30, MaxScore: 50},
+		"3": {Name: "Exam", Weight: 50, MaxScore: 100},
+	}
+	tests := []struct {
+		name        string
+		assessments map[string]database.AssessmentInfo
+		scores      map[string]float64
+		want        float64
+	}{
+		{"full marks", assessments, map[string]float64{"1": 10, "2": 50, "3": 100}, 100},
+		{"weighted", assessments, map[string]float64{"1": 5, "2": 40, "3": 70}, 69},
+		{"missing scores count as 0", assessments, map[string]float64{"3": 100}, 50},
+		{"no scores", assessments, nil, 0},
+		{"weights under 100 are scaled to the total", map[string]database.AssessmentInfo{
+			"1": {Weight: 20, MaxScore: 10},
+			"2": {Weight: 20, MaxScore: 10},
+		}, map[string]float64{"1": 10, "2": 5}, 75},
+		{"rounded to 2 decimal places", map[string]database.AssessmentInfo{
+			"1": {Weight: 10, MaxScore: 3},
+		}, map[string]float64{"1": 1}, 33.33},
+		{"scores of other assessments are ignored", map[string]database.AssessmentInfo{
+			"1": {Weight: 10, MaxScore: 10},
+		}, map[string]float64{"1": 10, "9": 0}, 100},
+		{"no assessments", nil, map[string]float64{"1": 10}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := finalPercent(tt.assessments, tt.scores); got != tt.want {
+				t.Errorf("finalPercent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLetterGrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		scale   map[string]float64
+		percent float64
+		want    string
+	}{
+		{"top", defaultGradeScale, 100, "A"},
+		{"on a boundary", defaultGradeScale, 80, "A"},
+		{"just below a boundary", defaultGradeScale, 79.99, "B"},
+		{"between boundaries", defaultGradeScale, 65, "C"},
+		{"lowest", defaultGradeScale, 0, "F"},
+		{"custom scale", map[string]float64{"P": 40, "HD": 85, "N": 0}, 84.99, "P"},
+		{"below every boundary", map[string]float64{"P": 50}, 49, ""},
+		{"empty scale", nil, 90, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := letterGrade(tt.scale, tt.percent); got != tt.want {
+				t.Errorf("letterGrade(%v) = %q, want %q", tt.percent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGradeScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		scale   map[string]float64
+		wantErr string // empty if the scale is valid
+	}{
+		{"default", defaultGradeScale, ""},
+		{"any order", map[string]float64{"F": 0, "A": 90, "C": 50, "B": 75}, ""},
+		{"two-character grades", map[string]float64{"HD": 85, "D": 75, "P": 50, "N": 0}, ""},
+		{"single grade", map[string]float64{"P": 0}, ""},
+		{"overlapping boundaries", map[string]float64{"A": 80, "B": 80, "F": 0}, "More than one grade has the boundary 80"},
+		{"no grade at 0", map[string]float64{"A": 80, "B": 50}, "The lowest grade must have a boundary of 0"},
+		{"negative boundary", map[string]float64{"A": 80, "F": -1}, "Boundary of grade F must be between 0 and 100"},
+		{"boundary over 100", map[string]float64{"A": 100.5, "F": 0}, "Boundary of grade A must be between 0 and 100"},
+		{"empty grade", map[string]float64{"": 50, "F": 0}, "Grades must be of 1 or 2 characters"},
+		{"long grade", map[string]float64{"ABC": 50, "F": 0}, "Grades must be of 1 or 2 characters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateGradeScale(tt.scale)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateGradeScale = %v, want nil", err)
+			} else if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("validateGradeScale = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteGradebookCSV(t *testing.T) {
+	book := gradebookInfo{
+		Assessments: map[string]database.AssessmentInfo{
+			"10": {Name: "Exam", Weight: 60, MaxScore: 100},
+			"9":  {Name: "Quiz", Weight: 40, MaxScore: 10},
+		},
+		Students: map[string]gradeInfo{
+			"S2": {Name: "Bo", Scores: map[string]float64{"9": 5}, Percent: 20, Grade: "F"},
+			"S1": {Name: "Al, Jr", Scores: map[string]float64{"9": 10, "10": 75.5}, Percent: 85.3, Grade: "A"},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	writeGradebookCSV(rec, "GO101", book)
+
+	want := "StudentID,Name,Quiz (40% of 10),Exam (60% of 100),Percent,Grade\n" +
+		"S1,\"Al, Jr\",10,75.5,85.30,A\n" +
+		"S2,Bo,5,,20.00,F\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", got, want)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != "attachment; filename=GO101-gradebook.csv" {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", got)
+	}
+}
@@ -1,183 +1,300 @@
-package server
-
-import (
-	"GoMS1Assignment/restapi/database"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"net/http"
-
-	"github.com/gorilla/mux"
-	"github.com/kennygrant/sanitize"
-)
-
-// courseInfo struct for the json
-type courseInfo struct {
-	Title string `json:"Title"`
-}
-
-// validKey checks that the access keys supplied to the REST API is valid. Returns a bool.
-func validKey(r *http.Request) bool {
-	v := r.URL.Query()
-	if key, ok := v["key"]; ok {
-		urlKey := sanitize.Accents(key[0]) // Sanitise the url param string
-
-		// Checks key in the database
-		if database.ValidKey(urlKey) {
-			return true
-		} else {
-			return false
-		}
-	} else {
-		return false
-	}
-}
-
-// home is the handler function to display the home page of the REST API.
-func home(w http.ResponseWriter, r *http.Request) {
-	fmt.Fprintf(w, "Welcome to the GoSchool REST API!")
-}
-
-// allcourses is the handler function to retrieve all courses.
-// It converts the map object retrieved into JSON and passes it back to the client.
-func allcourses(w http.ResponseWriter, r *http.Request) {
-	if !validKey(r) {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("401 - Invalid key"))
-		return
-	}
-	// Get all courses from the database
-	courses := database.GetAllCourses()
-
-	// convert the map object to JSON, and pass it back to the client
-	json.NewEncoder(w).Encode(courses)
-}
-
-// courses is the handler function for CRUD operations sent by the client.
-// The operations for GET, POST, PUT and DELETE will be determined by switch
-// and its respective functions will be called.
-func course(w http.ResponseWriter, r *http.Request) {
-	if !validKey(r) {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("401 - Invalid key"))
-		return
-	}
-
-	params := mux.Vars(r)
-
-	switch r.Method {
-	case "GET": // GET is for retrieving course
-		getCourse(params, w)
-	case "POST": // POST is for creating new course
-		addCourse(params, w, r)
-	case "PUT": //---PUT is for updating course
-		updateCourse(params, w, r)
-	case "DELETE": // DELETE is for deleting course
-		deleteCourse(params, w)
-	}
-}
-
-// getCourse implements the GET method invoked by the client and
-// retrieves the course detail with the course id given.
-func getCourse(params map[string]string, w http.ResponseWriter) {
-	// Get courses from the database
-	courses := database.GetCourse(params["courseid"])
-
-	// Course exists
-	if len(courses) != 0 {
-		// convert the map object to JSON, and pass it back to the client
-		json.NewEncoder(w).Encode(courses)
-	} else {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("404 - No course found"))
-	}
-}
-
-// addCourse implements the POST method invoked by the client and
-// adds a course with the course id and title given.
-func addCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
-	// read the string sent to the service
-	reqBody, err := ioutil.ReadAll(r.Body)
-	if err == nil {
-		newCourse := convertJSON(reqBody, w, r)
-
-		// Check if course exists;
-		courses := database.GetCourse(params["courseid"])
-
-		// Course does not exists
-		if len(courses) == 0 {
-			// Add course information into the database
-			database.AddCourse(params["courseid"], newCourse.Title)
-
-			w.WriteHeader(http.StatusCreated)
-			w.Write([]byte("201 - Course added: " + params["courseid"]))
-		} else {
-			w.WriteHeader(http.StatusConflict)
-			w.Write([]byte("409 - Duplicate course ID"))
-		}
-	} else {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		w.Write([]byte("422 - Please supply course information in JSON format"))
-	}
-}
-
-// updateCourse implements the PUT method invoked by the client and
-// update a course title with the course id given.
-func updateCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
-	// read the string sent to the service
-	reqBody, err := ioutil.ReadAll(r.Body)
-	if err == nil {
-		newCourse := convertJSON(reqBody, w, r)
-
-		// Check if course exists;
-		courses := database.GetCourse(params["courseid"])
-
-		// Course exists
-		if len(courses) != 0 {
-			// Update course in the database
-			database.UpdateCourse(params["courseid"], newCourse.Title)
-
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("200 - Course updated"))
-		} else {
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("404 - No course found"))
-		}
-	} else {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		w.Write([]byte("422 - Please supply course information in JSON format"))
-	}
-}
-
-// deleteCourse implements the DELETE method invoked by the client and
-// deletes a course with the course id given.
-func deleteCourse(params map[string]string, w http.ResponseWriter) {
-	// Check if course exists;
-	courses := database.GetCourse(params["courseid"])
-
-	// Course exists
-	if len(courses) != 0 {
-		// Delete course from the database
-		database.DeleteCourse(params["courseid"])
-
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("200 - Course deleted"))
-	} else {
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte("404 - No course found"))
-	}
-}
-
-// convertJSON converts the client JSON to object and returns the unmarshal data.
-func convertJSON(reqBody []byte, w http.ResponseWriter, r *http.Request) courseInfo {
-	var newCourse courseInfo
-
-	// convert JSON to object
-	json.Unmarshal(reqBody, &newCourse)
-
-	if newCourse.Title == "" {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		w.Write([]byte("422 - Please supply course information in JSON format"))
-	}
-	return newCourse
-}
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kennygrant/sanitize"
+)
+
+// courseInfo struct for the json
+type courseInfo struct {
+	Title       string  `json:"Title"`
+	Description *string `json:"Description,omitempty"` // Markdown; "" for none, kept unchanged by PUT if omitted
+	Capacity    *int    `json:"Capacity,omitempty"`    // Maximum enrolled students; 0 or omitted for unlimited
+	Department  *string `json:"Department,omitempty"`  // Department ID; "" for none, kept unchanged by PUT if omitted
+}
+
+// validKey checks that the access keys supplied to the REST API is valid. Returns a bool.
+func validKey(r *http.Request) bool {
+	v := r.URL.Query()
+	if key, ok := v["key"]; ok {
+		urlKey := sanitize.Accents(key[0]) // Sanitise the url param string
+
+		// Checks key in the database
+		if label, ok := database.ValidKey(r.Context(), urlKey); ok {
+			// Record the key label for the access log
+			if info := getRequestInfo(r.Context()); info != nil {
+				if label == "" {
+					label = "unlabelled"
+				}
+				info.KeyLabel = label
+			}
+			return true
+		} else {
+			return false
+		}
+	} else {
+		return false
+	}
+}
+
+// home is the handler function to display the home page of the REST API.
+func home(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "Welcome to the GoSchool REST API!")
+}
+
+// allcourses is the handler function to retrieve the active courses, optionally filtered
+// with ?instructor= to the courses an instructor is assigned to, ?department= to the
+// courses of a department and ?tag= to the courses with a tag. Courses in another status
+// are retrieved with ?status=, or courses in any status with ?status=all.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func allcourses(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	filter := database.CourseFilter{
+		Instructor: r.URL.Query().Get("instructor"),
+		Department: r.URL.Query().Get("department"),
+		Tag:        normaliseTag(r.URL.Query().Get("tag")),
+		Status:     r.URL.Query().Get("status"),
+	}
+
+	switch filter.Status {
+	case "":
+		filter.Status = database.Active
+	case "all":
+		filter.Status = ""
+	default:
+		if _, ok := courseTransitions[filter.Status]; !ok {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - Please supply a status of draft, pending, active, retired or all"))
+			return
+		}
+	}
+
+	// Get all courses from the database
+	courses := database.GetAllCourses(r.Context(), filter)
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(courses)
+}
+
+// courses is the handler function for CRUD operations sent by the client.
+// The operations for GET, POST, PUT and DELETE will be determined by switch
+// and its respective functions will be called.
+func course(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving course
+		getCourse(params, w, r)
+	case "POST": // POST is for creating new course
+		addCourse(params, w, r)
+	case "PUT": //---PUT is for updating course
+		updateCourse(params, w, r)
+	case "DELETE": // DELETE is for deleting course
+		deleteCourse(params, w, r)
+	}
+}
+
+// getCourse implements the GET method invoked by the client and
+// retrieves the course detail with the course id given, or redirects to the
+// course renamed from it.
+func getCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Get courses from the database
+	courses := database.GetCourse(r.Context(), params["courseid"])
+
+	// Course exists
+	if len(courses) != 0 {
+		// convert the map object to JSON, and pass it back to the client
+		json.NewEncoder(w).Encode(courses)
+	} else if newID, ok := database.GetCourseAlias(r.Context(), params["courseid"]); ok {
+		// Course was renamed
+		redirectRenamed(w, r, newID)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+	}
+}
+
+// addCourse implements the POST method invoked by the client and
+// adds a draft course with the course id and title given.
+func addCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check the course ID against the rules
+	if errs := rules.ValidateCourseID(params["courseid"]); len(errs) != 0 {
+		writeFieldErrors(w, errs)
+		return
+	}
+
+	newCourse, ok := convertJSON(w, r)
+	if !ok {
+		return
+	}
+
+	if !checkDepartment(w, r, newCourse) {
+		return
+	}
+
+	// Add course information into the database
+	capacity := 0
+	if newCourse.Capacity != nil {
+		capacity = *newCourse.Capacity
+	}
+	department := ""
+	if newCourse.Department != nil {
+		department = *newCourse.Department
+	}
+	description := ""
+	if newCourse.Description != nil {
+		description = *newCourse.Description
+	}
+
+	// The database rejects a course ID in use, or which a course was renamed from
+	err := database.AddCourse(r.Context(), params["courseid"], newCourse.Title, description, capacity, department)
+	if errors.Is(err, database.ErrDuplicateCourseID) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Duplicate course ID"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Error adding course"))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("201 - Course added: " + params["courseid"]))
+}
+
+// updateCourse implements the PUT method invoked by the client and
+// update a course title with the course id given.
+func updateCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newCourse, ok := convertJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if course exists;
+	courses := database.GetCourse(r.Context(), params["courseid"])
+
+	// Course exists
+	if len(courses) != 0 {
+		if !checkDepartment(w, r, newCourse) {
+			return
+		}
+
+		// Update course in the database
+		// Keep the existing capacity, department and description if none was supplied
+		capacity := courses[params["courseid"]].Capacity
+		if newCourse.Capacity != nil {
+			capacity = *newCourse.Capacity
+		}
+		department := courses[params["courseid"]].Department
+		if newCourse.Department != nil {
+			department = *newCourse.Department
+		}
+		description := courses[params["courseid"]].Description
+		if newCourse.Description != nil {
+			description = *newCourse.Description
+		}
+		err := database.UpdateCourse(r.Context(), params["courseid"], newCourse.Title, description, capacity, department)
+		if errors.Is(err, database.ErrNoDepartment) {
+			// The department was deleted since it was checked
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - No department found: " + department))
+			return
+		} else if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Error updating course"))
+			return
+		}
+
+		// Seats may have been added for students on the waitlist
+		database.PromoteWaitlisted(r.Context(), params["courseid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Course updated"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+	}
+}
+
+// deleteCourse implements the DELETE method invoked by the client and
+// deletes a course with the course id given.
+func deleteCourse(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check if course exists;
+	courses := database.GetCourse(r.Context(), params["courseid"])
+
+	// Course exists
+	if len(courses) != 0 {
+		// Delete course from the database, and then the files of its attachments from the blob store
+		attachments := database.GetAttachments(r.Context(), params["courseid"])
+		if err := database.DeleteCourse(r.Context(), params["courseid"]); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Error deleting course"))
+			return
+		}
+		for _, attachment := range attachments {
+			if err := blobs.Delete(attachment.BlobKey); err != nil {
+				logger(r.Context()).Error("deleting attachment file", "course", params["courseid"], "error", err)
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Course deleted"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+	}
+}
+
+// checkDepartment checks that the department of the course, if given, exists.
+// If it does not a 422 response is written and false is returned.
+func checkDepartment(w http.ResponseWriter, r *http.Request, course courseInfo) bool {
+	if course.Department == nil || *course.Department == "" {
+		return true
+	}
+	if len(database.GetDepartment(r.Context(), *course.Department)) == 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - No department found: " + *course.Department))
+		return false
+	}
+	return true
+}
+
+// convertJSON converts the client JSON to a course and validates it against the rules.
+// If the course is not valid a response is written and false is returned.
+func convertJSON(w http.ResponseWriter, r *http.Request) (courseInfo, bool) {
+	var newCourse courseInfo
+	if !decodeJSON(w, r, &newCourse) {
+		return newCourse, false
+	}
+
+	// Check the details against the rules
+	course := validation.Course{Title: newCourse.Title}
+	if newCourse.Description != nil {
+		course.Description = *newCourse.Description
+	}
+	if newCourse.Capacity != nil {
+		course.Capacity = *newCourse.Capacity
+	}
+	if errs := rules.ValidateCourse(course); len(errs) != 0 {
+		writeFieldErrors(w, errs)
+		return newCourse, false
+	}
+	return newCourse, true
+}
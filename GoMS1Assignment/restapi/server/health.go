@@ -0,0 +1,116 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Build information, set at build time with
+//
+//	go build -ldflags "-X GoMS1Assignment/restapi/server.version=v1.0.0 -X GoMS1Assignment/restapi/server.commit=abc123 -X GoMS1Assignment/restapi/server.buildTime=2021-06-17T00:00:00Z"
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
+// versionInfo struct for the json
+type versionInfo struct {
+	Version   string `json:"Version"`
+	Commit    string `json:"Commit"`
+	BuildTime string `json:"BuildTime"`
+	GoVersion string `json:"GoVersion"`
+}
+
+// healthStatus struct for the json
+type healthStatus struct {
+	Status string            `json:"Status"`
+	Checks map[string]string `json:"Checks,omitempty"`
+}
+
+// healthz is the handler function for the liveness check.
+// It reports that the process is up and able to serve requests.
+func healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthStatus{Status: "ok"})
+}
+
+// readyz is the handler function for the readiness check.
+// It pings the database and checks that all schema migrations have been applied.
+// The errors are logged rather than reported, as the check is not behind a key.
+func readyz(w http.ResponseWriter, r *http.Request) {
+	status := healthStatus{Status: "ok", Checks: map[string]string{}}
+	code := http.StatusOK
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	if database.DB == nil {
+		status.Checks["database"] = "not connected"
+		code = http.StatusServiceUnavailable
+	} else if err := database.DB.PingContext(ctx); err != nil {
+		logger(r.Context()).Error("readiness check: pinging database", "error", err)
+		status.Checks["database"] = "unavailable"
+		code = http.StatusServiceUnavailable
+	} else {
+		status.Checks["database"] = "ok"
+
+		pending, err := database.PendingMigrations(ctx)
+		if err != nil {
+			logger(r.Context()).Error("readiness check: getting pending migrations", "error", err)
+			status.Checks["migrations"] = "unavailable"
+			code = http.StatusServiceUnavailable
+		} else if len(pending) != 0 {
+			status.Checks["migrations"] = "pending: " + pending[0]
+			code = http.StatusServiceUnavailable
+		} else {
+			status.Checks["migrations"] = "ok"
+		}
+	}
+
+	if code != http.StatusOK {
+		status.Status = "unavailable"
+	}
+	writeHealth(w, code, status)
+}
+
+// versionHandler is the handler function to display the build information of the REST API.
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	info := versionInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	// Fall back to the version control details embedded by the go tool
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// writeHealth writes the health status as JSON with the status code given.
+func writeHealth(w http.ResponseWriter, code int, status healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
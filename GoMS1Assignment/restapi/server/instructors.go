@@ -0,0 +1,272 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// instructorRoles are the allowed roles of an instructor assigned to a course.
+var instructorRoles = map[string]bool{
+	database.Lead:      true,
+	database.Assistant: true,
+}
+
+// assignmentInfo struct for the json
+type assignmentInfo struct {
+	Role string `json:"Role"`
+}
+
+// allinstructors is the handler function to retrieve all instructors.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func allinstructors(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	// Get all instructors from the database
+	instructors := database.GetAllInstructors(r.Context())
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(instructors)
+}
+
+// instructor is the handler function for CRUD operations on instructors sent by the client.
+// The operations for GET, POST, PUT and DELETE will be determined by switch
+// and its respective functions will be called.
+func instructor(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving instructor
+		getInstructor(params, w, r)
+	case "POST": // POST is for creating new instructor
+		addInstructor(params, w, r)
+	case "PUT": //---PUT is for updating instructor
+		updateInstructor(params, w, r)
+	case "DELETE": // DELETE is for deleting instructor
+		deleteInstructor(params, w, r)
+	}
+}
+
+// getInstructor implements the GET method invoked by the client and
+// retrieves the instructor detail with the instructor id given.
+func getInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Get instructor from the database
+	instructors := database.GetInstructor(r.Context(), params["instructorid"])
+
+	// Instructor exists
+	if len(instructors) != 0 {
+		// convert the map object to JSON, and pass it back to the client
+		json.NewEncoder(w).Encode(instructors)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No instructor found"))
+	}
+}
+
+// addInstructor implements the POST method invoked by the client and
+// adds an instructor with the instructor id and details given.
+func addInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newInstructor, ok := convertInstructorJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if instructor exists
+	instructors := database.GetInstructor(r.Context(), params["instructorid"])
+
+	// Instructor does not exist
+	if len(instructors) == 0 {
+		// Add instructor information into the database
+		database.AddInstructor(r.Context(), params["instructorid"], newInstructor)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("201 - Instructor added: " + params["instructorid"]))
+	} else {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Duplicate instructor ID"))
+	}
+}
+
+// updateInstructor implements the PUT method invoked by the client and
+// updates the details of an instructor with the instructor id given.
+func updateInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newInstructor, ok := convertInstructorJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if instructor exists
+	instructors := database.GetInstructor(r.Context(), params["instructorid"])
+
+	// Instructor exists
+	if len(instructors) != 0 {
+		// Update instructor in the database
+		database.UpdateInstructor(r.Context(), params["instructorid"], newInstructor)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Instructor updated"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No instructor found"))
+	}
+}
+
+// deleteInstructor implements the DELETE method invoked by the client and
+// deletes an instructor, and their course assignments, with the instructor id given.
+func deleteInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check if instructor exists
+	instructors := database.GetInstructor(r.Context(), params["instructorid"])
+
+	// Instructor exists
+	if len(instructors) != 0 {
+		// Delete instructor from the database
+		database.DeleteInstructor(r.Context(), params["instructorid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Instructor deleted"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No instructor found"))
+	}
+}
+
+// courseInstructors is the handler function to retrieve the instructors assigned to a course.
+func courseInstructors(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(database.GetCourseInstructors(r.Context(), params["courseid"]))
+}
+
+// courseInstructor is the handler function to assign an instructor to a course with PUT,
+// which also changes the role of an instructor already assigned, and to remove them with DELETE.
+func courseInstructor(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "PUT": // PUT is for assigning instructor
+		assignInstructor(params, w, r)
+	case "DELETE": // DELETE is for removing instructor
+		unassignInstructor(params, w, r)
+	}
+}
+
+// assignInstructor implements the PUT method invoked by the client and
+// assigns the instructor to the course with the role given.
+func assignInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	var assignment assignmentInfo
+
+	if !decodeJSON(w, r, &assignment) {
+		return
+	}
+
+	if !instructorRoles[assignment.Role] {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Please supply a Role of lead or assistant in JSON format"))
+		return
+	}
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+	if len(database.GetInstructor(r.Context(), params["instructorid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No instructor found"))
+		return
+	}
+
+	// The instructor cannot teach sessions of another course at the same time
+	assigned, clashes := database.AssignInstructor(r.Context(), params["courseid"], params["instructorid"], assignment.Role)
+	if len(clashes) != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Session clashes: " + strings.Join(clashes, "; ")))
+		return
+	}
+	if !assigned {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Instructor could not be assigned"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Instructor assigned as " + assignment.Role))
+}
+
+// unassignInstructor implements the DELETE method invoked by the client and
+// removes the instructor from the course.
+func unassignInstructor(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	assigned := database.GetCourseInstructors(r.Context(), params["courseid"])
+
+	// Instructor is assigned to the course
+	if _, ok := assigned[params["instructorid"]]; ok {
+		database.UnassignInstructor(r.Context(), params["courseid"], params["instructorid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Instructor removed"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - Instructor not assigned to course"))
+	}
+}
+
+// convertInstructorJSON converts the client JSON to an instructor and validates it.
+// If the instructor is not valid a 422 response is written and false is returned.
+func convertInstructorJSON(w http.ResponseWriter, r *http.Request) (database.InstructorInfo, bool) {
+	var newInstructor database.InstructorInfo
+
+	if !decodeJSON(w, r, &newInstructor) {
+		return newInstructor, false
+	}
+
+	if err := validateInstructor(newInstructor); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newInstructor, false
+	}
+	return newInstructor, true
+}
+
+// validateInstructor checks that the instructor details are valid. Returns error type.
+func validateInstructor(instructor database.InstructorInfo) error {
+	if instructor.Name == "" || len(instructor.Name) > 100 {
+		return errors.New("Please supply an instructor name of up to 100 characters")
+	}
+	if _, err := mail.ParseAddress(instructor.Email); err != nil || len(instructor.Email) > 254 {
+		return errors.New("Please supply a valid email address")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kennygrant/sanitize"
+)
+
+// statusChangeInfo struct for the json of a status change requested with PUT on the
+// status of a course
+type statusChangeInfo struct {
+	Status  string `json:"Status"`
+	Comment string `json:"Comment"`
+}
+
+// reviewInfo struct for the json of a review of a course pending approval
+type reviewInfo struct {
+	Decision string `json:"Decision"` // approve or reject
+	Comment  string `json:"Comment"`  // required to reject
+}
+
+// courseTransitions lists the statuses a course may be changed to from each status with
+// PUT on its status. A pending course becomes active only when approved in a review.
+var courseTransitions = map[string][]string{
+	database.Draft:   {database.Pending},
+	database.Pending: {database.Draft},
+	database.Active:  {database.Retired},
+	database.Retired: {database.Draft},
+}
+
+// reviewDecisions are the statuses a pending course is changed to by each review decision.
+var reviewDecisions = map[string]string{
+	"approve": database.Active,
+	"reject":  database.Draft,
+}
+
+// courseStatus is the handler function to retrieve the history of the status of a course
+// with GET, and to change its status with PUT following courseTransitions: submitting a
+// draft for approval, withdrawing it from review, retiring an active course, or reopening a
+// retired course as a draft.
+func courseStatus(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	current, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the history of the status
+		json.NewEncoder(w).Encode(database.GetCourseHistory(r.Context(), params["courseid"]))
+	case "PUT": // PUT is for changing the status
+		var newStatus statusChangeInfo
+
+		if !decodeJSON(w, r, &newStatus) {
+			return
+		}
+
+		err := validateComment(newStatus.Comment, false)
+		if err != nil || newStatus.Status == "" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - Please supply the Status in JSON format, with a Comment of up to 255 characters"))
+			return
+		}
+
+		if !allowedTransition(courseTransitions[current.Status], newStatus.Status) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Course cannot be changed from " + current.Status + " to " + newStatus.Status))
+			return
+		}
+
+		changeCourseStatus(w, r, params["courseid"], current.Status, newStatus.Status, newStatus.Comment)
+	}
+}
+
+// review is the handler function for an admin to approve or reject a course pending
+// approval with POST, with a comment for the author. A rejected course returns to draft.
+func review(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	if !adminKey(r) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("403 - Admin key required"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	current, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	var newReview reviewInfo
+
+	if !decodeJSON(w, r, &newReview) {
+		return
+	}
+
+	status, ok := reviewDecisions[newReview.Decision]
+	err := validateComment(newReview.Comment, newReview.Decision == "reject")
+	if !ok {
+		err = errors.New("Please supply a Decision of approve or reject")
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return
+	}
+
+	if current.Status != database.Pending {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Course is " + current.Status + ", not pending approval"))
+		return
+	}
+
+	changeCourseStatus(w, r, params["courseid"], current.Status, status, newReview.Comment)
+}
+
+// changeCourseStatus changes the status of the course and writes the response. The change is
+// rejected with 409 if the status of the course was changed by another request meanwhile.
+func changeCourseStatus(w http.ResponseWriter, r *http.Request, courseID, from, to, comment string) {
+	change := database.StatusChangeInfo{From: from, To: to, Comment: comment, ChangedBy: keyLabel(r)}
+	if !database.SetCourseStatus(r.Context(), courseID, change) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Course status was changed by another request"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Course is " + to))
+}
+
+// allowedTransition reports whether status is among the statuses allowed.
+func allowedTransition(allowed []string, status string) bool {
+	for _, s := range allowed {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// adminKey checks if the access key given in the url is an admin key. Returns a bool.
+func adminKey(r *http.Request) bool {
+	key := sanitize.Accents(r.URL.Query().Get("key")) // Sanitise the url param string
+	return database.AdminKey(r.Context(), key)
+}
+
+// keyLabel returns the label of the access key of the request recorded by validKey.
+func keyLabel(r *http.Request) string {
+	if info := getRequestInfo(r.Context()); info != nil {
+		return info.KeyLabel
+	}
+	return ""
+}
+
+// validateComment checks that the comment on a status change is no longer than 255
+// characters, and given if required. Returns error type.
+func validateComment(comment string, required bool) error {
+	if required && comment == "" {
+		return errors.New("Please supply a Comment explaining the rejection")
+	}
+	if len(comment) > 255 {
+		return errors.New("Please supply a Comment of up to 255 characters")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestIDHeader is the header used to carry the request ID between the client and the REST API.
+const requestIDHeader = "X-Request-ID"
+
+// contextKey is the type for values stored in the request context by this package.
+type contextKey int
+
+const requestInfoKey contextKey = iota
+
+// requestInfo holds the per-request details which are filled in as the request
+// is handled and written to the access log once it completes.
+type requestInfo struct {
+	ID       string
+	KeyLabel string
+}
+
+// initLogger sets the default logger to write JSON to stdout at the level
+// given by the LOG_LEVEL environment variable (debug, info, warn or error).
+func initLogger() {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})))
+}
+
+// requestIDMiddleware propagates the X-Request-ID header sent by the client, or generates
+// a new ID if none was sent, and echoes it back in the response.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		info := &requestInfo{ID: id}
+		ctx := context.WithValue(r.Context(), requestInfoKey, info)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessLogMiddleware writes an access log entry for each request once it has been handled.
+// The API key is logged by its label only; the key itself is never logged.
+func accessLogMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		attrs := []any{
+			"method", r.Method,
+			"route", routeTemplate(r),
+			"status", rec.status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if span := trace.SpanFromContext(r.Context()); span.SpanContext().IsValid() {
+			attrs = append(attrs, "trace_id", span.SpanContext().TraceID().String())
+		}
+		if info := getRequestInfo(r.Context()); info != nil {
+			attrs = append(attrs, "request_id", info.ID)
+			if info.KeyLabel != "" {
+				attrs = append(attrs, "api_key", info.KeyLabel)
+			}
+		}
+		slog.Info("request", attrs...)
+	})
+}
+
+// getRequestInfo returns the requestInfo stored in the context, or nil if there is none.
+func getRequestInfo(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey).(*requestInfo)
+	return info
+}
+
+// logger returns the default logger with the request ID of the context attached.
+func logger(ctx context.Context) *slog.Logger {
+	if info := getRequestInfo(ctx); info != nil {
+		return slog.Default().With("request_id", info.ID)
+	}
+	return slog.Default()
+}
+
+// newRequestID generates a random 128-bit request ID encoded as hex.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID checks that a request ID received from a client is safe to log and echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	return strings.IndexFunc(id, func(c rune) bool {
+		return !(c == '-' || c == '_' || c == '.' ||
+			'0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z')
+	}) < 0
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "goschool_api_http_requests_total",
+		Help: "Total number of HTTP requests handled by the REST API.",
+	}, []string{"route", "method", "code"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goschool_api_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests handled by the REST API.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+// statusRecorder wraps http.ResponseWriter to capture the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before passing it on.
+func (rec *statusRecorder) WriteHeader(code int) {
+	rec.status = code
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+// metricsMiddleware records the request count, status code and latency of each request
+// labelled by the route template, e.g. /api/v1/courses/{courseid}, to keep the
+// number of series bounded.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := routeTemplate(r)
+		httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+		httpDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
+
+// countUnmatched sets the handlers of the router for requests which match no route, or no
+// method of a route, to record their metrics too, as the middleware of the router is only
+// run for matched routes.
+func countUnmatched(router *mux.Router) {
+	router.NotFoundHandler = metricsMiddleware(http.NotFoundHandler())
+	router.MethodNotAllowedHandler = metricsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+}
+
+// routeTemplate returns the path template of the matched route. Returns "unmatched"
+// if the request did not match any route.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return "unmatched"
+}
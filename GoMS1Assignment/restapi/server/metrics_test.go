@@ -0,0 +1,39 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestCountUnmatched checks that requests which match no route, or no method of a route,
+// are counted as well as matched ones.
+func TestCountUnmatched(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(metricsMiddleware)
+	countUnmatched(router)
+	router.HandleFunc("/metrics-test", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+
+	tests := []struct {
+		method, path string
+		route, code  string
+	}{
+		{"GET", "/metrics-test", "/metrics-test", "200"},
+		{"GET", "/metrics-test/missing", "unmatched", "404"},
+		{"POST", "/metrics-test", "unmatched", "405"},
+	}
+	for _, tt := range tests {
+		counter := httpRequests.WithLabelValues(tt.route, tt.method, tt.code)
+		before := testutil.ToFloat64(counter)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if testutil.ToFloat64(counter) != before+1 {
+			t.Errorf("%s %s was not counted with route %s and code %s", tt.method, tt.path, tt.route, tt.code)
+		}
+	}
+}
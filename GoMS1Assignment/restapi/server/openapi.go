@@ -0,0 +1,29 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openapiSpec is the OpenAPI 3 document describing the /api/v1 endpoints.
+// It must be kept in step with initaliseHandlers; see openapi_test.go.
+//
+//go:embed openapi.json
+var openapiSpec []byte
+
+// docsPage renders openapiSpec as browsable documentation.
+//
+//go:embed docs.html
+var docsPage []byte
+
+// openapi is the handler function to serve the OpenAPI document of the REST API.
+func openapi(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapiSpec)
+}
+
+// docs is the handler function to display the documentation page of the REST API.
+func docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(docsPage)
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestOpenAPIMatchesRoutes fails when a route registered by initaliseHandlers under
+// /api/v1 is missing from openapi.json, or the document describes an operation
+// which is not routed.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapiSpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "patch", "head", "options":
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	router := mux.NewRouter()
+	initaliseHandlers(router)
+
+	routed := make(map[string]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil || !strings.HasPrefix(path, "/api/v1/") {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			t.Errorf("route %s does not restrict its methods", path)
+			return nil
+		}
+		for _, method := range methods {
+			routed[method+" "+path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range sortedKeys(routed) {
+		if !documented[op] {
+			t.Errorf("%s is routed but missing from openapi.json", op)
+		}
+	}
+	for _, op := range sortedKeys(documented) {
+		if !routed[op] {
+			t.Errorf("%s is in openapi.json but not routed", op)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
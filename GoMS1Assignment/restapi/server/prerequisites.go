@@ -0,0 +1,259 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// prerequisiteInfo struct for the json
+type prerequisiteInfo struct {
+	Title string `json:"Title"`
+}
+
+// planStep struct for the json of a course in a study plan
+type planStep struct {
+	CourseID string `json:"CourseID"`
+	Title    string `json:"Title"`
+	Stage    int    `json:"Stage"` // 1 for courses without prerequisites
+}
+
+// eligibilityRequest struct for the json
+type eligibilityRequest struct {
+	Completed []string `json:"Completed"`
+}
+
+// eligibilityInfo struct for the json
+type eligibilityInfo struct {
+	Eligible   []string            `json:"Eligible"`
+	Ineligible map[string][]string `json:"Ineligible"` // the missing prerequisites of each course
+}
+
+// prerequisites is the handler function to retrieve the prerequisites of a course.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func prerequisites(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	courses := database.GetAllCourses(r.Context(), database.CourseFilter{})
+	if _, ok := courses[params["courseid"]]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	graph := database.GetPrerequisiteGraph(r.Context())
+
+	var prerequisites = make(map[string]prerequisiteInfo)
+	for _, id := range graph[params["courseid"]] {
+		prerequisites[id] = prerequisiteInfo{courses[id].Title}
+	}
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(prerequisites)
+}
+
+// prerequisite is the handler function to add a prerequisite to a course with PUT
+// and remove it with DELETE.
+func prerequisite(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "PUT": // PUT is for adding prerequisite
+		addPrerequisite(params, w, r)
+	case "DELETE": // DELETE is for removing prerequisite
+		deletePrerequisite(params, w, r)
+	}
+}
+
+// addPrerequisite implements the PUT method invoked by the client and records that the
+// course requires the prerequisite. It is rejected if the prerequisite already requires
+// the course, directly or indirectly, as the courses could then never be taken.
+func addPrerequisite(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	courseID, prerequisiteID := params["courseid"], params["prerequisiteid"]
+
+	courses := database.GetAllCourses(r.Context(), database.CourseFilter{})
+	_, courseOK := courses[courseID]
+	_, prerequisiteOK := courses[prerequisiteID]
+	if !courseOK || !prerequisiteOK {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	added, cycle := database.AddPrerequisite(r.Context(), courseID, prerequisiteID)
+	if len(cycle) != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Prerequisite would create a cycle: " + courseID + " -> " + strings.Join(cycle, " -> ")))
+		return
+	}
+	if !added {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Prerequisite could not be added"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Prerequisite added: " + prerequisiteID))
+}
+
+// deletePrerequisite implements the DELETE method invoked by the client and
+// removes the prerequisite from the course.
+func deletePrerequisite(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	courseID, prerequisiteID := params["courseid"], params["prerequisiteid"]
+
+	for _, id := range database.GetPrerequisiteGraph(r.Context())[courseID] {
+		if id == prerequisiteID {
+			database.DeletePrerequisite(r.Context(), courseID, prerequisiteID)
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Prerequisite removed"))
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("404 - No prerequisite found"))
+}
+
+// studyplan is the handler function to retrieve the order in which to take a course and
+// all the courses it requires. Courses in the same stage may be taken together.
+func studyplan(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	courses := database.GetAllCourses(r.Context(), database.CourseFilter{})
+	if _, ok := courses[params["courseid"]]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	plan := studyPlan(database.GetPrerequisiteGraph(r.Context()), params["courseid"])
+	for i := range plan {
+		plan[i].Title = courses[plan[i].CourseID].Title
+	}
+
+	// convert the plan to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(plan)
+}
+
+// eligibility is the handler function to report which courses may be taken by a learner
+// who has completed the courses given, and the prerequisites missing for the others.
+// Completed courses are not reported, nor are courses which are not active.
+func eligibility(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	var req eligibilityRequest
+
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	// Only active courses are open for enrolment
+	courses := database.GetAllCourses(r.Context(), database.CourseFilter{Status: database.Active})
+	graph := database.GetPrerequisiteGraph(r.Context())
+
+	completed := make(map[string]bool)
+	for _, id := range req.Completed {
+		completed[validation.CanonicalCourseID(id)] = true
+	}
+
+	courseIDs := make([]string, 0, len(courses))
+	for courseID := range courses {
+		courseIDs = append(courseIDs, courseID)
+	}
+	result := checkEligibility(graph, courseIDs, completed)
+
+	// convert the result to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(result)
+}
+
+// checkEligibility returns which of the courses given may be taken by a learner who has
+// completed the courses in completed, and the prerequisites missing for the others, in the
+// order of the graph. Completed courses are not reported.
+func checkEligibility(graph map[string][]string, courseIDs []string, completed map[string]bool) eligibilityInfo {
+	result := eligibilityInfo{Eligible: []string{}, Ineligible: map[string][]string{}}
+	for _, courseID := range courseIDs {
+		if completed[courseID] {
+			continue
+		}
+
+		var missing []string
+		for _, id := range graph[courseID] {
+			if !completed[id] {
+				missing = append(missing, id)
+			}
+		}
+
+		if len(missing) == 0 {
+			result.Eligible = append(result.Eligible, courseID)
+		} else {
+			result.Ineligible[courseID] = missing
+		}
+	}
+	sort.Strings(result.Eligible)
+	return result
+}
+
+// studyPlan returns the course and every course it requires, directly or indirectly, in an
+// order they can be taken. Each course is placed in the stage after its last prerequisite,
+// and courses within a stage are sorted by course ID.
+func studyPlan(graph map[string][]string, courseID string) []planStep {
+	stages := make(map[string]int)
+
+	var stage func(id string) int
+	stage = func(id string) int {
+		if s, ok := stages[id]; ok {
+			return s
+		}
+		stages[id] = 0 // guards against a cycle, which addPrerequisite does not allow
+
+		s := 1
+		for _, prerequisiteID := range graph[id] {
+			if next := stage(prerequisiteID) + 1; next > s {
+				s = next
+			}
+		}
+		stages[id] = s
+		return s
+	}
+	stage(courseID)
+
+	plan := make([]planStep, 0, len(stages))
+	for id, s := range stages {
+		plan = append(plan, planStep{CourseID: id, Stage: s})
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		if plan[i].Stage != plan[j].Stage {
+			return plan[i].Stage < plan[j].Stage
+		}
+		return plan[i].CourseID < plan[j].CourseID
+	})
+	return plan
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testGraph is a prerequisite graph with a diamond: GO301 requires GO201 and DB201, which
+// both require GO101. DB201 also requires DB101.
+var testGraph = map[string][]string{
+	"GO301": {"GO201", "DB201"},
+	"GO201": {"GO101"},
+	"DB201": {"DB101", "GO101"},
+}
+
+func TestStudyPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		courseID string
+		want     []planStep
+	}{
+		{"no prerequisites", "GO101", []planStep{{CourseID: "GO101", Stage: 1}}},
+		{"chain", "GO201", []planStep{{CourseID: "GO101", Stage: 1}, {CourseID: "GO201", Stage: 2}}},
+		{"stage sorted by course ID", "DB201", []planStep{
+			{CourseID: "DB101", Stage: 1}, {CourseID: "GO101", Stage: 1}, {CourseID: "DB201", Stage: 2},
+		}},
+		{"diamond lists shared prerequisite once", "GO301", []planStep{
+			{CourseID: "DB101", Stage: 1}, {CourseID: "GO101", Stage: 1},
+			{CourseID: "DB201", Stage: 2}, {CourseID: "GO201", Stage: 2},
+			{CourseID: "GO301", Stage: 3},
+		}},
+		{"unknown course", "XX101", []planStep{{CourseID: "XX101", Stage: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := studyPlan(testGraph, tt.courseID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("studyPlan(%s) = %+v, want %+v", tt.courseID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStudyPlanStageAfterLongestPath(t *testing.T) {
+	// GO401 requires GO101 directly, and through GO201 and GO301, so it comes after GO301
+	graph := map[string][]string{
+		"GO401": {"GO101", "GO301"},
+		"GO301": {"GO201"},
+		"GO201": {"GO101"},
+	}
+	want := []planStep{
+		{CourseID: "GO101", Stage: 1}, {CourseID: "GO201", Stage: 2},
+		{CourseID: "GO301", Stage: 3}, {CourseID: "GO401", Stage: 4},
+	}
+	if got := studyPlan(graph, "GO401"); !reflect.DeepEqual(got, want) {
+		t.Errorf("studyPlan(GO401) = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckEligibility(t *testing.T) {
+	courseIDs := []string{"GO301", "GO201", "DB201", "DB101", "GO101"}
+
+	tests := []struct {
+		name      string
+		completed []string
+		want      eligibilityInfo
+	}{
+		{"nothing completed", nil, eligibilityInfo{
+			Eligible: []string{"DB101", "GO101"},
+			Ineligible: map[string][]string{
+				"GO301": {"GO201", "DB201"}, "GO201": {"GO101"}, "DB201": {"DB101", "GO101"},
+			},
+		}},
+		{"one of two prerequisites met", []string{"GO101"}, eligibilityInfo{
+			Eligible:   []string{"DB101", "GO201"},
+			Ineligible: map[string][]string{"GO301": {"GO201", "DB201"}, "DB201": {"DB101"}},
+		}},
+		{"diamond met", []string{"GO101", "DB101", "GO201", "DB201"}, eligibilityInfo{
+			Eligible: []string{"GO301"}, Ineligible: map[string][]string{},
+		}},
+		{"indirect prerequisite not required", []string{"GO201", "DB201"}, eligibilityInfo{
+			Eligible: []string{"DB101", "GO101", "GO301"}, Ineligible: map[string][]string{},
+		}},
+		{"all completed", courseIDs, eligibilityInfo{Eligible: []string{}, Ineligible: map[string][]string{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completed := make(map[string]bool)
+			for _, id := range tt.completed {
+				completed[id] = true
+			}
+			if got := checkEligibility(testGraph, courseIDs, completed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkEligibility(%v) = %+v, want %+v", tt.completed, got, tt.want)
+			}
+		})
+	}
+}
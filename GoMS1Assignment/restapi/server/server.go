@@ -1,105 +1,262 @@
-/*
-Package server initialises the handler functions for the REST API
-and implements its functions for CRUD operations.
-It also initialises the database for interfacing with the database package.
-It is separated into 2 .go files to segregate the functionalities of the application.
-
-	server.go: Initialises the handler functions and database, then starts the REST API to run
-	on the designated port.
-
-	handler.go: Implements the functions for CRUD operations as called by the client.
-	Interface with the database package for database operations.
-*/
-package server
-
-import (
-	"GoMS1Assignment/restapi/database"
-	"fmt"
-	"log"
-	"net/http"
-	"os"
-
-	"github.com/gorilla/mux"
-	"github.com/joho/godotenv"
-)
-
-// StartServer initialises the database and handler functions then
-// listens on the designated port to start the REST API running.
-func StartServer() {
-	// Initialise the database
-	initDB()
-
-	router := mux.NewRouter()
-
-	// Initialise the handlers
-	initaliseHandlers(router)
-
-	// Set the listen port
-	fmt.Println("Listening at port 5000")
-	err := http.ListenAndServe(":5000", router)
-	if err != nil {
-		log.Fatal("FATAL: ListenAndServe - ", err)
-	}
-
-	defer database.DB.Close()
-}
-
-// initaliseHandlers initialises the handlers for the REST API.
-func initaliseHandlers(router *mux.Router) {
-	router.HandleFunc("/api/v1/", home)
-	router.HandleFunc("/api/v1/courses", allcourses)
-	router.HandleFunc("/api/v1/courses/{courseid}", course).Methods("GET", "PUT", "POST", "DELETE")
-}
-
-// initDB initialises the database
-func initDB() {
-	defer func() {
-		if err := recover(); err != nil {
-			fmt.Println(">> Panic:", err)
-		}
-	}()
-
-	config := getDBConfig()
-
-	// Get database connection string
-	connectionString := database.GetConnectionString(config)
-
-	// Connect to database
-	err := database.Connect(connectionString)
-	if err != nil {
-		panic("error connecting to database")
-	}
-
-	// Test connection to database
-	err = database.DB.Ping()
-	if err != nil {
-		panic("error pinging to database")
-	} else {
-		fmt.Println("Ping to database success")
-	}
-}
-
-// getDBConfig retrieves the database configurations and returns a struct.
-func getDBConfig() database.Config {
-	// Load setup.env file from same directory
-	err := godotenv.Load("setup.env")
-	if err != nil {
-		log.Fatal("FATAL: Error loading .env file")
-	}
-
-	// Get env variables for database configuration
-	serverName := os.Getenv("SERVER_NAME")
-	dbName := os.Getenv("DB_NAME")
-	dbUserName := os.Getenv("DB_USERNAME")
-	dbPassword := os.Getenv("DB_PASSWORD")
-
-	config :=
-		database.Config{
-			ServerName: serverName,
-			User:       dbUserName,
-			Password:   dbPassword,
-			DB:         dbName,
-		}
-
-	return config
-}
+/*
+Package server initialises the handler functions for the REST API
+and implements its functions for CRUD operations.
+It also initialises the database for interfacing with the database package.
+It is separated into 25 .go files to segregate the functionalities of the application.
+
+	server.go: Initialises the handler functions and database, then starts the REST API to run
+	on the designated port.
+
+	handler.go: Implements the functions for CRUD operations as called by the client.
+	Interface with the database package for database operations.
+
+	students.go: Implements the functions for CRUD operations on students as called by the client.
+
+	departments.go: Implements the functions for CRUD operations on the departments courses
+	belong to.
+
+	tags.go: Implements the functions for the free-form tags of courses and their management.
+
+	attachments.go: Implements the upload, download and deletion of the files attached to
+	courses, such as syllabi, which are kept in the blob store.
+
+	lifecycle.go: Implements the lifecycle of courses from draft to retired, and the review
+	of courses pending approval by admins.
+
+	aliases.go: Implements the renaming of courses, which keeps their former IDs as aliases
+	redirecting to the new ID.
+
+	clone.go: Implements the cloning of courses as templates for new courses, with their
+	related data and a copy of the files of their attachments.
+
+	enrolments.go: Implements the functions for enrolling students in courses, with waitlisting
+	once a course reaches its capacity.
+
+	instructors.go: Implements the functions for CRUD operations on instructors, and
+	their assignment to courses as lead or assistant.
+
+	prerequisites.go: Implements the functions for the prerequisites of courses, which are
+	kept free of cycles, with the study plan and eligibility checks built on them.
+
+	sessions.go: Implements the functions for the weekly sessions of courses, which may not
+	double-book a room or instructor, and their export as iCalendar documents.
+
+	calendar.go: Writes iCalendar documents of weekly recurring events.
+
+	terms.go: Implements the functions for CRUD operations on academic terms, and the
+	offerings of courses in them with a capacity for each term.
+
+	gradebook.go: Implements the functions for the assessments of courses, the scores of
+	students in them and their weighted final grades, exported as JSON or CSV.
+
+	transcripts.go: Implements the functions for the courses students completed, and their
+	transcripts with term and cumulative GPA, rendered as JSON or PDF.
+
+	attendance.go: Implements the functions for the dated classes of courses, the attendance
+	of students at them, and the attendance reports with alerts for low attendance.
+
+	decode.go: Decodes the JSON bodies of requests strictly, rejecting bodies of the wrong
+	type, too large or with unknown fields.
+
+	validation.go: Configures the validation rules, serves them to the client, writes the
+	violations of the rules by field and canonicalises the course IDs of requests.
+
+	health.go: Implements the liveness, readiness and version endpoints used by
+	load balancers and monitoring.
+
+	metrics.go: Implements the middleware which records request metrics exposed
+	on /metrics for Prometheus.
+
+	logging.go: Implements the structured logger, the request ID middleware and the access log.
+
+	tracing.go: Initialises OpenTelemetry tracing and the exporter chosen at startup.
+
+	openapi.go: Serves the OpenAPI document of the REST API and its documentation page.
+*/
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+)
+
+// StartServer initialises the database and handler functions then
+// listens on the designated port to start the REST API running.
+func StartServer() {
+	// Load setup.env and initialise the logger, tracer and validation rules
+	loadEnv()
+	initLogger()
+	initRules()
+
+	shutdownTracer, err := initTracer(context.Background())
+	if err != nil {
+		slog.Error("error initialising tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracer(context.Background())
+
+	// Initialise the database and blob store
+	initDB()
+	initBlobs()
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(serviceName), requestIDMiddleware, accessLogMiddleware, metricsMiddleware,
+		canonicalCourseIDs)
+	countUnmatched(router)
+
+	// Initialise the handlers
+	initaliseHandlers(router)
+
+	// Set the listen port
+	slog.Info("listening", "port", 5000)
+	err = http.ListenAndServe(":5000", router)
+	if err != nil {
+		slog.Error("ListenAndServe failed", "error", err)
+		os.Exit(1)
+	}
+
+	defer database.DB.Close()
+}
+
+// initaliseHandlers initialises the handlers for the REST API.
+func initaliseHandlers(router *mux.Router) {
+	router.HandleFunc("/healthz", healthz).Methods("GET")
+	router.HandleFunc("/readyz", readyz).Methods("GET")
+	router.HandleFunc("/version", versionHandler).Methods("GET")
+	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	router.HandleFunc("/api/v1/", home).Methods("GET")
+	router.HandleFunc("/api/v1/openapi.json", openapi).Methods("GET")
+	router.HandleFunc("/api/v1/docs", docs).Methods("GET")
+	router.HandleFunc("/api/v1/courses", allcourses).Methods("GET")
+	// Registered before /api/v1/courses/{courseid}, whose course ID would otherwise match GO101:clone
+	router.HandleFunc("/api/v1/courses/{courseid}:clone", clone).Methods("POST")
+	router.HandleFunc("/api/v1/courses/{courseid}", course).Methods("GET", "PUT", "POST", "DELETE")
+	router.HandleFunc("/api/v1/students", allstudents).Methods("GET")
+	router.HandleFunc("/api/v1/students/{studentid}", student).Methods("GET", "PUT", "POST", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/enrolments", enrolments).Methods("GET", "POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/enrolments/{studentid}", enrolment).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/learners/{id}/courses", learnerCourses).Methods("GET")
+	router.HandleFunc("/api/v1/instructors", allinstructors).Methods("GET")
+	router.HandleFunc("/api/v1/instructors/{instructorid}", instructor).Methods("GET", "PUT", "POST", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/instructors", courseInstructors).Methods("GET")
+	router.HandleFunc("/api/v1/courses/{courseid}/instructors/{instructorid}", courseInstructor).Methods("PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/prerequisites", prerequisites).Methods("GET")
+	router.HandleFunc("/api/v1/courses/{courseid}/prerequisites/{prerequisiteid}", prerequisite).Methods("PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/studyplan", studyplan).Methods("GET")
+	router.HandleFunc("/api/v1/eligibility", eligibility).Methods("POST")
+	router.HandleFunc("/api/v1/sessions", allsessions).Methods("GET")
+	router.HandleFunc("/api/v1/courses/{courseid}/sessions", sessions).Methods("GET", "POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/sessions/{sessionid}", session).Methods("GET", "PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/calendar.ics", courseCalendar).Methods("GET")
+	router.HandleFunc("/api/v1/learners/{id}/calendar.ics", learnerCalendar).Methods("GET")
+	router.HandleFunc("/api/v1/terms", allterms).Methods("GET")
+	router.HandleFunc("/api/v1/terms/{termid}", term).Methods("GET", "PUT", "POST", "DELETE")
+	router.HandleFunc("/api/v1/terms/{termid}/offerings", termOfferings).Methods("GET")
+	router.HandleFunc("/api/v1/terms/{termid}/offerings/{courseid}", offering).Methods("GET", "PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/offerings", courseOfferings).Methods("GET")
+	router.HandleFunc("/api/v1/courses/{courseid}/assessments", assessments).Methods("GET", "POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/assessments/{assessmentid}", assessment).Methods("GET", "PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/assessments/{assessmentid}/scores", scores).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/courses/{courseid}/assessments/{assessmentid}/scores/{studentid}", score).Methods("DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/gradescale", gradescale).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/courses/{courseid}/gradebook", gradebook).Methods("GET")
+	router.HandleFunc("/api/v1/learners/{id}/transcript", transcript).Methods("GET")
+	router.HandleFunc("/api/v1/learners/{id}/transcript/{termid}/{courseid}", completion).Methods("PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/classes", classes).Methods("GET", "POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/classes/{classid}", class).Methods("GET", "PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/classes/{classid}/attendance", attendance).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/courses/{courseid}/attendance", courseAttendance).Methods("GET")
+	router.HandleFunc("/api/v1/learners/{id}/attendance", learnerAttendance).Methods("GET")
+	router.HandleFunc("/api/v1/attendance/alerts", attendanceAlerts).Methods("GET")
+	router.HandleFunc("/api/v1/courses/{courseid}/status", courseStatus).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/courses/{courseid}/review", review).Methods("POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/rename", rename).Methods("POST")
+	router.HandleFunc("/api/v1/departments", alldepartments).Methods("GET")
+	router.HandleFunc("/api/v1/departments/{departmentid}", department).Methods("GET", "PUT", "POST", "DELETE")
+	router.HandleFunc("/api/v1/rules", validationRules).Methods("GET")
+	router.HandleFunc("/api/v1/tags", alltags).Methods("GET")
+	router.HandleFunc("/api/v1/tags/{tag}", tag).Methods("PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/tags", courseTags).Methods("GET", "PUT")
+	router.HandleFunc("/api/v1/courses/{courseid}/tags/{tag}", courseTag).Methods("PUT", "DELETE")
+	router.HandleFunc("/api/v1/courses/{courseid}/attachments", attachments).Methods("GET", "POST")
+	router.HandleFunc("/api/v1/courses/{courseid}/attachments/{attachmentid}", attachment).Methods("GET", "DELETE")
+}
+
+// initDB initialises the database
+func initDB() {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("database initialisation failed", "error", err)
+		}
+	}()
+
+	config := getDBConfig()
+
+	// Get database connection string
+	connectionString := database.GetConnectionString(config)
+
+	// Connect to database
+	err := database.Connect(connectionString)
+	if err != nil {
+		panic(fmt.Errorf("error connecting to database: %w", err))
+	}
+
+	// Test connection to database
+	err = database.DB.Ping()
+	if err != nil {
+		panic(fmt.Errorf("error pinging to database: %w", err))
+	} else {
+		slog.Info("ping to database success")
+	}
+
+	// Expose the connection pool statistics of database.DB
+	prometheus.MustRegister(collectors.NewDBStatsCollector(database.DB, config.DB))
+
+	// Apply any outstanding schema migrations
+	err = database.Migrate(context.Background())
+	if err != nil {
+		panic(fmt.Errorf("error applying database migrations: %w", err))
+	}
+
+	// Give the courses added before course IDs were canonicalised their canonical ID
+	canonicaliseCourseIDs(context.Background())
+}
+
+// loadEnv loads the setup.env file from the same directory into the environment.
+func loadEnv() {
+	err := godotenv.Load("setup.env")
+	if err != nil {
+		slog.Error("error loading .env file", "error", err)
+		os.Exit(1)
+	}
+}
+
+// getDBConfig retrieves the database configurations and returns a struct.
+func getDBConfig() database.Config {
+	// Get env variables for database configuration
+	serverName := os.Getenv("SERVER_NAME")
+	dbName := os.Getenv("DB_NAME")
+	dbUserName := os.Getenv("DB_USERNAME")
+	dbPassword := os.Getenv("DB_PASSWORD")
+
+	config :=
+		database.Config{
+			ServerName: serverName,
+			User:       dbUserName,
+			Password:   dbPassword,
+			DB:         dbName,
+		}
+
+	return config
+}
@@ -0,0 +1,335 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// sessionDays are the allowed days of a session.
+var sessionDays = map[string]time.Weekday{
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+	"Sun": time.Sunday,
+}
+
+// termWeeks is the number of weeks a calendar covers when the dates of a term are unknown
+// and no end date is given.
+const termWeeks = 15
+
+// allsessions is the handler function to retrieve the sessions of all courses, filtered by
+// ?term=, ?room=, ?instructor= and ?student= to build a timetable.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func allsessions(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	v := r.URL.Query()
+	filter := database.SessionFilter{
+		Term:       v.Get("term"),
+		Room:       v.Get("room"),
+		Instructor: v.Get("instructor"),
+		Student:    v.Get("student"),
+	}
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(database.GetSessions(r.Context(), filter))
+}
+
+// sessions is the handler function to retrieve the sessions of a course with GET
+// and add a session with POST.
+func sessions(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving sessions
+		filter := database.SessionFilter{CourseID: params["courseid"], Term: r.URL.Query().Get("term")}
+		json.NewEncoder(w).Encode(database.GetSessions(r.Context(), filter))
+	case "POST": // POST is for creating new session
+		addSession(params, w, r)
+	}
+}
+
+// addSession implements the POST method invoked by the client and adds a session to the
+// course. It is rejected if the course is not offered in the term, or if the room, or an
+// instructor of the course, is already booked.
+func addSession(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newSession, ok := convertSessionJSON(w, r)
+	if !ok || !checkOffered(w, r, params["courseid"], newSession.Term) {
+		return
+	}
+	newSession.CourseID = params["courseid"]
+
+	id, clashes := database.AddSession(r.Context(), newSession)
+	if len(clashes) != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Session clashes: " + strings.Join(clashes, "; ")))
+		return
+	}
+	if id == 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Session could not be added"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(database.GetSession(r.Context(), id))
+}
+
+// session is the handler function for the GET, PUT and DELETE operations on a session of a course.
+func session(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	// The session must belong to the course in the path
+	sessionID, _ := strconv.ParseInt(params["sessionid"], 10, 64)
+	current := database.GetSession(r.Context(), sessionID)
+	if s, ok := current[params["sessionid"]]; !ok || s.CourseID != params["courseid"] {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No session found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving session
+		json.NewEncoder(w).Encode(current)
+	case "PUT": //---PUT is for updating session
+		newSession, ok := convertSessionJSON(w, r)
+		if !ok || !checkOffered(w, r, params["courseid"], newSession.Term) {
+			return
+		}
+		newSession.CourseID = params["courseid"]
+
+		updated, clashes := database.UpdateSession(r.Context(), sessionID, newSession)
+		if len(clashes) != 0 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Session clashes: " + strings.Join(clashes, "; ")))
+		} else if !updated {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("500 - Session could not be updated"))
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Session updated"))
+		}
+	case "DELETE": // DELETE is for deleting session
+		database.DeleteSession(r.Context(), sessionID)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Session deleted"))
+	}
+}
+
+// courseCalendar is the handler function to export the sessions of a course as an
+// iCalendar document.
+func courseCalendar(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	filter := database.SessionFilter{CourseID: params["courseid"], Term: r.URL.Query().Get("term")}
+	calendar(w, r, params["courseid"], filter)
+}
+
+// learnerCalendar is the handler function to export the sessions of the courses a student
+// is enrolled in as an iCalendar document.
+func learnerCalendar(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetStudent(r.Context(), params["id"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	filter := database.SessionFilter{Student: params["id"], Term: r.URL.Query().Get("term")}
+	calendar(w, r, params["id"], filter)
+}
+
+// calendar writes the sessions matching the filter as weekly events of an iCalendar document.
+// The events recur from the start to the end of the term of each session, limited to the
+// dates in ?from= and ?until= if given. Sessions of terms without dates recur from ?from=,
+// or today, for termWeeks weeks.
+func calendar(w http.ResponseWriter, r *http.Request, name string, filter database.SessionFilter) {
+	from, until, err := calendarDates(r)
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return
+	}
+
+	courses := database.GetAllCourses(r.Context(), database.CourseFilter{})
+	terms := database.GetAllTerms(r.Context())
+
+	var events []calendarEvent
+	for id, s := range database.GetSessions(r.Context(), filter) {
+		first, last := termDates(terms[s.Term], from, until)
+		if last.Before(first) {
+			continue // the term lies outside the dates asked for
+		}
+
+		start, end := firstOccurrence(first, s)
+		if last.Before(start) {
+			continue
+		}
+		events = append(events, calendarEvent{
+			UID:         "session-" + id + "@goschool",
+			Summary:     s.CourseID + " " + courses[s.CourseID].Title,
+			Location:    s.Room,
+			Description: "Term " + s.Term,
+			Start:       start,
+			End:         end,
+			Until:       last,
+		})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	writeCalendar(w, name, events)
+}
+
+// calendarDates returns the dates in ?from= and ?until= of the request. A date which is
+// not given is returned as the zero time.
+func calendarDates(r *http.Request) (time.Time, time.Time, error) {
+	v := r.URL.Query()
+
+	var from, until time.Time
+	if s := v.Get("from"); s != "" {
+		var err error
+		if from, err = time.Parse("2006-01-02", s); err != nil {
+			return from, until, errors.New("Please supply the from date in the format YYYY-MM-DD")
+		}
+	}
+
+	if s := v.Get("until"); s != "" {
+		var err error
+		if until, err = time.Parse("2006-01-02", s); err != nil || until.Before(from) {
+			return from, until, errors.New("Please supply an until date on or after the from date in the format YYYY-MM-DD")
+		}
+	}
+	return from, until, nil
+}
+
+// termDates returns the first and last date of the term, limited to the dates from and
+// until if they are not zero. Without term dates it defaults to from, or today, and
+// termWeeks weeks later.
+func termDates(term database.TermInfo, from time.Time, until time.Time) (time.Time, time.Time) {
+	first, errStart := time.Parse("2006-01-02", term.StartDate)
+	last, errEnd := time.Parse("2006-01-02", term.EndDate)
+	if errStart != nil || errEnd != nil {
+		first = from
+		if first.IsZero() {
+			y, m, d := time.Now().Date()
+			first = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		}
+		last = first.AddDate(0, 0, 7*termWeeks-1)
+	}
+
+	if !from.IsZero() && from.After(first) {
+		first = from
+	}
+	if !until.IsZero() && until.Before(last) {
+		last = until
+	}
+	return first, last
+}
+
+// firstOccurrence returns the start and end of the first occurrence of the weekly
+// session on or after the date from.
+func firstOccurrence(from time.Time, s database.SessionInfo) (time.Time, time.Time) {
+	day := from.AddDate(0, 0, (int(sessionDays[s.Day])-int(from.Weekday())+7)%7)
+
+	start, _ := time.Parse("15:04", s.StartTime)
+	end, _ := time.Parse("15:04", s.EndTime)
+
+	y, m, d := day.Date()
+	return time.Date(y, m, d, start.Hour(), start.Minute(), 0, 0, time.UTC),
+		time.Date(y, m, d, end.Hour(), end.Minute(), 0, 0, time.UTC)
+}
+
+// convertSessionJSON converts the client JSON to a session and validates it.
+// If the session is not valid a 422 response is written and false is returned.
+func convertSessionJSON(w http.ResponseWriter, r *http.Request) (database.SessionInfo, bool) {
+	var newSession database.SessionInfo
+
+	if !decodeJSON(w, r, &newSession) {
+		return newSession, false
+	}
+
+	if err := validateSession(newSession); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newSession, false
+	}
+	return newSession, true
+}
+
+// validateSession checks that the session details are valid. Returns error type.
+func validateSession(session database.SessionInfo) error {
+	if session.Term == "" || len(session.Term) > 20 {
+		return errors.New("Please supply a term of up to 20 characters")
+	}
+	if _, ok := sessionDays[session.Day]; !ok {
+		return errors.New("Day must be one of Mon, Tue, Wed, Thu, Fri, Sat or Sun")
+	}
+	start, err := time.Parse("15:04", session.StartTime)
+	if err != nil {
+		return errors.New("Please supply the start time in the format HH:MM")
+	}
+	end, err := time.Parse("15:04", session.EndTime)
+	if err != nil || !end.After(start) {
+		return errors.New("Please supply an end time after the start time in the format HH:MM")
+	}
+	if session.Room == "" || len(session.Room) > 45 {
+		return errors.New("Please supply a room of up to 45 characters")
+	}
+	return nil
+}
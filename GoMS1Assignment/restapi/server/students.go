@@ -0,0 +1,181 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// studentStatuses are the allowed values of a student's status.
+var studentStatuses = map[string]bool{
+	"active":    true,
+	"suspended": true,
+	"graduated": true,
+	"withdrawn": true,
+}
+
+// allstudents is the handler function to retrieve all students.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func allstudents(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	// Get all students from the database
+	students := database.GetAllStudents(r.Context())
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(students)
+}
+
+// student is the handler function for CRUD operations on students sent by the client.
+// The operations for GET, POST, PUT and DELETE will be determined by switch
+// and its respective functions will be called.
+func student(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving student
+		getStudent(params, w, r)
+	case "POST": // POST is for creating new student
+		addStudent(params, w, r)
+	case "PUT": //---PUT is for updating student
+		updateStudent(params, w, r)
+	case "DELETE": // DELETE is for deleting student
+		deleteStudent(params, w, r)
+	}
+}
+
+// getStudent implements the GET method invoked by the client and
+// retrieves the student detail with the student id given.
+func getStudent(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Get student from the database
+	students := database.GetStudent(r.Context(), params["studentid"])
+
+	// Student exists
+	if len(students) != 0 {
+		// convert the map object to JSON, and pass it back to the client
+		json.NewEncoder(w).Encode(students)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+	}
+}
+
+// addStudent implements the POST method invoked by the client and
+// adds a student with the student id and details given.
+func addStudent(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newStudent, ok := convertStudentJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if student exists
+	students := database.GetStudent(r.Context(), params["studentid"])
+
+	// Student does not exist
+	if len(students) == 0 {
+		// Add student information into the database
+		database.AddStudent(r.Context(), params["studentid"], newStudent)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("201 - Student added: " + params["studentid"]))
+	} else {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Duplicate student ID"))
+	}
+}
+
+// updateStudent implements the PUT method invoked by the client and
+// updates the details of a student with the student id given.
+func updateStudent(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newStudent, ok := convertStudentJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if student exists
+	students := database.GetStudent(r.Context(), params["studentid"])
+
+	// Student exists
+	if len(students) != 0 {
+		// Update student in the database
+		database.UpdateStudent(r.Context(), params["studentid"], newStudent)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Student updated"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+	}
+}
+
+// deleteStudent implements the DELETE method invoked by the client and
+// deletes a student with the student id given.
+func deleteStudent(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check if student exists
+	students := database.GetStudent(r.Context(), params["studentid"])
+
+	// Student exists
+	if len(students) != 0 {
+		// Delete student from the database
+		database.DeleteStudent(r.Context(), params["studentid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Student deleted"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+	}
+}
+
+// convertStudentJSON converts the client JSON to a student and validates it.
+// If the student is not valid a 422 response is written and false is returned.
+func convertStudentJSON(w http.ResponseWriter, r *http.Request) (database.StudentInfo, bool) {
+	var newStudent database.StudentInfo
+
+	if !decodeJSON(w, r, &newStudent) {
+		return newStudent, false
+	}
+
+	if newStudent.Status == "" {
+		newStudent.Status = "active"
+	}
+
+	if err := validateStudent(newStudent); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newStudent, false
+	}
+	return newStudent, true
+}
+
+// validateStudent checks that the student details are valid. Returns error type.
+func validateStudent(student database.StudentInfo) error {
+	if student.Name == "" || len(student.Name) > 100 {
+		return errors.New("Please supply a student name of up to 100 characters")
+	}
+	if _, err := mail.ParseAddress(student.Email); err != nil || len(student.Email) > 254 {
+		return errors.New("Please supply a valid email address")
+	}
+	dob, err := time.Parse("2006-01-02", student.DateOfBirth)
+	if err != nil || dob.After(time.Now()) {
+		return errors.New("Please supply a date of birth in the format YYYY-MM-DD")
+	}
+	if !studentStatuses[student.Status] {
+		return errors.New("Status must be one of active, suspended, graduated or withdrawn")
+	}
+	return nil
+}
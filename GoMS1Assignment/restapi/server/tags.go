@@ -0,0 +1,204 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// tagInfo struct for the json of a tag renamed with PUT
+type tagInfo struct {
+	Name string `json:"Name"`
+}
+
+// alltags is the handler function to retrieve the number of courses with each tag, keyed by tag.
+func alltags(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(database.GetAllTags(r.Context()))
+}
+
+// tag is the handler function to rename a tag on every course with PUT, merging it into the
+// new tag on courses which already have it, and to remove a tag from every course with DELETE.
+func tag(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+	current := normaliseTag(params["tag"])
+
+	if _, ok := database.GetAllTags(r.Context())[current]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No tag found"))
+		return
+	}
+
+	switch r.Method {
+	case "PUT": // PUT is for renaming the tag
+		var newTag tagInfo
+
+		if !decodeJSON(w, r, &newTag) {
+			return
+		}
+
+		newTag.Name = normaliseTag(newTag.Name)
+		if err := validateTag(newTag.Name); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+
+		database.RenameTag(r.Context(), current, newTag.Name)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Tag renamed: " + newTag.Name))
+	case "DELETE": // DELETE is for removing the tag
+		database.DeleteTag(r.Context(), current)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Tag deleted"))
+	}
+}
+
+// courseTags is the handler function to retrieve the tags of a course with GET and replace
+// them with PUT.
+func courseTags(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	course, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the tags
+		tags := course.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		json.NewEncoder(w).Encode(tags)
+	case "PUT": // PUT is for replacing the tags
+		var newTags []string
+
+		if !decodeJSON(w, r, &newTags) {
+			return
+		}
+
+		newTags, err := normaliseTags(newTags)
+		if err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+
+		database.SetCourseTags(r.Context(), params["courseid"], newTags)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Tags updated"))
+	}
+}
+
+// courseTag is the handler function to tag a course with PUT and remove a tag from the
+// course with DELETE.
+func courseTag(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+	newTag := normaliseTag(params["tag"])
+
+	course, ok := database.GetCourse(r.Context(), params["courseid"])[params["courseid"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	switch r.Method {
+	case "PUT": // PUT is for tagging the course
+		if err := validateTag(newTag); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+		if len(course.Tags) >= rules.MaxTags {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Course already has the maximum of " + strconv.Itoa(rules.MaxTags) + " tags"))
+			return
+		}
+
+		if database.AddCourseTag(r.Context(), params["courseid"], newTag) {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("201 - Tag added: " + newTag))
+		} else {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Course already tagged: " + newTag))
+		}
+	case "DELETE": // DELETE is for removing the tag
+		if !database.DeleteCourseTag(r.Context(), params["courseid"], newTag) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No tag found"))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Tag removed"))
+	}
+}
+
+// normaliseTag returns the tag in lower case with surrounding spaces removed and inner
+// spaces collapsed, so that tags typed differently match.
+func normaliseTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+// normaliseTags normalises and validates the tags, removing duplicates. Returns error type.
+func normaliseTags(tags []string) ([]string, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = normaliseTag(tag)
+		if err := validateTag(tag); err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+	if len(unique) > rules.MaxTags {
+		return nil, errors.New("Please supply up to " + strconv.Itoa(rules.MaxTags) + " tags")
+	}
+	return unique, nil
+}
+
+// validateTag checks that a normalised tag meets the rules. Returns error type.
+func validateTag(tag string) error {
+	if errs := rules.ValidateTag(tag); len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// offeringInfo struct for the json
+type offeringInfo struct {
+	Capacity *int `json:"Capacity"` // defaults to the capacity of the course; 0 is unlimited
+}
+
+// allterms is the handler function to retrieve all terms.
+// It converts the map object retrieved into JSON and passes it back to the client.
+func allterms(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+	// Get all terms from the database
+	terms := database.GetAllTerms(r.Context())
+
+	// convert the map object to JSON, and pass it back to the client
+	json.NewEncoder(w).Encode(terms)
+}
+
+// term is the handler function for CRUD operations on terms sent by the client.
+// The operations for GET, POST, PUT and DELETE will be determined by switch
+// and its respective functions will be called.
+func term(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	switch r.Method {
+	case "GET": // GET is for retrieving term
+		getTerm(params, w, r)
+	case "POST": // POST is for creating new term
+		addTerm(params, w, r)
+	case "PUT": //---PUT is for updating term
+		updateTerm(params, w, r)
+	case "DELETE": // DELETE is for deleting term
+		deleteTerm(params, w, r)
+	}
+}
+
+// getTerm implements the GET method invoked by the client and
+// retrieves the term with the term id given.
+func getTerm(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Get term from the database
+	terms := database.GetTerm(r.Context(), params["termid"])
+
+	// Term exists
+	if len(terms) != 0 {
+		// convert the map object to JSON, and pass it back to the client
+		json.NewEncoder(w).Encode(terms)
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No term found"))
+	}
+}
+
+// addTerm implements the POST method invoked by the client and
+// adds a term with the term id and details given.
+func addTerm(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	if len(params["termid"]) > 20 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - Term ID cannot be greater than 20 characters"))
+		return
+	}
+
+	newTerm, ok := convertTermJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if term exists
+	terms := database.GetTerm(r.Context(), params["termid"])
+
+	// Term does not exist
+	if len(terms) == 0 {
+		// Add term information into the database
+		database.AddTerm(r.Context(), params["termid"], newTerm)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("201 - Term added: " + params["termid"]))
+	} else {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Duplicate term ID"))
+	}
+}
+
+// updateTerm implements the PUT method invoked by the client and
+// updates the details of a term with the term id given.
+func updateTerm(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	newTerm, ok := convertTermJSON(w, r)
+	if !ok {
+		return
+	}
+
+	// Check if term exists
+	terms := database.GetTerm(r.Context(), params["termid"])
+
+	// Term exists
+	if len(terms) != 0 {
+		// Update term in the database
+		database.UpdateTerm(r.Context(), params["termid"], newTerm)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Term updated"))
+	} else {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No term found"))
+	}
+}
+
+// deleteTerm implements the DELETE method invoked by the client and deletes a term with
+// the term id given. A term with courses offered in it cannot be deleted.
+func deleteTerm(params map[string]string, w http.ResponseWriter, r *http.Request) {
+	// Check if term exists
+	terms := database.GetTerm(r.Context(), params["termid"])
+
+	// Term does not exist
+	if len(terms) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No term found"))
+		return
+	}
+
+	if offerings := database.GetTermOfferings(r.Context(), params["termid"]); len(offerings) != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Term has " + strconv.Itoa(len(offerings)) + " course offerings"))
+		return
+	}
+	if count := database.CountTermCompletions(r.Context(), params["termid"]); count != 0 {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("409 - Term has " + strconv.Itoa(count) + " course completions on transcripts"))
+		return
+	}
+
+	// Delete term from the database
+	database.DeleteTerm(r.Context(), params["termid"])
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("200 - Term deleted"))
+}
+
+// termOfferings is the handler function to retrieve the courses offered in a term.
+func termOfferings(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetTerm(r.Context(), params["termid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No term found"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(database.GetTermOfferings(r.Context(), params["termid"]))
+}
+
+// courseOfferings is the handler function to retrieve the terms a course is offered in.
+func courseOfferings(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(database.GetCourseOfferings(r.Context(), params["courseid"]))
+}
+
+// offering is the handler function for the offering of a course in a term. GET retrieves
+// the offering, PUT offers the course in the term or changes the capacity of the offering,
+// and DELETE withdraws the offering.
+func offering(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetTerm(r.Context(), params["termid"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No term found"))
+		return
+	}
+	courses := database.GetCourse(r.Context(), params["courseid"])
+	if len(courses) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No course found"))
+		return
+	}
+
+	offerings := database.GetCourseOfferings(r.Context(), params["courseid"])
+	current, offered := offerings[params["termid"]]
+
+	switch r.Method {
+	case "GET": // GET is for retrieving the offering
+		if !offered {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - Course is not offered in term " + params["termid"]))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]database.OfferingInfo{params["termid"]: current})
+	case "PUT": // PUT is for offering the course in the term
+		var newOffering offeringInfo
+
+		// The body is optional
+		if r.ContentLength != 0 && !decodeJSON(w, r, &newOffering) {
+			return
+		}
+		if newOffering.Capacity != nil && *newOffering.Capacity < 0 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - Please supply a Capacity of 0 (unlimited) or more in JSON format"))
+			return
+		}
+
+		capacity := courses[params["courseid"]].Capacity
+		if newOffering.Capacity != nil {
+			capacity = *newOffering.Capacity
+		} else if offered {
+			capacity = current.Capacity
+		}
+		database.SetOffering(r.Context(), params["courseid"], params["termid"], capacity)
+
+		if offered {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Offering updated"))
+		} else {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("201 - Course offered in term " + params["termid"]))
+		}
+	case "DELETE": // DELETE is for withdrawing the offering
+		if !offered {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - Course is not offered in term " + params["termid"]))
+			return
+		}
+		if current.Enrolled+current.Waitlisted != 0 {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("409 - Offering has " + strconv.Itoa(current.Enrolled+current.Waitlisted) + " students enrolled or waitlisted"))
+			return
+		}
+		database.DeleteOffering(r.Context(), params["courseid"], params["termid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Offering deleted"))
+	}
+}
+
+// checkOffered checks that the course is offered in the term given. If it is not a 404
+// response is written and false is returned.
+func checkOffered(w http.ResponseWriter, r *http.Request, courseID string, termID string) bool {
+	if _, ok := database.GetCourseOfferings(r.Context(), courseID)[termID]; !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - Course is not offered in term " + termID))
+		return false
+	}
+	return true
+}
+
+// convertTermJSON converts the client JSON to a term and validates it.
+// If the term is not valid a 422 response is written and false is returned.
+func convertTermJSON(w http.ResponseWriter, r *http.Request) (database.TermInfo, bool) {
+	var newTerm database.TermInfo
+
+	if !decodeJSON(w, r, &newTerm) {
+		return newTerm, false
+	}
+
+	if err := validateTerm(newTerm); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("422 - " + err.Error()))
+		return newTerm, false
+	}
+	return newTerm, true
+}
+
+// validateTerm checks that the term details are valid. Returns error type.
+func validateTerm(term database.TermInfo) error {
+	if term.Name == "" || len(term.Name) > 45 {
+		return errors.New("Please supply a term name of up to 45 characters")
+	}
+	start, err := time.Parse("2006-01-02", term.StartDate)
+	if err != nil {
+		return errors.New("Please supply the start date in the format YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", term.EndDate)
+	if err != nil || end.Before(start) {
+		return errors.New("Please supply an end date on or after the start date in the format YYYY-MM-DD")
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// gradePoints is the grade point of each grade counted in a GPA. A pass (P) earns credits
+// but is not counted in a GPA.
+var gradePoints = map[string]float64{
+	"A+": 4.0, "A": 4.0, "A-": 3.7,
+	"B+": 3.3, "B": 3.0, "B-": 2.7,
+	"C+": 2.3, "C": 2.0, "C-": 1.7,
+	"D+": 1.3, "D": 1.0, "F": 0,
+}
+
+// transcriptInfo struct for the json of the transcript of a student
+type transcriptInfo struct {
+	StudentID string           `json:"StudentID"`
+	Name      string           `json:"Name"`
+	Terms     []termRecordInfo `json:"Terms"`   // ordered by start date
+	Credits   float64          `json:"Credits"` // credits earned in all terms
+	GPA       float64          `json:"GPA"`     // cumulative GPA
+}
+
+// termRecordInfo struct for the json of the courses a student completed in a term
+type termRecordInfo struct {
+	TermID    string                    `json:"TermID"`
+	Name      string                    `json:"Name"`
+	StartDate string                    `json:"StartDate"`
+	EndDate   string                    `json:"EndDate"`
+	Courses   []database.CompletionInfo `json:"Courses"` // ordered by course ID
+	Credits   float64                   `json:"Credits"` // credits earned in the term
+	GPA       float64                   `json:"GPA"`     // term GPA
+}
+
+// transcript is the handler function to retrieve the transcript of a learner: the courses
+// they completed by term with grades and credits, and the term and cumulative GPA. The
+// transcript is rendered as a printable PDF with ?format=pdf.
+func transcript(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	student, ok := database.GetStudent(r.Context(), params["id"])[params["id"]]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	t := buildTranscript(params["id"], student.Name, database.GetCompletions(r.Context(), params["id"]),
+		database.GetAllTerms(r.Context()))
+
+	if r.URL.Query().Get("format") == "pdf" {
+		writeTranscriptPDF(w, r, t)
+		return
+	}
+
+	json.NewEncoder(w).Encode(t)
+}
+
+// completion is the handler function to record with PUT that a learner completed a course
+// in a term, or correct the record, and to delete the record with DELETE. Recording a
+// completion also marks an enrolment in the course for the term as completed.
+func completion(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	params := mux.Vars(r)
+
+	if len(database.GetStudent(r.Context(), params["id"])) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 - No student found"))
+		return
+	}
+
+	var exists bool
+	for _, c := range database.GetCompletions(r.Context(), params["id"]) {
+		if c.TermID == params["termid"] && c.CourseID == params["courseid"] {
+			exists = true
+		}
+	}
+
+	switch r.Method {
+	case "PUT": // PUT is for recording the completion
+		if len(database.GetTerm(r.Context(), params["termid"])) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No term found"))
+			return
+		}
+		if len(database.GetCourse(r.Context(), params["courseid"])) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No course found"))
+			return
+		}
+
+		var newCompletion database.CompletionInfo
+
+		if !decodeJSON(w, r, &newCompletion) {
+			return
+		}
+
+		if err := validateCompletion(newCompletion); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte("422 - " + err.Error()))
+			return
+		}
+		newCompletion.TermID = params["termid"]
+		newCompletion.CourseID = params["courseid"]
+
+		database.SetCompletion(r.Context(), params["id"], newCompletion)
+
+		// Complete the enrolment of the student in the term, or else outside any term
+		for _, termID := range []string{params["termid"], ""} {
+			if e, ok := database.GetEnrolment(r.Context(), params["courseid"], termID, params["id"])[params["id"]]; ok {
+				if e.Status == database.Enrolled {
+					database.SetEnrolmentStatus(r.Context(), params["courseid"], termID, params["id"], database.Completed)
+				}
+				break
+			}
+		}
+
+		if exists {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("200 - Completion updated"))
+		} else {
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("201 - Completion recorded"))
+		}
+	case "DELETE": // DELETE is for deleting the completion
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("404 - No completion found"))
+			return
+		}
+
+		database.DeleteCompletion(r.Context(), params["id"], params["termid"], params["courseid"])
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("200 - Completion deleted"))
+	}
+}
+
+// buildTranscript groups the completions, ordered by the start date of their term, into the
+// terms of the transcript and computes the credits and GPA of each term and in total.
+// Retaken courses count in the cumulative GPA once for each attempt.
+func buildTranscript(studentID string, name string, completions []database.CompletionInfo, terms map[string]database.TermInfo) transcriptInfo {
+	t := transcriptInfo{StudentID: studentID, Name: name, Terms: []termRecordInfo{}}
+
+	for _, c := range completions {
+		if n := len(t.Terms); n == 0 || t.Terms[n-1].TermID != c.TermID {
+			term := terms[c.TermID]
+			t.Terms = append(t.Terms, termRecordInfo{
+				TermID: c.TermID, Name: term.Name, StartDate: term.StartDate, EndDate: term.EndDate,
+			})
+		}
+		record := &t.Terms[len(t.Terms)-1]
+		record.Courses = append(record.Courses, c)
+
+		if earnsCredits(c.Grade) {
+			record.Credits += c.Credits
+			t.Credits += c.Credits
+		}
+	}
+
+	for i := range t.Terms {
+		t.Terms[i].GPA = gpa(t.Terms[i].Courses)
+	}
+	t.GPA = gpa(completions)
+	return t
+}
+
+// earnsCredits reports whether a completion with the grade earns its credits: a pass, or a
+// grade counted in a GPA other than F.
+func earnsCredits(grade string) bool {
+	_, graded := gradePoints[grade]
+	return grade == "P" || graded && grade != "F"
+}
+
+// gpa returns the credit-weighted average grade point of the completions with grades counted
+// in a GPA, rounded to 2 decimal places, or 0 if there are none.
+func gpa(completions []database.CompletionInfo) float64 {
+	var points, graded float64
+	for _, c := range completions {
+		if p, ok := gradePoints[c.Grade]; ok {
+			points += p * c.Credits
+			graded += c.Credits
+		}
+	}
+	if graded == 0 {
+		return 0
+	}
+	return math.Round(points/graded*100) / 100
+}
+
+// writeTranscriptPDF writes the transcript as a printable A4 PDF document.
+func writeTranscriptPDF(w http.ResponseWriter, r *http.Request, t transcriptInfo) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Transcript of "+t.Name, true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "GoSchool Academic Transcript", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(0, 6, tr(t.Name+" ("+t.StudentID+")"), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	widths := []float64{30, 100, 25, 25}
+	for _, term := range t.Terms {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, tr(fmt.Sprintf("%s (%s to %s)", term.Name, term.StartDate, term.EndDate)), "", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 10)
+		for i, heading := range []string{"Course", "Title", "Grade", "Credits"} {
+			pdf.CellFormat(widths[i], 7, heading, "B", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 10)
+		for _, c := range term.Courses {
+			row := []string{c.CourseID, c.Title, c.Grade, strconv.FormatFloat(c.Credits, 'f', 1, 64)}
+			for i, cell := range row {
+				pdf.CellFormat(widths[i], 6, tr(cell), "", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 7, fmt.Sprintf("Term credits: %.1f    Term GPA: %.2f", term.Credits, term.GPA), "T", 1, "R", false, 0, "")
+		pdf.Ln(3)
+	}
+
+	if len(t.Terms) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 8, "No completed courses", "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 8, fmt.Sprintf("Total credits: %.1f    Cumulative GPA: %.2f", t.Credits, t.GPA), "", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		logger(r.Context()).Error("error rendering transcript pdf", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("500 - Transcript could not be rendered"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": t.StudentID + "-transcript.pdf"}))
+	w.Write(buf.Bytes())
+}
+
+// validateCompletion checks that the grade and credits of the completion are valid. Returns error type.
+func validateCompletion(completion database.CompletionInfo) error {
+	if _, ok := gradePoints[completion.Grade]; !ok && completion.Grade != "P" {
+		return errors.New("Please supply a grade from A+ to F, or P for a pass")
+	}
+	if completion.Credits <= 0 || completion.Credits > 999.9 {
+		return errors.New("Please supply credits above 0 and up to 999.9")
+	}
+	return nil
+}
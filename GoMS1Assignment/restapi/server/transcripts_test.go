@@ -0,0 +1,128 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"bytes"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGPA(t *testing.T) {
+	tests := []struct {
+		name        string
+		completions []database.CompletionInfo
+		want        float64
+	}{
+		{"none", nil, 0},
+		{"single grade", []database.CompletionInfo{{Grade: "B+", Credits: 4}}, 3.3},
+		{"weighted by credits", []database.CompletionInfo{
+			{Grade: "A", Credits: 6}, {Grade: "C", Credits: 2},
+		}, 3.5},
+		{"fail counts as 0", []database.CompletionInfo{
+			{Grade: "A", Credits: 3}, {Grade: "F", Credits: 3},
+		}, 2},
+		{"repeated course counts each attempt", []database.CompletionInfo{
+			{CourseID: "GO101", Grade: "F", Credits: 4}, {CourseID: "GO101", Grade: "B", Credits: 4},
+		}, 1.5},
+		{"pass is not counted", []database.CompletionInfo{
+			{Grade: "P", Credits: 10}, {Grade: "A-", Credits: 2},
+		}, 3.7},
+		{"withdrawn and incomplete are not counted", []database.CompletionInfo{
+			{Grade: "W", Credits: 4}, {Grade: "I", Credits: 4}, {Grade: "C+", Credits: 4},
+		}, 2.3},
+		{"only ungraded", []database.CompletionInfo{{Grade: "P", Credits: 4}, {Grade: "W", Credits: 4}}, 0},
+		{"zero credits", []database.CompletionInfo{{Grade: "A", Credits: 0}}, 0},
+		{"rounded to 2 decimal places", []database.CompletionInfo{
+			{Grade: "A", Credits: 1}, {Grade: "B", Credits: 1}, {Grade: "B", Credits: 1},
+		}, 3.33},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gpa(tt.completions); got != tt.want {
+				t.Errorf("gpa = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTranscript(t *testing.T) {
+	terms := map[string]database.TermInfo{
+		"2030S1": {Name: "Semester 1", StartDate: "2030-01-06", EndDate: "2030-04-30"},
+		"2030S2": {Name: "Semester 2", StartDate: "2030-07-01", EndDate: "2030-10-31"},
+		"2031S1": {Name: "Semester 1 2031", StartDate: "2031-01-05", EndDate: "2031-04-30"},
+	}
+	// Completions are ordered by the start date of their term, then course ID
+	completions := []database.CompletionInfo{
+		{TermID: "2030S1", CourseID: "DB101", Grade: "A", Credits: 4},
+		{TermID: "2030S1", CourseID: "GO101", Grade: "F", Credits: 4},
+		{TermID: "2030S2", CourseID: "GO101", Grade: "B", Credits: 4},
+		{TermID: "2030S2", CourseID: "GO150", Grade: "P", Credits: 2},
+		{TermID: "2031S1", CourseID: "GO201", Grade: "W", Credits: 4},
+	}
+
+	got := buildTranscript("S1", "Ann", completions, terms)
+
+	want := transcriptInfo{StudentID: "S1", Name: "Ann", Credits: 10, GPA: 2.33, Terms: []termRecordInfo{
+		{TermID: "2030S1", Name: "Semester 1", StartDate: "2030-01-06", EndDate: "2030-04-30",
+			Courses: completions[0:2], Credits: 4, GPA: 2},
+		{TermID: "2030S2", Name: "Semester 2", StartDate: "2030-07-01", EndDate: "2030-10-31",
+			Courses: completions[2:4], Credits: 6, GPA: 3},
+		{TermID: "2031S1", Name: "Semester 1 2031", StartDate: "2031-01-05", EndDate: "2031-04-30",
+			Courses: completions[4:5], Credits: 0, GPA: 0},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildTranscript =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestBuildTranscriptEmpty(t *testing.T) {
+	got := buildTranscript("S1", "Ann", nil, map[string]database.TermInfo{"2030S1": {Name: "Semester 1"}})
+
+	// Terms with no completions are left out, and the terms are [] rather than null in the json
+	if got.Terms == nil || len(got.Terms) != 0 || got.Credits != 0 || got.GPA != 0 {
+		t.Errorf("buildTranscript with no completions = %+v", got)
+	}
+}
+
+func TestValidateCompletion(t *testing.T) {
+	tests := []struct {
+		name       string
+		completion database.CompletionInfo
+		valid      bool
+	}{
+		{"letter grade", database.CompletionInfo{Grade: "A-", Credits: 4}, true},
+		{"fail", database.CompletionInfo{Grade: "F", Credits: 4}, true},
+		{"pass", database.CompletionInfo{Grade: "P", Credits: 0.5}, true},
+		{"most credits", database.CompletionInfo{Grade: "B", Credits: 999.9}, true},
+		{"withdrawn", database.CompletionInfo{Grade: "W", Credits: 4}, false},
+		{"incomplete", database.CompletionInfo{Grade: "I", Credits: 4}, false},
+		{"no grade", database.CompletionInfo{Credits: 4}, false},
+		{"lower-case grade", database.CompletionInfo{Grade: "a", Credits: 4}, false},
+		{"zero credits", database.CompletionInfo{Grade: "A", Credits: 0}, false},
+		{"negative credits", database.CompletionInfo{Grade: "A", Credits: -1}, false},
+		{"too many credits", database.CompletionInfo{Grade: "A", Credits: 1000}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCompletion(tt.completion); (err == nil) != tt.valid {
+				t.Errorf("validateCompletion(%+v) = %v, want valid %v", tt.completion, err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestWriteTranscriptPDF(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeTranscriptPDF(rec, httptest.NewRequest("GET", "/", nil), transcriptInfo{StudentID: `S "1"`, Name: "Ann"})
+
+	if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Content-Type = %q, want application/pdf", got)
+	}
+	if got, want := rec.Header().Get("Content-Disposition"), `inline; filename="S \"1\"-transcript.pdf"`; got != want {
+		t.Errorf("Content-Disposition = %q, want %q", got, want)
+	}
+	if !bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")) {
+		t.Error("body is not a PDF document")
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"GoMS1Assignment/restapi/database"
+	"GoMS1Assignment/validation"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+)
+
+// rules are the rules the details of courses must meet, served to the client by validationRules.
+var rules = validation.DefaultRules()
+
+// fieldErrors struct for the json of the violations of the rules, keyed by field
+type fieldErrors struct {
+	Errors validation.Errors `json:"Errors"`
+}
+
+// initRules configures the rules from setup.env, keeping the default of any rule not set.
+func initRules() {
+	var err error
+	rules, err = validation.FromEnv()
+	if err != nil {
+		slog.Error("error configuring validation rules", "error", err)
+		os.Exit(1)
+	}
+}
+
+// validationRules is the handler function to retrieve the rules the details of courses must
+// meet, so the client checks courses against the rules the REST API is configured with.
+func validationRules(w http.ResponseWriter, r *http.Request) {
+	if !validKey(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("401 - Invalid key"))
+		return
+	}
+
+	json.NewEncoder(w).Encode(rules)
+}
+
+// courseIDVars are the route variables which hold course IDs.
+var courseIDVars = []string{"courseid", "prerequisiteid"}
+
+// canonicalCourseIDs is the middleware which replaces the course IDs in the route variables
+// with their canonical form, so that handlers store and look up courses by canonical ID only.
+// The courses stored before are given their canonical ID by canonicaliseCourseIDs.
+func canonicalCourseIDs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// mux.Vars returns the variables of the matched route itself
+		vars := mux.Vars(r)
+		for _, name := range courseIDVars {
+			if id, ok := vars[name]; ok {
+				vars[name] = validation.CanonicalCourseID(id)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// canonicaliseCourseIDs gives the courses added before course IDs were canonicalised their
+// canonical ID on startup, renaming those whose ID is not canonical, so that the handlers
+// find them by the canonical ID in the route. The courses whose IDs have the same canonical
+// form as another, such as go101 and GO101, are left to be resolved by hand, as reported by
+// the courseids command.
+func canonicaliseCourseIDs(ctx context.Context) {
+	var renames []string
+	for _, courseID := range database.GetLegacyCourseIDs(ctx) {
+		// The IDs already canonical are claimed first, so they keep their ID in a collision
+		if validation.CanonicalCourseID(courseID) != courseID {
+			renames = append(renames, courseID)
+		} else if err := database.SetCanonicalID(ctx, courseID); errors.Is(err, database.ErrDuplicateCourseID) {
+			slog.Warn("course ID collides with another course", "courseid", courseID)
+		}
+	}
+
+	for _, courseID := range renames {
+		canonical := validation.CanonicalCourseID(courseID)
+		err := database.RenameCourse(ctx, courseID, canonical, false, "canonical course IDs")
+		if errors.Is(err, database.ErrDuplicateCourseID) {
+			slog.Warn("course ID collides with another course", "courseid", courseID, "canonical", canonical)
+		} else if err == nil {
+			slog.Info("course renamed to its canonical ID", "courseid", courseID, "canonical", canonical)
+		}
+	}
+}
+
+// writeFieldErrors writes the 422 response listing the violations of the rules by field.
+func writeFieldErrors(w http.ResponseWriter, errs validation.Errors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(fieldErrors{errs})
+}
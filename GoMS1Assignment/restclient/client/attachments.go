@@ -0,0 +1,106 @@
+package client
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// maxAttachmentSize is the maximum size in bytes of a file uploaded through the course page,
+// the default maximum of the REST API.
+const maxAttachmentSize = 8 << 20
+
+// attachment is the handler function to download an attachment of a course with GET, and to
+// upload or delete an attachment on the course page with POST, since the browser cannot
+// call the REST API without its access key.
+// ListAttachments and DownloadAttachment, UploadAttachment or DeleteAttachment of the REST API
+// are invoked.
+func attachment(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		downloadAttachment(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	// Allow for the boundaries and headers of the multipart form around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+64<<10)
+	courseID := r.FormValue("courseid")
+
+	var msg string
+	var err error
+	if r.FormValue("action") == "delete" {
+		attachmentID, _ := strconv.Atoi(r.FormValue("attachmentid"))
+		err = api.DeleteAttachment(r.Context(), courseID, attachmentID)
+		msg = "attachmentdeleted"
+	} else if file, header, formErr := r.FormFile("file"); formErr != nil {
+		msg = "invalidattachment"
+	} else {
+		defer file.Close()
+		_, err = api.UploadAttachment(r.Context(), courseID, header.Filename, file)
+		msg = "attachmentadded"
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "invalidattachment"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error updating attachment", "courseid", courseID, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// downloadAttachment sends the file of the attachment of the course given by the courseid
+// and attachmentid query parameters.
+func downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	courseID := v.Get("courseid")
+	attachmentID, _ := strconv.Atoi(v.Get("attachmentid"))
+
+	// Find the name and type of the file to send with it
+	a, err := findAttachment(r, courseID, attachmentID)
+	var file []byte
+	if err == nil {
+		file, err = api.DownloadAttachment(r.Context(), courseID, attachmentID)
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		logger(r.Context()).Error("error downloading attachment", "courseid", courseID, "error", err)
+		http.Error(w, "Error downloading attachment.", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	w.Write(file)
+}
+
+// findAttachment retrieves the attachment of the course with the attachment id given.
+// Returns an error matching ErrNotFound if there is no such attachment.
+func findAttachment(r *http.Request, courseID string, attachmentID int) (coursesapi.Attachment, error) {
+	attachments, err := api.ListAttachments(r.Context(), courseID)
+	if err != nil {
+		return coursesapi.Attachment{}, err
+	}
+	for _, a := range attachments {
+		if a.ID == attachmentID {
+			return a, nil
+		}
+	}
+	return coursesapi.Attachment{}, &coursesapi.APIError{StatusCode: http.StatusNotFound}
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+	"GoMS1Assignment/validation"
+)
+
+// attendanceStatuses are the statuses a student may be marked with on the roll-call page.
+var attendanceStatuses = []string{coursesapi.Present, coursesapi.Late, coursesapi.Absent, coursesapi.Excused}
+
+// rollcall is the handler function for the roll-call page of a course, where instructors add
+// dated classes, mark the attendance of students at them and review the attendance report
+// with its low-attendance alerts. The report is of the term of the class selected.
+// ListClasses, AddClass, DeleteClass, Attendance, MarkAttendance, ListSessions and
+// CourseAttendanceInTerm of the REST API are invoked.
+func rollcall(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	courseID := validation.CanonicalCourseID(r.FormValue("courseid"))
+	classID, _ := strconv.Atoi(r.FormValue("classid"))
+	form := coursesapi.Class{CourseID: courseID, Date: time.Now().Format("2006-01-02")}
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	var err error
+	if r.Method == http.MethodPost {
+		switch r.FormValue("action") {
+		case "addclass":
+			form.SessionID, _ = strconv.Atoi(r.FormValue("sessionid"))
+			form.Date = r.FormValue("date")
+			form.Topic = r.FormValue("topic")
+
+			var added coursesapi.Class
+			if added, err = api.AddClass(r.Context(), form); err == nil {
+				classID = added.ID
+				clientMsg = fmt.Sprintf("Class on %s added successfully.\n", added.Date)
+			}
+		case "deleteclass":
+			err = api.DeleteClass(r.Context(), courseID, classID)
+			classID = 0
+			clientMsg = "Class deleted successfully."
+		case "allpresent":
+			err = api.MarkAttendance(r.Context(), courseID, classID, nil, coursesapi.Present)
+			clientMsg = "Unmarked students marked present."
+		default:
+			marks := make(map[string]string)
+			for _, studentID := range r.Form["studentid"] {
+				if status := r.FormValue("status-" + studentID); status != "" {
+					marks[studentID] = status
+				}
+			}
+			err = api.MarkAttendance(r.Context(), courseID, classID, marks, "")
+			clientMsg = "Attendance saved successfully."
+		}
+
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+			clientMsg = ""
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Course or class not found."
+		} else if errors.Is(err, coursesapi.ErrConflict) {
+			clientMsg = ">> The course already has a class on that date in that session."
+		} else if errors.Is(err, coursesapi.ErrInvalid) {
+			clientMsg = ">> Please enter a valid date, on the day of the session if one is selected."
+		} else if err != nil {
+			logger(r.Context()).Error("error updating attendance", "courseid", courseID, "error", err)
+			clientMsg = ">> Error updating attendance."
+		}
+	}
+
+	classes, err := api.ListClasses(r.Context(), courseID) // Get the classes of the course
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if errors.Is(err, coursesapi.ErrNotFound) {
+		clientMsg = ">> Invalid Course ID"
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving classes", "courseid", courseID, "error", err)
+	}
+	valid := err == nil
+
+	// Default to the latest class
+	var selected coursesapi.Class
+	for _, c := range classes {
+		if c.ID == classID || (classID == 0 && c.Date <= time.Now().Format("2006-01-02")) {
+			selected = c
+		}
+	}
+
+	var roll []coursesapi.AttendanceMark
+	if selected.ID != 0 {
+		if roll, err = api.Attendance(r.Context(), courseID, selected.ID); err != nil && !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error retrieving attendance", "courseid", courseID, "classid", selected.ID, "error", err)
+		}
+	}
+
+	sessions, err := api.ListSessions(r.Context(), courseID)
+	if err != nil && !errors.Is(err, coursesapi.ErrUnavailable) && !errors.Is(err, coursesapi.ErrNotFound) {
+		logger(r.Context()).Error("error retrieving sessions", "courseid", courseID, "error", err)
+	}
+
+	// A class held in a weekly session is taken by the students enrolled in its term
+	var termID string
+	for _, s := range sessions {
+		if selected.SessionID != 0 && s.ID == selected.SessionID {
+			termID = s.Term
+		}
+	}
+
+	report, err := api.CourseAttendanceInTerm(r.Context(), courseID, termID, 0)
+	if err != nil && !errors.Is(err, coursesapi.ErrUnavailable) && !errors.Is(err, coursesapi.ErrNotFound) {
+		logger(r.Context()).Error("error retrieving attendance report", "courseid", courseID, "termid", termID, "error", err)
+	}
+
+	data := struct {
+		CourseID    string
+		Valid       bool
+		Classes     []coursesapi.Class
+		Selected    coursesapi.Class
+		Roll        []coursesapi.AttendanceMark
+		Statuses    []string
+		TermID      string
+		Report      []coursesapi.AttendanceReport
+		Sessions    []coursesapi.Session
+		Class       coursesapi.Class
+		ClientMsg   string
+		Unavailable bool
+	}{
+		courseID,
+		valid,
+		classes,
+		selected,
+		roll,
+		attendanceStatuses,
+		termID,
+		report,
+		sessions,
+		form,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "rollcall.gohtml", data)
+}
@@ -1,58 +1,142 @@
-/*
-Package client initialises the handler functions for the client web pages
-and implements its functions for CRUD operations.
-It is separated into 3 .go files to segregate the functionalities of the application.
-
-	client.go: Initialises the templates and handler functions, then starts the client to run
-	on the designated port.
-
-	handler.go: Implements the handler functions for displaying the web pages of the client
-	to perform the CRUD operations.
-
-	crud.go: Implements the functions for CRUD operations and invokes the REST API.
-*/
-package client
-
-import (
-	"fmt"
-	"html/template"
-	"log"
-	"net/http"
-
-	"github.com/gorilla/mux"
-)
-
-var (
-	tpl *template.Template
-)
-
-// InitClient initialises the templates for displaying the web pages of the client
-func InitClient() {
-	// Parse templates
-	tpl = template.Must(template.ParseGlob("templates/*"))
-}
-
-// StartClient initialises the handler functions then
-// listens on the designated port to start the client running.
-func StartClient() {
-	router := mux.NewRouter()
-
-	// Initialise the handlers
-	initaliseHandlers(router)
-
-	// Set the listen port
-	fmt.Println("Listening at port 5221")
-	err := http.ListenAndServeTLS(":5221", "certs//cert.pem", "certs//key.pem", router)
-	if err != nil {
-		log.Fatal("FATAL: ListenAndServeTLS - ", err)
-	}
-}
-
-// initaliseHandlers initialises the handlers for the client.
-func initaliseHandlers(router *mux.Router) {
-	router.HandleFunc("/", index)
-	router.HandleFunc("/addcourse", addcourse)
-	router.HandleFunc("/updcourse", updcourse)
-	router.HandleFunc("/delcourse", delcourse)
-	router.Handle("/favicon.ico", http.NotFoundHandler())
-}
+/*
+Package client initialises the handler functions for the client web pages
+and implements its functions for CRUD operations.
+It is separated into 20 .go files to segregate the functionalities of the application.
+
+	client.go: Initialises the templates and handler functions, then starts the client to run
+	on the designated port.
+
+	handler.go: Implements the handler functions for displaying the web pages of the client
+	to perform the CRUD operations.
+
+	students.go: Implements the handler functions for the web pages to manage students.
+
+	enrolments.go: Implements the enrolment actions on the course page.
+
+	instructors.go: Implements the web page to manage instructors, and the actions to
+	assign them to courses on the course page.
+
+	sessions.go: Implements the weekly timetable, the session actions on the course page
+	and the calendar downloads.
+
+	departments.go: Implements the web page to manage departments, and the grouping of
+	the course listing by department.
+
+	attachments.go: Implements the attachment actions on the course page and the download of
+	attached files.
+
+	markdown.go: Renders the Markdown of course descriptions as sanitised HTML.
+
+	lifecycle.go: Implements the status actions on the course page and the review queue
+	page where admins approve or reject courses pending approval.
+
+	terms.go: Implements the web page to manage terms, and the actions to offer courses
+	in them on the course page.
+
+	gradebook.go: Implements the grading page where instructors record assessments and
+	scores, and the gradebook download.
+
+	transcripts.go: Implements the transcript page of a student, where the courses they
+	completed are recorded, and the printable transcript download.
+
+	attendance.go: Implements the roll-call page where instructors add dated classes, mark
+	attendance and review low-attendance alerts.
+
+	validation.go: Fetches the validation rules of courses from the REST API.
+
+	crud.go: Creates the coursesapi client which invokes the REST API for CRUD operations.
+
+	health.go: Implements the health endpoint which checks that the REST API is reachable.
+
+	metrics.go: Implements the middleware which records request metrics exposed
+	on /metrics for Prometheus.
+
+	logging.go: Implements the structured logger, the request ID middleware and the access log.
+
+	tracing.go: Initialises OpenTelemetry tracing and the exporter chosen at startup.
+*/
+package client
+
+import (
+	"context"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+)
+
+var (
+	tpl *template.Template
+)
+
+// InitClient initialises the templates for displaying the web pages of the client
+func InitClient() {
+	// Parse templates
+	tpl = template.Must(template.ParseGlob("templates/*"))
+}
+
+// StartClient initialises the handler functions then
+// listens on the designated port to start the client running.
+func StartClient() {
+	initLogger()
+	initRules()
+
+	shutdownTracer, err := initTracer(context.Background())
+	if err != nil {
+		slog.Error("error initialising tracing", "error", err)
+		os.Exit(1)
+	}
+	defer shutdownTracer(context.Background())
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware(serviceName), requestIDMiddleware, accessLogMiddleware, metricsMiddleware)
+	countUnmatched(router)
+
+	// Initialise the handlers
+	initaliseHandlers(router)
+
+	// Set the listen port
+	slog.Info("listening", "port", 5221)
+	err = http.ListenAndServeTLS(":5221", "certs//cert.pem", "certs//key.pem", router)
+	if err != nil {
+		slog.Error("ListenAndServeTLS failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// initaliseHandlers initialises the handlers for the client.
+func initaliseHandlers(router *mux.Router) {
+	router.HandleFunc("/", index)
+	router.HandleFunc("/healthz", healthz)
+	router.Handle("/metrics", promhttp.Handler())
+	router.HandleFunc("/addcourse", addcourse)
+	router.HandleFunc("/updcourse", updcourse)
+	router.HandleFunc("/delcourse", delcourse)
+	router.HandleFunc("/renamecourse", renamecourse)
+	router.HandleFunc("/enrolment", enrolment)
+	router.HandleFunc("/instructors", instructors)
+	router.HandleFunc("/assignment", assignment)
+	router.HandleFunc("/timetable", timetable)
+	router.HandleFunc("/session", session)
+	router.HandleFunc("/calendar", calendar)
+	router.HandleFunc("/departments", departments)
+	router.HandleFunc("/coursestatus", coursestatus)
+	router.HandleFunc("/attachment", attachment)
+	router.HandleFunc("/reviews", reviews)
+	router.HandleFunc("/terms", terms)
+	router.HandleFunc("/offering", offering)
+	router.HandleFunc("/gradebook", gradebook)
+	router.HandleFunc("/gradebook.csv", gradebookCSV)
+	router.HandleFunc("/transcript", transcript)
+	router.HandleFunc("/transcript.pdf", transcriptPDF)
+	router.HandleFunc("/rollcall", rollcall)
+	router.HandleFunc("/students", students)
+	router.HandleFunc("/addstudent", addstudent)
+	router.HandleFunc("/updstudent", updstudent)
+	router.HandleFunc("/delstudent", delstudent)
+	router.Handle("/favicon.ico", http.NotFoundHandler())
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// courseGroup is the courses of a department in the department-grouped course listing.
+type courseGroup struct {
+	Department coursesapi.Department // zero for the courses of no department
+	Courses    []coursesapi.Course
+}
+
+// departments is the handler function to display all departments, add a department and
+// delete a department, with the tags in use on courses.
+// ListDepartments, AddDepartment, DeleteDepartment and ListTags of the REST API are invoked.
+func departments(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	var department coursesapi.Department
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		if r.FormValue("action") == "delete" {
+			departmentID := r.FormValue("departmentid")
+			err := api.DeleteDepartment(r.Context(), departmentID)
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s deleted successfully.\n", departmentID)
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Department not found."
+			} else if errors.Is(err, coursesapi.ErrConflict) {
+				clientMsg = ">> The department has courses, so it cannot be deleted."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error deleting department", "departmentid", departmentID, "error", err)
+				clientMsg = ">> Error deleting department."
+			}
+		} else {
+			department = coursesapi.Department{
+				ID:   r.FormValue("departmentid"),
+				Name: r.FormValue("name"),
+			}
+
+			if err := validateDepartment(department); err != nil {
+				clientMsg = err.Error()
+			} else {
+				err := api.AddDepartment(r.Context(), department)
+
+				if err == nil {
+					clientMsg = fmt.Sprintf("%s - %s added successfully.\n", department.ID, department.Name)
+					department = coursesapi.Department{}
+				} else if errors.Is(err, coursesapi.ErrConflict) {
+					clientMsg = ">> Duplicate Department ID."
+				} else if errors.Is(err, coursesapi.ErrUnavailable) {
+					unavailable = true
+				} else {
+					logger(r.Context()).Error("error adding department", "departmentid", department.ID, "error", err)
+					clientMsg = ">> Error adding department. Please contact the system administrator."
+				}
+			}
+		}
+	}
+
+	list, err := api.ListDepartments(r.Context()) // Get all departments
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving departments", "error", err)
+	}
+
+	tags, err := api.ListTags(r.Context()) // Get the tags in use
+	if err != nil && !errors.Is(err, coursesapi.ErrUnavailable) {
+		logger(r.Context()).Error("error retrieving tags", "error", err)
+	}
+
+	data := struct {
+		Departments []coursesapi.Department
+		Department  coursesapi.Department
+		Tags        []coursesapi.TagCount
+		ClientMsg   string
+		Unavailable bool
+	}{
+		list,
+		department,
+		tags,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "departments.gohtml", data)
+}
+
+// groupCourses groups the courses by department, in the order of the departments given,
+// followed by the courses of no department. Departments without courses are left out.
+func groupCourses(courses []coursesapi.Course, departments []coursesapi.Department) []courseGroup {
+	byDepartment := make(map[string][]coursesapi.Course)
+	for _, c := range courses {
+		var id string
+		if c.Department != nil {
+			id = *c.Department
+		}
+		byDepartment[id] = append(byDepartment[id], c)
+	}
+
+	var groups []courseGroup
+	for _, d := range departments {
+		if list, ok := byDepartment[d.ID]; ok {
+			groups = append(groups, courseGroup{d, list})
+			delete(byDepartment, d.ID)
+		}
+	}
+	// Courses of no department, or of a department not listed
+	var rest []coursesapi.Course
+	for _, c := range courses {
+		if c.Department == nil {
+			rest = append(rest, c)
+		} else if _, ok := byDepartment[*c.Department]; ok {
+			rest = append(rest, c)
+		}
+	}
+	if len(rest) != 0 {
+		groups = append(groups, courseGroup{Courses: rest})
+	}
+	return groups
+}
+
+// parseTags splits the comma-separated tags typed by the user, dropping blank ones.
+func parseTags(tags string) []string {
+	var list []string
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			list = append(list, tag)
+		}
+	}
+	return list
+}
+
+// validateDepartment checks that user input for the department details is valid. Returns error type.
+func validateDepartment(department coursesapi.Department) error {
+	if department.ID == "" {
+		return errors.New(">> Department ID cannot be blank")
+	} else if len(department.ID) > 20 {
+		return errors.New(">> Department ID cannot be greater than 20 characters")
+	}
+	if department.Name == "" {
+		return errors.New(">> Name cannot be blank")
+	} else if len(department.Name) > 45 {
+		return errors.New(">> Name cannot be greater than 45 characters")
+	}
+	return nil
+}
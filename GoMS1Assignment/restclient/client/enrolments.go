@@ -0,0 +1,98 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// courseEnrolments holds the enrolments shown on the course page.
+type courseEnrolments struct {
+	Enrolments []coursesapi.Enrolment
+	Students   []coursesapi.Student // students who may be enrolled
+}
+
+// getCourseEnrolments retrieves the enrolments of the course, and the active students
+// who are not enrolled or waitlisted. ListEnrolments and ListStudents of the REST API are invoked.
+func getCourseEnrolments(r *http.Request, courseID string) (courseEnrolments, error) {
+	var ce courseEnrolments
+
+	enrolments, err := api.ListEnrolments(r.Context(), courseID)
+	if err != nil {
+		return ce, err
+	}
+	ce.Enrolments = enrolments
+
+	students, err := api.ListStudents(r.Context())
+	if err != nil {
+		return ce, err
+	}
+
+	current := make(map[string]bool)
+	for _, e := range enrolments {
+		if e.Status != coursesapi.Withdrawn {
+			current[e.StudentID] = true
+		}
+	}
+	for _, s := range students {
+		if s.Status == "active" && !current[s.ID] {
+			ce.Students = append(ce.Students, s)
+		}
+	}
+	return ce, nil
+}
+
+// enrolment is the handler function for the enrol, withdraw and complete actions on the
+// course page. It redirects back to the course page with a message of the outcome.
+// EnrolInTerm and SetEnrolmentStatusInTerm of the REST API are invoked.
+func enrolment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+	studentID := r.FormValue("studentid")
+	action := r.FormValue("action")
+
+	var msg string
+	var err error
+	if action == "enrol" {
+		var e coursesapi.Enrolment
+		e, err = api.EnrolInTerm(r.Context(), courseID, r.FormValue("termid"), studentID)
+		msg = e.Status
+	} else {
+		err = api.SetEnrolmentStatusInTerm(r.Context(), courseID, r.FormValue("termid"), studentID, action)
+		msg = action
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrConflict) || errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "conflict"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error updating enrolment", "courseid", courseID,
+				"studentid", studentID, "action", action, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// parseCapacity converts user input for the capacity of a course. A blank capacity is unlimited.
+func parseCapacity(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	capacity, err := strconv.Atoi(s)
+	if err != nil || capacity < 0 {
+		return 0, errors.New(">> Capacity must be a whole number, or blank for unlimited")
+	}
+	return capacity, nil
+}
@@ -0,0 +1,258 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"GoMS1Assignment/coursesapi"
+	"GoMS1Assignment/validation"
+)
+
+// gradeRow is a row of the grading page: the scores of a student, as entered in the
+// inputs, and their final grade.
+type gradeRow struct {
+	StudentID string
+	Name      string
+	Scores    []scoreCell // in the order of the assessments
+	Percent   float64
+	Grade     string
+}
+
+// scoreCell is the input for the score of a student in an assessment.
+type scoreCell struct {
+	AssessmentID int
+	Value        string // blank if not scored
+}
+
+// gradebook is the handler function for the grading page of a course, where instructors add
+// assessments, enter the scores of students, set the letter-grade boundaries and export the
+// gradebook. The students graded are those enrolled in the term chosen, or outside any term.
+// GradebookInTerm, ListCourseOfferings, AddAssessment, DeleteAssessment, SetScoresInTerm,
+// DeleteScoreInTerm and SetGradeScale of the REST API are invoked.
+func gradebook(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	courseID := validation.CanonicalCourseID(r.FormValue("courseid"))
+	termID := r.FormValue("termid")
+	var assessment coursesapi.Assessment
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	var err error
+	if r.Method == http.MethodPost {
+		switch r.FormValue("action") {
+		case "addassessment":
+			assessment, clientMsg, err = addAssessment(r, courseID)
+		case "deleteassessment":
+			assessmentID, _ := strconv.Atoi(r.FormValue("assessmentid"))
+			err = api.DeleteAssessment(r.Context(), courseID, assessmentID)
+			clientMsg = "Assessment deleted successfully."
+		case "gradescale":
+			var scale map[string]float64
+			if scale, err = parseGradeScale(r.FormValue("gradescale")); err != nil {
+				clientMsg = err.Error()
+				err = nil
+			} else {
+				err = api.SetGradeScale(r.Context(), courseID, scale)
+				clientMsg = "Grade boundaries updated successfully."
+			}
+		default:
+			clientMsg, err = saveScores(r, courseID, termID)
+		}
+
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+			clientMsg = ""
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Course or assessment not found."
+		} else if errors.Is(err, coursesapi.ErrConflict) {
+			clientMsg = ">> Duplicate assessment name, or the weights would total more than 100."
+		} else if errors.Is(err, coursesapi.ErrInvalid) {
+			clientMsg = ">> Please enter scores between 0 and the maximum score, and valid grade boundaries."
+		} else if err != nil {
+			logger(r.Context()).Error("error updating gradebook", "courseid", courseID, "error", err)
+			clientMsg = ">> Error updating gradebook."
+		}
+	}
+
+	book, err := api.GradebookInTerm(r.Context(), courseID, termID) // Get the gradebook
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if errors.Is(err, coursesapi.ErrNotFound) {
+		clientMsg = ">> Invalid Course ID"
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving gradebook", "courseid", courseID, "termid", termID, "error", err)
+	}
+
+	var offerings []coursesapi.Offering // The terms the course may be graded in
+	if err == nil {
+		var offeringsErr error
+		offerings, offeringsErr = api.ListCourseOfferings(r.Context(), courseID)
+		if offeringsErr != nil && !errors.Is(offeringsErr, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error retrieving offerings", "courseid", courseID, "error", offeringsErr)
+		}
+	}
+
+	var rows []gradeRow
+	for _, g := range book.Grades {
+		row := gradeRow{StudentID: g.StudentID, Name: g.Name, Percent: g.Percent, Grade: g.Grade}
+		for _, a := range book.Assessments {
+			cell := scoreCell{AssessmentID: a.ID}
+			if score, ok := g.Scores[a.ID]; ok {
+				cell.Value = strconv.FormatFloat(score, 'f', -1, 64)
+			}
+			row.Scores = append(row.Scores, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	data := struct {
+		CourseID    string
+		TermID      string
+		Offerings   []coursesapi.Offering
+		Valid       bool
+		Assessments []coursesapi.Assessment
+		Assessment  coursesapi.Assessment
+		Rows        []gradeRow
+		GradeScale  string
+		ClientMsg   string
+		Unavailable bool
+	}{
+		courseID,
+		termID,
+		offerings,
+		err == nil,
+		book.Assessments,
+		assessment,
+		rows,
+		formatGradeScale(book.GradeScale),
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "gradebook.gohtml", data)
+}
+
+// gradebookCSV is the handler function to download the gradebook of a course as CSV, for
+// the students enrolled in the term given or outside any term. GradebookCSVInTerm of the
+// REST API is invoked.
+func gradebookCSV(w http.ResponseWriter, r *http.Request) {
+	courseID := r.URL.Query().Get("courseid")
+
+	data, err := api.GradebookCSVInTerm(r.Context(), courseID, r.URL.Query().Get("termid"))
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		logger(r.Context()).Error("error exporting gradebook", "courseid", courseID, "error", err)
+		http.Error(w, "Error exporting gradebook.", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="gradebook.csv"`)
+	w.Write(data)
+}
+
+// addAssessment adds the assessment entered on the grading page. Returns the assessment to
+// show in the form again if it was not added, the message for the user and error type.
+func addAssessment(r *http.Request, courseID string) (coursesapi.Assessment, string, error) {
+	form := coursesapi.Assessment{CourseID: courseID, Name: r.FormValue("name")}
+
+	weight, err := strconv.ParseFloat(r.FormValue("weight"), 64)
+	if err != nil || weight <= 0 || weight > 100 {
+		return form, ">> Weight must be a number more than 0 and at most 100", nil
+	}
+	form.Weight = weight
+
+	maxScore, err := strconv.ParseFloat(r.FormValue("maxscore"), 64)
+	if err != nil || maxScore <= 0 {
+		return form, ">> Maximum score must be a number more than 0", nil
+	}
+	form.MaxScore = maxScore
+
+	if form.Name == "" || len(form.Name) > 45 {
+		return form, ">> Name cannot be blank or greater than 45 characters", nil
+	}
+
+	if _, err := api.AddAssessment(r.Context(), form); err != nil {
+		return form, "", err
+	}
+	return coursesapi.Assessment{}, fmt.Sprintf("%s added successfully.\n", form.Name), nil
+}
+
+// saveScores records the scores entered on the grading page for the students enrolled in the
+// term given, in inputs named score-<assessment id>-<student id>. A score cleared by the
+// user is deleted. Returns the message for the user and error type.
+func saveScores(r *http.Request, courseID string, termID string) (string, error) {
+	book, err := api.GradebookInTerm(r.Context(), courseID, termID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range book.Assessments {
+		scores := make(map[string]float64)
+		for _, g := range book.Grades {
+			value := strings.TrimSpace(r.FormValue(fmt.Sprintf("score-%d-%s", a.ID, g.StudentID)))
+			current, scored := g.Scores[a.ID]
+
+			if value == "" {
+				if scored {
+					if err := api.DeleteScoreInTerm(r.Context(), courseID, termID, a.ID, g.StudentID); err != nil {
+						return "", err
+					}
+				}
+				continue
+			}
+
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Sprintf(">> Score of %s in %s must be a number", g.StudentID, a.Name), nil
+			}
+			if !scored || score != current {
+				scores[g.StudentID] = score
+			}
+		}
+
+		if len(scores) != 0 {
+			if err := api.SetScoresInTerm(r.Context(), courseID, termID, a.ID, scores); err != nil {
+				return "", err
+			}
+		}
+	}
+	return "Scores saved successfully.", nil
+}
+
+// parseGradeScale converts user input for letter-grade boundaries, e.g. "A:80 B:70 F:0",
+// to the minimum percentage keyed by grade. Returns error type.
+func parseGradeScale(s string) (map[string]float64, error) {
+	scale := make(map[string]float64)
+	for _, field := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+		grade, min, ok := strings.Cut(field, ":")
+		percent, err := strconv.ParseFloat(min, 64)
+		if !ok || grade == "" || err != nil {
+			return nil, errors.New(">> Please enter the boundaries as grade:percentage, e.g. A:80 B:70 F:0")
+		}
+		scale[grade] = percent
+	}
+	return scale, nil
+}
+
+// formatGradeScale formats letter-grade boundaries for the grading page, highest first.
+func formatGradeScale(scale map[string]float64) string {
+	grades := make([]string, 0, len(scale))
+	for grade := range scale {
+		grades = append(grades, grade)
+	}
+	sort.Slice(grades, func(i, j int) bool {
+		return scale[grades[i]] > scale[grades[j]]
+	})
+
+	fields := make([]string, len(grades))
+	for i, grade := range grades {
+		fields[i] = grade + ":" + strconv.FormatFloat(scale[grade], 'f', -1, 64)
+	}
+	return strings.Join(fields, " ")
+}
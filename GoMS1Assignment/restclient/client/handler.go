@@ -1,202 +1,606 @@
-package client
-
-import (
-	"errors"
-	"fmt"
-	"net/http"
-
-	"github.com/kennygrant/sanitize"
-)
-
-// courseInfo struct for the json
-type courseInfo struct {
-	Title string `json:"Title"`
-}
-
-// index is the handler function to display the home page of the client.
-// This is also the page where all courses are retrieved and displayed.
-// CRUD function for getCourse is invoked.
-func index(w http.ResponseWriter, r *http.Request) {
-	courses := getCourse("") // Get all courses
-	tpl.ExecuteTemplate(w, "index.gohtml", courses)
-}
-
-// addcourse is the handler function to retrieve user input for new course details.
-// Validations are performed to ensure valid course details are submitted.
-// CRUD function for addCourse is invoked.
-func addcourse(w http.ResponseWriter, r *http.Request) {
-	clientMsg := "" // To display message to the user on the client
-	courseID := ""
-	courseTitle := ""
-
-	if r.Method == http.MethodPost {
-		courseID = r.FormValue("courseid")
-		courseTitle = r.FormValue("coursetitle")
-
-		if err := validateCourseID(courseID); err != nil {
-			clientMsg = err.Error()
-		} else if err := validateCourseTitle(courseTitle); err != nil {
-			clientMsg = err.Error()
-		} else {
-			jsonData := map[string]string{"title": courseTitle}
-
-			responseCode := addCourse(courseID, jsonData)
-
-			if responseCode == 201 { // 201 - http.StatusCreated
-				clientMsg = fmt.Sprintf("%s - %s added successfully.\n", courseID, courseTitle)
-			} else if responseCode == 409 { // 409 - http.StatusConflict
-				clientMsg = ">> Duplicate Course ID."
-			} else {
-				clientMsg = ">> Error adding course. Please contact the system administrator."
-			}
-		}
-	}
-
-	data := struct {
-		CourseID    string
-		CourseTitle string
-		ClientMsg   string
-	}{
-		courseID,
-		courseTitle,
-		clientMsg,
-	}
-
-	tpl.ExecuteTemplate(w, "addcourse.gohtml", data)
-}
-
-// updcourse is the handler function to retrieve user input for change in course title.
-// Validations are performed to ensure valid course title are submitted.
-// CRUD function for updateCourse is invoked.
-func updcourse(w http.ResponseWriter, r *http.Request) {
-	clientMsg := "" // To display message to the user on the client
-	courseID := ""
-	courseTitle := ""
-	validCourseID := true // Determine whether to show course info
-
-	v := r.URL.Query()
-	if key, ok := v["courseid"]; ok {
-		courseID = sanitize.Accents(key[0])
-	}
-
-	if courseID != "" {
-		courses := getCourse(courseID) // Get all courses
-		if len(courses) == 0 {
-			validCourseID = false
-			clientMsg = ">> Invalid Course ID"
-		} else {
-			courseTitle = courses[courseID].Title
-		}
-	}
-
-	if r.Method == http.MethodPost {
-		courseID = r.FormValue("courseid")
-		courseTitle = r.FormValue("coursetitle")
-
-		if err := validateCourseTitle(courseTitle); err != nil {
-			clientMsg = err.Error()
-		} else {
-			jsonData := map[string]string{"title": courseTitle}
-			responseCode := updateCourse(courseID, jsonData)
-
-			if responseCode == 200 { // 200 - http.StatusOK
-				clientMsg = fmt.Sprintf("%s - %s updated successfully.\n", courseID, courseTitle)
-			} else if responseCode == 404 { // 404 - http.StatusNotFound
-				clientMsg = ">> Course not found."
-			} else {
-				clientMsg = ">> Error updating course."
-			}
-		}
-	}
-
-	data := struct {
-		CourseID      string
-		CourseTitle   string
-		ClientMsg     string
-		ValidCourseID bool
-	}{
-		courseID,
-		courseTitle,
-		clientMsg,
-		validCourseID,
-	}
-
-	tpl.ExecuteTemplate(w, "updcourse.gohtml", data)
-}
-
-// delcourse is the handler function to delete a course details as selected by the user.
-// CRUD function for deleteCourse is invoked.
-func delcourse(w http.ResponseWriter, r *http.Request) {
-	clientMsg := "" // To display message to the user on the client
-	courseID := ""
-	courseTitle := ""
-	validCourseID := true // Determine whether to show course info
-
-	v := r.URL.Query()
-	if key, ok := v["courseid"]; ok {
-		courseID = sanitize.Accents(key[0])
-	}
-
-	if courseID != "" {
-		courses := getCourse(courseID) // Get all courses
-		if len(courses) == 0 {
-			validCourseID = false
-			clientMsg = ">> Invalid Course ID"
-		} else {
-			courseTitle = courses[courseID].Title
-		}
-	}
-
-	if r.Method == http.MethodPost {
-		courseID = r.FormValue("courseid")
-		courseTitle = r.FormValue("coursetitle")
-
-		responseCode := deleteCourse(courseID)
-
-		if responseCode == 200 { // 200 - http.StatusOK
-			clientMsg = fmt.Sprintf("%s - %s deleted successfully.\n", courseID, courseTitle)
-		} else if responseCode == 404 { // 404 - http.StatusNotFound
-			clientMsg = ">> Course not found."
-		} else {
-			clientMsg = ">> Error deleting course."
-		}
-	}
-
-	data := struct {
-		CourseID      string
-		CourseTitle   string
-		ClientMsg     string
-		ValidCourseID bool
-	}{
-		courseID,
-		courseTitle,
-		clientMsg,
-		validCourseID,
-	}
-
-	tpl.ExecuteTemplate(w, "delcourse.gohtml", data)
-}
-
-// validateCourseID checks that user input for course id is valid. Returns error type.
-func validateCourseID(courseID string) error {
-	if courseID != "" {
-		if len(courseID) > 6 {
-			return errors.New(">> Course ID cannot be greater than 6 characters")
-		}
-	} else {
-		return errors.New(">> Course ID cannot be blank")
-	}
-	return nil
-}
-
-// validateCourseTitle checks that user input for course title is valid. Returns error type.
-func validateCourseTitle(courseTitle string) error {
-	if courseTitle != "" {
-		if len(courseTitle) > 45 {
-			return errors.New(">> Course Title cannot be greater than 45 characters")
-		}
-	} else {
-		return errors.New(">> Course Title cannot be blank")
-	}
-	return nil
-}
+package client
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"GoMS1Assignment/coursesapi"
+	"GoMS1Assignment/validation"
+)
+
+// courseMsgs are the messages shown on the course page after an enrolment, instructor,
+// session, status, attachment or rename action, keyed by the msg query parameter set by the
+// enrolment, assignment, session, status, attachment and rename handlers.
+var courseMsgs = map[string]string{
+	"enrolled":          "Student enrolled successfully.",
+	"waitlisted":        "The course is full. Student added to the waitlist.",
+	"withdrawn":         "Student withdrawn successfully.",
+	"completed":         "Student marked as completed.",
+	"assigned":          "Instructor assigned successfully.",
+	"unassigned":        "Instructor removed successfully.",
+	"sessionadded":      "Session added successfully.",
+	"sessiondeleted":    "Session deleted successfully.",
+	"clash":             ">> The session clashes with another session in the same room or with the same instructor.",
+	"invalidsession":    ">> Please enter a term, day, room, and start and end times as HH:MM.",
+	"offered":           "Course offered successfully.",
+	"offeringdeleted":   "Offering withdrawn successfully.",
+	"offeringinuse":     ">> Students are enrolled or waitlisted in the offering. Please withdraw them first.",
+	"invalidcapacity":   ">> Capacity must be a whole number, or blank for unlimited.",
+	"notfound":          ">> Course, student, instructor or term offering not found.",
+	"conflict":          ">> The enrolment cannot be changed.",
+	"invalidrole":       ">> Please select a role of lead or assistant.",
+	"statuspending":     "Course submitted for approval.",
+	"statusdraft":       "Course returned to draft.",
+	"statusretired":     "Course retired.",
+	"statusconflict":    ">> The status of the course cannot be changed. It may have been changed meanwhile.",
+	"invalidcomment":    ">> Please enter a comment of up to 255 characters.",
+	"attachmentadded":   "Attachment uploaded successfully.",
+	"attachmentdeleted": "Attachment deleted successfully.",
+	"invalidattachment": ">> Please upload a PDF or DOCX file of at most 8 MB.",
+	"error":             ">> Error updating course.",
+	"renamed":           "Course renamed successfully.",
+	"invalidcourseid":   ">> Please enter a valid course ID which differs from the current one.",
+	"courseidinuse":     ">> The course ID is in use by another course, or was the ID of a renamed course.",
+}
+
+// index is the handler function to display the home page of the client.
+// This is also the page where the active courses are retrieved and displayed grouped by
+// department, optionally only those of the instructor, department, tag or status selected,
+// or those offered in the term selected.
+// FindCourses, ListInstructors, ListDepartments, ListTags, ListTerms and ListOfferings of the
+// REST API are invoked.
+func index(w http.ResponseWriter, r *http.Request) {
+	filter := coursesapi.CourseFilter{
+		Instructor: r.URL.Query().Get("instructor"),
+		Department: r.URL.Query().Get("department"),
+		Tag:        r.URL.Query().Get("tag"),
+		Status:     r.URL.Query().Get("status"),
+	}
+	termID := r.URL.Query().Get("term")
+
+	courses, err := api.FindCourses(r.Context(), filter) // Get all courses
+	if err != nil {
+		logger(r.Context()).Error("error retrieving courses", "error", err)
+	}
+
+	var instructors []coursesapi.Instructor
+	if err == nil {
+		instructors, err = api.ListInstructors(r.Context()) // Get the instructors to filter by
+		if err != nil {
+			logger(r.Context()).Error("error retrieving instructors", "error", err)
+		}
+	}
+
+	var departments []coursesapi.Department
+	if err == nil {
+		departments, err = api.ListDepartments(r.Context()) // Get the departments to filter and group by
+		if err != nil {
+			logger(r.Context()).Error("error retrieving departments", "error", err)
+		}
+	}
+
+	var tags []coursesapi.TagCount
+	if err == nil {
+		tags, err = api.ListTags(r.Context()) // Get the tags to filter by
+		if err != nil {
+			logger(r.Context()).Error("error retrieving tags", "error", err)
+		}
+	}
+
+	var terms []coursesapi.Term
+	if err == nil {
+		terms, err = api.ListTerms(r.Context()) // Get the terms to filter by
+		if err != nil {
+			logger(r.Context()).Error("error retrieving terms", "error", err)
+		}
+	}
+
+	// The offerings of the term selected, of the courses of the instructor selected
+	var offerings []coursesapi.Offering
+	if err == nil && termID != "" {
+		var list []coursesapi.Offering
+		list, err = api.ListOfferings(r.Context(), termID)
+		if err != nil && !errors.Is(err, coursesapi.ErrNotFound) {
+			logger(r.Context()).Error("error retrieving offerings", "termid", termID, "error", err)
+		}
+
+		listed := make(map[string]bool)
+		for _, c := range courses {
+			listed[c.ID] = true
+		}
+		for _, o := range list {
+			if listed[o.CourseID] {
+				offerings = append(offerings, o)
+			}
+		}
+	}
+
+	data := struct {
+		Groups      []courseGroup
+		Instructors []coursesapi.Instructor
+		Instructor  string
+		Departments []coursesapi.Department
+		Department  string
+		Tags        []coursesapi.TagCount
+		Tag         string
+		Statuses    []string
+		Status      string
+		Terms       []coursesapi.Term
+		Term        string
+		Offerings   []coursesapi.Offering
+		Unavailable bool
+	}{
+		groupCourses(courses, departments),
+		instructors,
+		filter.Instructor,
+		departments,
+		filter.Department,
+		tags,
+		filter.Tag,
+		courseStatuses,
+		filter.Status,
+		terms,
+		termID,
+		offerings,
+		errors.Is(err, coursesapi.ErrUnavailable),
+	}
+
+	tpl.ExecuteTemplate(w, "index.gohtml", data)
+}
+
+// cloneCopies are the related data offered to be copied when duplicating a course. Its tags
+// are set from the form as for a new course.
+var cloneCopies = []struct{ Value, Label string }{
+	{coursesapi.CopyInstructors, "Instructors"},
+	{coursesapi.CopyPrerequisites, "Prerequisites"},
+	{coursesapi.CopyGradebook, "Assessments and grade scale"},
+	{coursesapi.CopyAttachments, "Attachments"},
+}
+
+// addcourse is the handler function to retrieve user input for new course details.
+// With ?from= it duplicates that course instead, with its details filled in to be changed,
+// and copies the related data selected.
+// Validations are performed to ensure valid course details are submitted.
+// AddCourse or CloneCourse, and SetCourseTags of the REST API are invoked.
+func addcourse(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	courseID := ""
+	courseTitle := ""
+	description := ""
+	capacity := ""
+	department := ""
+	tags := ""
+	from := validation.CanonicalCourseID(r.FormValue("from")) // Course being duplicated
+	include := make(map[string]bool)                          // Related data copied from it
+	var fieldErrs validation.Errors                           // Errors shown next to the fields
+	unavailable := false                                      // Determine whether to show the service unavailable banner
+
+	if from != "" && r.Method != http.MethodPost {
+		course, err := api.GetCourse(r.Context(), from) // Get the course to fill in its details
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+		} else if err != nil {
+			clientMsg = ">> Course to duplicate not found."
+			from = ""
+		} else {
+			courseTitle = course.Title
+			if course.Description != nil {
+				description = *course.Description
+			}
+			if course.Capacity != nil && *course.Capacity > 0 {
+				capacity = strconv.Itoa(*course.Capacity)
+			}
+			if course.Department != nil {
+				department = *course.Department
+			}
+			tags = strings.Join(course.Tags, ", ")
+			for _, c := range cloneCopies {
+				include[c.Value] = true
+			}
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		courseID = validation.CanonicalCourseID(r.FormValue("courseid"))
+		courseTitle = r.FormValue("coursetitle")
+		description = r.FormValue("description")
+		capacity = r.FormValue("capacity")
+		department = r.FormValue("department")
+		tags = r.FormValue("tags")
+		for _, value := range r.Form["include"] {
+			include[value] = true
+		}
+
+		seats, capacityErr := parseCapacity(capacity)
+		if capacityErr == nil {
+			fieldErrs = currentRules().ValidateNewCourse(validation.Course{
+				ID: courseID, Title: courseTitle, Description: description, Capacity: seats, Tags: parseTags(tags),
+			})
+		}
+		if capacityErr != nil {
+			clientMsg = capacityErr.Error()
+		} else if len(fieldErrs) != 0 {
+			clientMsg = invalidFieldsMsg
+		} else {
+			course := coursesapi.Course{
+				ID: courseID, Title: courseTitle, Description: &description, Capacity: &seats, Department: &department,
+			}
+
+			var err error
+			if from != "" {
+				var copies []string
+				for _, c := range cloneCopies {
+					if include[c.Value] {
+						copies = append(copies, c.Value)
+					}
+				}
+				err = api.CloneCourse(r.Context(), from, course, copies...)
+			} else {
+				err = api.AddCourse(r.Context(), course)
+			}
+
+			if err == nil {
+				// The course is added by now, so failing to tag it is reported with the course added
+				added := "added as a draft"
+				if from != "" {
+					added += " copy of " + from
+				}
+				if err := api.SetCourseTags(r.Context(), courseID, parseTags(tags)); err != nil {
+					if !errors.Is(err, coursesapi.ErrUnavailable) {
+						logger(r.Context()).Error("error tagging course", "courseid", courseID, "error", err)
+					}
+					clientMsg = fmt.Sprintf("%s - %s %s, but its tags could not be saved. Set them on its course page.\n", courseID, courseTitle, added)
+				} else {
+					clientMsg = fmt.Sprintf("%s - %s %s. Submit it for approval on its course page.\n", courseID, courseTitle, added)
+				}
+			} else if errors.Is(err, coursesapi.ErrConflict) {
+				clientMsg = ">> Duplicate Course ID."
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Course to duplicate not found."
+			} else if fieldErrs = apiFieldErrors(err); fieldErrs != nil {
+				clientMsg = invalidFieldsMsg
+			} else if errors.Is(err, coursesapi.ErrInvalid) {
+				clientMsg = ">> Department not found."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error adding course", "courseid", courseID, "from", from, "error", err)
+				clientMsg = ">> Error adding course. Please contact the system administrator."
+			}
+		}
+	}
+
+	departments, err := api.ListDepartments(r.Context()) // Get the departments to select from
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving departments", "error", err)
+	}
+
+	data := struct {
+		CourseID    string
+		CourseTitle string
+		Description string
+		Capacity    string
+		Department  string
+		Departments []coursesapi.Department
+		Tags        string
+		From        string
+		Copies      []struct{ Value, Label string }
+		Include     map[string]bool
+		Errors      validation.Errors
+		ClientMsg   string
+		Unavailable bool
+	}{
+		courseID,
+		courseTitle,
+		description,
+		capacity,
+		department,
+		departments,
+		tags,
+		from,
+		cloneCopies,
+		include,
+		fieldErrs,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "addcourse.gohtml", data)
+}
+
+// updcourse is the handler function to retrieve user input for change in course title, description,
+// capacity, department and tags.
+// Validations are performed to ensure valid course details are submitted.
+// The rendered description, attachments, instructors, offerings, sessions and enrolments of the
+// course are also displayed, with actions to upload attachments, assign instructors, offer the
+// course in terms, schedule sessions and enrol students.
+// UpdateCourse and SetCourseTags of the REST API are invoked.
+func updcourse(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	courseID := ""
+	courseTitle := ""
+	description := ""
+	capacity := ""
+	department := ""
+	tags := ""
+	status := ""
+	var fieldErrs validation.Errors // Errors shown next to the fields
+	var assignments []coursesapi.Assignment
+	validCourseID := true // Determine whether to show course info
+	unavailable := false  // Determine whether to show the service unavailable banner
+
+	v := r.URL.Query()
+	if key, ok := v["courseid"]; ok {
+		courseID = validation.CanonicalCourseID(key[0])
+	}
+	if key, ok := v["msg"]; ok {
+		clientMsg = courseMsgs[key[0]]
+	}
+
+	if courseID != "" {
+		course, err := api.GetCourse(r.Context(), courseID) // Get the course
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			validCourseID = false
+			unavailable = true
+		} else if err != nil {
+			validCourseID = false
+			clientMsg = ">> Invalid Course ID"
+		} else {
+			courseTitle = course.Title
+			if course.Description != nil {
+				description = *course.Description
+			}
+			if course.Capacity != nil && *course.Capacity > 0 {
+				capacity = strconv.Itoa(*course.Capacity)
+			}
+			assignments = course.Instructors
+			if course.Department != nil {
+				department = *course.Department
+			}
+			tags = strings.Join(course.Tags, ", ")
+			status = course.Status
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		courseID = validation.CanonicalCourseID(r.FormValue("courseid"))
+		courseTitle = r.FormValue("coursetitle")
+		description = r.FormValue("description")
+		capacity = r.FormValue("capacity")
+		department = r.FormValue("department")
+		tags = r.FormValue("tags")
+
+		seats, capacityErr := parseCapacity(capacity)
+		if capacityErr == nil {
+			fieldErrs = currentRules().ValidateCourse(validation.Course{
+				Title: courseTitle, Description: description, Capacity: seats, Tags: parseTags(tags),
+			})
+		}
+		if capacityErr != nil {
+			clientMsg = capacityErr.Error()
+		} else if len(fieldErrs) != 0 {
+			clientMsg = invalidFieldsMsg
+		} else {
+			err := api.UpdateCourse(r.Context(), coursesapi.Course{
+				ID: courseID, Title: courseTitle, Description: &description, Capacity: &seats, Department: &department,
+			})
+			if err == nil {
+				err = api.SetCourseTags(r.Context(), courseID, parseTags(tags))
+			}
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s - %s updated successfully.\n", courseID, courseTitle)
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Course not found."
+			} else if fieldErrs = apiFieldErrors(err); fieldErrs != nil {
+				clientMsg = invalidFieldsMsg
+			} else if errors.Is(err, coursesapi.ErrInvalid) {
+				clientMsg = ">> Department not found, or tags are not valid."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error updating course", "courseid", courseID, "error", err)
+				clientMsg = ">> Error updating course."
+			}
+		}
+	}
+
+	var enrolments courseEnrolments
+	var instructors []coursesapi.Instructor
+	var sessions []coursesapi.Session
+	var offerings []coursesapi.Offering
+	var terms []coursesapi.Term
+	var departments []coursesapi.Department
+	var history []coursesapi.StatusChange
+	var attachments []coursesapi.Attachment
+	if validCourseID && courseID != "" && !unavailable {
+		var err error
+		enrolments, err = getCourseEnrolments(r, courseID) // Get the enrolments
+		if err == nil {
+			history, err = api.CourseHistory(r.Context(), courseID) // Get the history of the status
+		}
+		if err == nil {
+			attachments, err = api.ListAttachments(r.Context(), courseID) // Get the attachments
+		}
+		if err == nil {
+			departments, err = api.ListDepartments(r.Context()) // Get the departments to select from
+		}
+		if err == nil {
+			// Get the instructors who may be assigned
+			instructors, err = unassignedInstructors(r, assignments)
+		}
+		if err == nil {
+			offerings, err = api.ListCourseOfferings(r.Context(), courseID) // Get the offerings
+		}
+		if err == nil {
+			// Get the terms the course may be offered in
+			terms, err = unofferedTerms(r, offerings)
+		}
+		if err == nil {
+			sessions, err = api.ListSessions(r.Context(), courseID) // Get the sessions
+		}
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+		} else if err != nil {
+			logger(r.Context()).Error("error retrieving course details", "courseid", courseID, "error", err)
+		}
+	}
+
+	data := struct {
+		CourseID      string
+		CourseTitle   string
+		Description   string
+		Preview       template.HTML // the description rendered from Markdown
+		Capacity      string
+		Department    string
+		Departments   []coursesapi.Department
+		Tags          string
+		Attachments   []coursesapi.Attachment
+		Status        string
+		StatusActions []statusAction
+		History       []coursesapi.StatusChange
+		Errors        validation.Errors
+		ClientMsg     string
+		ValidCourseID bool
+		Unavailable   bool
+		Assignments   []coursesapi.Assignment
+		Instructors   []coursesapi.Instructor // instructors who may be assigned
+		Roles         []string
+		Sessions      []coursesapi.Session
+		Days          []string
+		Offerings     []coursesapi.Offering
+		Terms         []coursesapi.Term // terms the course may be offered in
+		courseEnrolments
+	}{
+		courseID,
+		courseTitle,
+		description,
+		renderMarkdown(description),
+		capacity,
+		department,
+		departments,
+		tags,
+		attachments,
+		status,
+		statusActions[status],
+		history,
+		fieldErrs,
+		clientMsg,
+		validCourseID,
+		unavailable,
+		assignments,
+		instructors,
+		instructorRoles,
+		sessions,
+		coursesapi.Days,
+		offerings,
+		terms,
+		enrolments,
+	}
+
+	tpl.ExecuteTemplate(w, "updcourse.gohtml", data)
+}
+
+// delcourse is the handler function to delete a course details as selected by the user.
+// DeleteCourse of the REST API is invoked.
+func delcourse(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	courseID := ""
+	courseTitle := ""
+	validCourseID := true // Determine whether to show course info
+	unavailable := false  // Determine whether to show the service unavailable banner
+
+	v := r.URL.Query()
+	if key, ok := v["courseid"]; ok {
+		courseID = validation.CanonicalCourseID(key[0])
+	}
+
+	if courseID != "" {
+		course, err := api.GetCourse(r.Context(), courseID) // Get the course
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			validCourseID = false
+			unavailable = true
+		} else if err != nil {
+			validCourseID = false
+			clientMsg = ">> Invalid Course ID"
+		} else {
+			courseTitle = course.Title
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		courseID = validation.CanonicalCourseID(r.FormValue("courseid"))
+		courseTitle = r.FormValue("coursetitle")
+
+		err := api.DeleteCourse(r.Context(), courseID)
+
+		if err == nil {
+			clientMsg = fmt.Sprintf("%s - %s deleted successfully.\n", courseID, courseTitle)
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Course not found."
+		} else if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+		} else {
+			logger(r.Context()).Error("error deleting course", "courseid", courseID, "error", err)
+			clientMsg = ">> Error deleting course."
+		}
+	}
+
+	data := struct {
+		CourseID      string
+		CourseTitle   string
+		ClientMsg     string
+		ValidCourseID bool
+		Unavailable   bool
+	}{
+		courseID,
+		courseTitle,
+		clientMsg,
+		validCourseID,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "delcourse.gohtml", data)
+}
+
+// renamecourse is the handler function for changing the course ID of a course on the course
+// page. It redirects to the course page under the new course ID, or back to the course page
+// with a message if the course could not be renamed.
+// RenameCourse of the REST API is invoked.
+func renamecourse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+	newID := validation.CanonicalCourseID(r.FormValue("newcourseid"))
+
+	msg := "invalidcourseid"
+	if len(currentRules().ValidateCourseID(newID)) == 0 && newID != courseID {
+		err := api.RenameCourse(r.Context(), courseID, newID)
+		if err == nil {
+			msg = "renamed"
+			courseID = newID
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			msg = "notfound"
+		} else if errors.Is(err, coursesapi.ErrConflict) {
+			msg = "courseidinuse"
+		} else if !errors.Is(err, coursesapi.ErrInvalid) {
+			if !errors.Is(err, coursesapi.ErrUnavailable) {
+				logger(r.Context()).Error("error renaming course", "courseid", courseID, "newcourseid", newID, "error", err)
+			}
+			msg = "error"
+		}
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// healthStatus struct for the json
+type healthStatus struct {
+	Status string            `json:"Status"`
+	Checks map[string]string `json:"Checks"`
+}
+
+// healthz is the handler function for the health check of the client.
+// It reports the client as unavailable if the REST API cannot be reached, logging the
+// error rather than reporting it as the check is public.
+func healthz(w http.ResponseWriter, r *http.Request) {
+	status := healthStatus{Status: "ok", Checks: map[string]string{}}
+	code := http.StatusOK
+
+	// Do not let a hung REST API hang the check
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	if err := api.Health(ctx); err != nil {
+		status.Status = "unavailable"
+		logger(r.Context()).Error("health check: reaching REST API", "error", err)
+		status.Checks["restapi"] = "unavailable"
+		code = http.StatusServiceUnavailable
+	} else {
+		status.Checks["restapi"] = "ok"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
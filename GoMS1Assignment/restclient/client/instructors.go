@@ -0,0 +1,167 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// instructorRoles are the roles an instructor can be assigned to a course, in the order shown to the user.
+var instructorRoles = []string{coursesapi.Lead, coursesapi.Assistant}
+
+// instructors is the handler function to display all instructors, add an instructor
+// and delete an instructor. ListInstructors, AddInstructor and DeleteInstructor of the
+// REST API are invoked.
+func instructors(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	var instructor coursesapi.Instructor
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		if r.FormValue("action") == "delete" {
+			instructorID := r.FormValue("instructorid")
+			err := api.DeleteInstructor(r.Context(), instructorID)
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s deleted successfully.\n", instructorID)
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Instructor not found."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error deleting instructor", "instructorid", instructorID, "error", err)
+				clientMsg = ">> Error deleting instructor."
+			}
+		} else {
+			instructor = coursesapi.Instructor{
+				ID:    r.FormValue("instructorid"),
+				Name:  r.FormValue("name"),
+				Email: r.FormValue("email"),
+			}
+
+			if err := validateInstructor(instructor); err != nil {
+				clientMsg = err.Error()
+			} else {
+				err := api.AddInstructor(r.Context(), instructor)
+
+				if err == nil {
+					clientMsg = fmt.Sprintf("%s - %s added successfully.\n", instructor.ID, instructor.Name)
+					instructor = coursesapi.Instructor{}
+				} else if errors.Is(err, coursesapi.ErrConflict) {
+					clientMsg = ">> Duplicate Instructor ID."
+				} else if errors.Is(err, coursesapi.ErrUnavailable) {
+					unavailable = true
+				} else {
+					logger(r.Context()).Error("error adding instructor", "instructorid", instructor.ID, "error", err)
+					clientMsg = ">> Error adding instructor. Please contact the system administrator."
+				}
+			}
+		}
+	}
+
+	list, err := api.ListInstructors(r.Context()) // Get all instructors
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving instructors", "error", err)
+	}
+
+	data := struct {
+		Instructors []coursesapi.Instructor
+		Instructor  coursesapi.Instructor
+		ClientMsg   string
+		Unavailable bool
+	}{
+		list,
+		instructor,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "instructors.gohtml", data)
+}
+
+// assignment is the handler function for assigning instructors to a course and removing
+// them on the course page. It redirects back to the course page with a message of the outcome.
+// AssignInstructor and UnassignInstructor of the REST API are invoked.
+func assignment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+	instructorID := r.FormValue("instructorid")
+	role := r.FormValue("role")
+
+	var msg string
+	var err error
+	if r.FormValue("action") == "remove" {
+		err = api.UnassignInstructor(r.Context(), courseID, instructorID)
+		msg = "unassigned"
+	} else {
+		err = api.AssignInstructor(r.Context(), courseID, instructorID, role)
+		msg = "assigned"
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrConflict) {
+		msg = "clash"
+	} else if errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "invalidrole"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error assigning instructor", "courseid", courseID,
+				"instructorid", instructorID, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// unassignedInstructors returns the instructors who are not among the assignments of a course.
+// ListInstructors of the REST API is invoked.
+func unassignedInstructors(r *http.Request, assignments []coursesapi.Assignment) ([]coursesapi.Instructor, error) {
+	list, err := api.ListInstructors(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make(map[string]bool)
+	for _, a := range assignments {
+		assigned[a.InstructorID] = true
+	}
+
+	var unassigned []coursesapi.Instructor
+	for _, instructor := range list {
+		if !assigned[instructor.ID] {
+			unassigned = append(unassigned, instructor)
+		}
+	}
+	return unassigned, nil
+}
+
+// validateInstructor checks that user input for the instructor details is valid. Returns error type.
+func validateInstructor(instructor coursesapi.Instructor) error {
+	if instructor.ID == "" {
+		return errors.New(">> Instructor ID cannot be blank")
+	} else if len(instructor.ID) > 20 {
+		return errors.New(">> Instructor ID cannot be greater than 20 characters")
+	}
+	if instructor.Name == "" {
+		return errors.New(">> Name cannot be blank")
+	} else if len(instructor.Name) > 100 {
+		return errors.New(">> Name cannot be greater than 100 characters")
+	}
+	if _, err := mail.ParseAddress(instructor.Email); err != nil || len(instructor.Email) > 254 {
+		return errors.New(">> Please enter a valid email address")
+	}
+	return nil
+}
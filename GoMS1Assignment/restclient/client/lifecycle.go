@@ -0,0 +1,114 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// statusAction is an action on the course page which changes the status of the course.
+type statusAction struct {
+	Label  string
+	Status string // the status the course is changed to
+}
+
+// courseStatuses are the statuses the course list may be filtered by, besides active.
+var courseStatuses = []string{coursesapi.Draft, coursesapi.Pending, coursesapi.Retired, coursesapi.AllStatuses}
+
+// statusActions are the actions offered on the course page for each status of a course.
+// Pending courses are approved or rejected on the review queue page.
+var statusActions = map[string][]statusAction{
+	coursesapi.Draft:   {{"Submit for Approval", coursesapi.Pending}},
+	coursesapi.Pending: {{"Withdraw from Review", coursesapi.Draft}},
+	coursesapi.Active:  {{"Retire", coursesapi.Retired}},
+	coursesapi.Retired: {{"Reopen as Draft", coursesapi.Draft}},
+}
+
+// coursestatus is the handler function for changing the status of a course on the course
+// page. It redirects back to the course page with a message of the outcome.
+// SetCourseStatus of the REST API is invoked.
+func coursestatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+	status := r.FormValue("status")
+
+	msg := "status" + status
+	err := api.SetCourseStatus(r.Context(), courseID, status, r.FormValue("comment"))
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrConflict) {
+		msg = "statusconflict"
+	} else if errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "invalidcomment"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error changing course status", "courseid", courseID,
+				"status", status, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// reviews is the handler function for the review queue page, where admins approve or reject
+// the courses pending approval with a comment for their authors.
+// FindCourses and ReviewCourse of the REST API are invoked.
+func reviews(w http.ResponseWriter, r *http.Request) {
+	clientMsg := ""      // To display message to the user on the client
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		courseID := r.FormValue("courseid")
+		decision := r.FormValue("decision")
+
+		err := api.ReviewCourse(r.Context(), courseID, decision, r.FormValue("comment"))
+
+		if err == nil && decision == coursesapi.Approve {
+			clientMsg = fmt.Sprintf("%s approved and now active.\n", courseID)
+		} else if err == nil {
+			clientMsg = fmt.Sprintf("%s rejected and returned to draft.\n", courseID)
+		} else if errors.Is(err, coursesapi.ErrForbidden) {
+			clientMsg = ">> The access key of the client is not an admin key, so it cannot review courses."
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Course not found."
+		} else if errors.Is(err, coursesapi.ErrConflict) {
+			clientMsg = ">> The course is no longer pending approval."
+		} else if errors.Is(err, coursesapi.ErrInvalid) {
+			clientMsg = ">> Please enter a comment of up to 255 characters explaining the rejection."
+		} else if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+		} else {
+			logger(r.Context()).Error("error reviewing course", "courseid", courseID, "error", err)
+			clientMsg = ">> Error reviewing course."
+		}
+	}
+
+	// Get the courses pending approval
+	pending, err := api.FindCourses(r.Context(), coursesapi.CourseFilter{Status: coursesapi.Pending})
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving courses pending approval", "error", err)
+	}
+
+	data := struct {
+		Courses     []coursesapi.Course
+		ClientMsg   string
+		Unavailable bool
+	}{
+		pending,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "reviews.gohtml", data)
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown converts course descriptions to HTML. Raw HTML in a description is not rendered.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// sanitizer removes anything but the formatting of user-generated content from the rendered
+// HTML, such as scripts and event handlers, as a second line of defence.
+var sanitizer = bluemonday.UGCPolicy()
+
+// renderMarkdown returns the HTML of the Markdown given, safe to be shown on a page.
+func renderMarkdown(source string) template.HTML {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return template.HTML(template.HTMLEscapeString(source))
+	}
+	return template.HTML(sanitizer.SanitizeBytes(buf.Bytes()))
+}
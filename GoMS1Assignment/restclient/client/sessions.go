@@ -0,0 +1,151 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// timetableDay is a column of the weekly timetable.
+type timetableDay struct {
+	Day      string
+	Sessions []coursesapi.Session
+}
+
+// timetable is the handler function to display the weekly timetable of the sessions of
+// all courses, filtered by term, student or instructor.
+// Timetable, ListTerms, ListStudents and ListInstructors of the REST API are invoked.
+func timetable(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	filter := coursesapi.SessionFilter{
+		Term:       v.Get("term"),
+		Student:    v.Get("student"),
+		Instructor: v.Get("instructor"),
+	}
+
+	list, err := api.Timetable(r.Context(), filter) // Get the sessions
+	if err != nil {
+		logger(r.Context()).Error("error retrieving timetable", "error", err)
+	}
+
+	// Get the terms, students and instructors to filter by
+	var terms []coursesapi.Term
+	var students []coursesapi.Student
+	var instructors []coursesapi.Instructor
+	if err == nil {
+		terms, err = api.ListTerms(r.Context())
+	}
+	if err == nil {
+		students, err = api.ListStudents(r.Context())
+	}
+	if err == nil {
+		instructors, err = api.ListInstructors(r.Context())
+	}
+
+	// Sessions are sorted by day then start time, so each day keeps that order
+	days := make([]timetableDay, len(coursesapi.Days))
+	for i, day := range coursesapi.Days {
+		days[i].Day = day
+		for _, s := range list {
+			if s.Day == day {
+				days[i].Sessions = append(days[i].Sessions, s)
+			}
+		}
+	}
+
+	data := struct {
+		Days        []timetableDay
+		Filter      coursesapi.SessionFilter
+		Terms       []coursesapi.Term
+		Students    []coursesapi.Student
+		Instructors []coursesapi.Instructor
+		Unavailable bool
+	}{
+		days,
+		filter,
+		terms,
+		students,
+		instructors,
+		errors.Is(err, coursesapi.ErrUnavailable),
+	}
+
+	tpl.ExecuteTemplate(w, "timetable.gohtml", data)
+}
+
+// session is the handler function for adding and deleting sessions on the course page.
+// It redirects back to the course page with a message of the outcome.
+// AddSession and DeleteSession of the REST API are invoked.
+func session(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+
+	var msg string
+	var err error
+	if r.FormValue("action") == "delete" {
+		sessionID, _ := strconv.Atoi(r.FormValue("sessionid"))
+		err = api.DeleteSession(r.Context(), courseID, sessionID)
+		msg = "sessiondeleted"
+	} else {
+		_, err = api.AddSession(r.Context(), coursesapi.Session{
+			CourseID: courseID,
+			Term:     r.FormValue("term"),
+			Day:      r.FormValue("day"),
+			Start:    r.FormValue("start"),
+			End:      r.FormValue("end"),
+			Room:     r.FormValue("room"),
+		})
+		msg = "sessionadded"
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrConflict) {
+		msg = "clash"
+	} else if errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "invalidsession"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error updating session", "courseid", courseID, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// calendar is the handler function to download the sessions of a course, or of the courses
+// a student is enrolled in, as an iCalendar document.
+// CourseCalendar or LearnerCalendar of the REST API is invoked.
+func calendar(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+
+	var ics []byte
+	var err error
+	if studentID := v.Get("studentid"); studentID != "" {
+		ics, err = api.LearnerCalendar(r.Context(), studentID, time.Time{}, time.Time{})
+	} else {
+		ics, err = api.CourseCalendar(r.Context(), v.Get("courseid"), time.Time{}, time.Time{})
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		logger(r.Context()).Error("error exporting calendar", "error", err)
+		http.Error(w, "Error exporting calendar.", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="timetable.ics"`)
+	w.Write(ics)
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+
+	"github.com/kennygrant/sanitize"
+)
+
+// studentStatuses are the statuses a student can be given, in the order shown to the user.
+var studentStatuses = []string{"active", "suspended", "graduated", "withdrawn"}
+
+// students is the handler function to display all students.
+// ListStudents of the REST API is invoked.
+func students(w http.ResponseWriter, r *http.Request) {
+	students, err := api.ListStudents(r.Context()) // Get all students
+	if err != nil {
+		logger(r.Context()).Error("error retrieving students", "error", err)
+	}
+
+	data := struct {
+		Students    []coursesapi.Student
+		Unavailable bool
+	}{
+		students,
+		errors.Is(err, coursesapi.ErrUnavailable),
+	}
+
+	tpl.ExecuteTemplate(w, "students.gohtml", data)
+}
+
+// addstudent is the handler function to retrieve user input for new student details.
+// Validations are performed to ensure valid student details are submitted.
+// AddStudent of the REST API is invoked.
+func addstudent(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	student := coursesapi.Student{Status: "active"}
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		student = studentFromForm(r)
+
+		if err := validateStudentID(student.ID); err != nil {
+			clientMsg = err.Error()
+		} else if err := validateStudent(student); err != nil {
+			clientMsg = err.Error()
+		} else {
+			err := api.AddStudent(r.Context(), student)
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s - %s added successfully.\n", student.ID, student.Name)
+			} else if errors.Is(err, coursesapi.ErrConflict) {
+				clientMsg = ">> Duplicate Student ID."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error adding student", "studentid", student.ID, "error", err)
+				clientMsg = ">> Error adding student. Please contact the system administrator."
+			}
+		}
+	}
+
+	data := struct {
+		Student     coursesapi.Student
+		Statuses    []string
+		ClientMsg   string
+		Unavailable bool
+	}{
+		student,
+		studentStatuses,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "addstudent.gohtml", data)
+}
+
+// updstudent is the handler function to retrieve user input for changes to a student.
+// Validations are performed to ensure valid student details are submitted.
+// UpdateStudent of the REST API is invoked.
+func updstudent(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	var student coursesapi.Student
+	validStudentID := true // Determine whether to show student info
+	unavailable := false   // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		student = studentFromForm(r)
+
+		if err := validateStudent(student); err != nil {
+			clientMsg = err.Error()
+		} else {
+			err := api.UpdateStudent(r.Context(), student)
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s - %s updated successfully.\n", student.ID, student.Name)
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Student not found."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error updating student", "studentid", student.ID, "error", err)
+				clientMsg = ">> Error updating student."
+			}
+		}
+	} else {
+		student, validStudentID, unavailable = lookupStudent(r)
+		if !validStudentID && !unavailable {
+			clientMsg = ">> Invalid Student ID"
+		}
+	}
+
+	data := struct {
+		Student        coursesapi.Student
+		Statuses       []string
+		ClientMsg      string
+		ValidStudentID bool
+		Unavailable    bool
+	}{
+		student,
+		studentStatuses,
+		clientMsg,
+		validStudentID,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "updstudent.gohtml", data)
+}
+
+// delstudent is the handler function to delete a student as selected by the user.
+// DeleteStudent of the REST API is invoked.
+func delstudent(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	student, validStudentID, unavailable := lookupStudent(r)
+	if !validStudentID && !unavailable {
+		clientMsg = ">> Invalid Student ID"
+	}
+
+	if r.Method == http.MethodPost && validStudentID {
+		err := api.DeleteStudent(r.Context(), student.ID)
+
+		if err == nil {
+			clientMsg = fmt.Sprintf("%s - %s deleted successfully.\n", student.ID, student.Name)
+			validStudentID = false
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Student not found."
+		} else if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+		} else {
+			logger(r.Context()).Error("error deleting student", "studentid", student.ID, "error", err)
+			clientMsg = ">> Error deleting student."
+		}
+	}
+
+	data := struct {
+		Student        coursesapi.Student
+		ClientMsg      string
+		ValidStudentID bool
+		Unavailable    bool
+	}{
+		student,
+		clientMsg,
+		validStudentID,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "delstudent.gohtml", data)
+}
+
+// lookupStudent retrieves the student given by the studentid url param.
+// Returns the student, whether it was found, and whether the REST API was unavailable.
+func lookupStudent(r *http.Request) (coursesapi.Student, bool, bool) {
+	studentID := ""
+	v := r.URL.Query()
+	if key, ok := v["studentid"]; ok {
+		studentID = sanitize.Accents(key[0])
+	}
+	if studentID == "" {
+		return coursesapi.Student{}, false, false
+	}
+
+	student, err := api.GetStudent(r.Context(), studentID)
+	if err != nil {
+		return coursesapi.Student{ID: studentID}, false, errors.Is(err, coursesapi.ErrUnavailable)
+	}
+	return student, true, false
+}
+
+// studentFromForm reads the student details submitted in the form.
+func studentFromForm(r *http.Request) coursesapi.Student {
+	return coursesapi.Student{
+		ID:          r.FormValue("studentid"),
+		Name:        r.FormValue("name"),
+		Email:       r.FormValue("email"),
+		DateOfBirth: r.FormValue("dateofbirth"),
+		Status:      r.FormValue("status"),
+	}
+}
+
+// validateStudentID checks that user input for student id is valid. Returns error type.
+func validateStudentID(studentID string) error {
+	if studentID != "" {
+		if len(studentID) > 20 {
+			return errors.New(">> Student ID cannot be greater than 20 characters")
+		}
+	} else {
+		return errors.New(">> Student ID cannot be blank")
+	}
+	return nil
+}
+
+// validateStudent checks that user input for the student details is valid. Returns error type.
+func validateStudent(student coursesapi.Student) error {
+	if student.Name == "" {
+		return errors.New(">> Name cannot be blank")
+	} else if len(student.Name) > 100 {
+		return errors.New(">> Name cannot be greater than 100 characters")
+	}
+	if _, err := mail.ParseAddress(student.Email); err != nil {
+		return errors.New(">> Please enter a valid email address")
+	}
+	if dob, err := time.Parse("2006-01-02", student.DateOfBirth); err != nil || dob.After(time.Now()) {
+		return errors.New(">> Please enter a valid date of birth")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+)
+
+// terms is the handler function to display all terms, add a term and delete a term.
+// ListTerms, AddTerm and DeleteTerm of the REST API are invoked.
+func terms(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	var term coursesapi.Term
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	if r.Method == http.MethodPost {
+		if r.FormValue("action") == "delete" {
+			termID := r.FormValue("termid")
+			err := api.DeleteTerm(r.Context(), termID)
+
+			if err == nil {
+				clientMsg = fmt.Sprintf("%s deleted successfully.\n", termID)
+			} else if errors.Is(err, coursesapi.ErrNotFound) {
+				clientMsg = ">> Term not found."
+			} else if errors.Is(err, coursesapi.ErrConflict) {
+				clientMsg = ">> Courses are offered in the term, or were completed in it, so it cannot be deleted."
+			} else if errors.Is(err, coursesapi.ErrUnavailable) {
+				unavailable = true
+			} else {
+				logger(r.Context()).Error("error deleting term", "termid", termID, "error", err)
+				clientMsg = ">> Error deleting term."
+			}
+		} else {
+			term = coursesapi.Term{
+				ID:        r.FormValue("termid"),
+				Name:      r.FormValue("name"),
+				StartDate: r.FormValue("startdate"),
+				EndDate:   r.FormValue("enddate"),
+			}
+
+			if err := validateTerm(term); err != nil {
+				clientMsg = err.Error()
+			} else {
+				err := api.AddTerm(r.Context(), term)
+
+				if err == nil {
+					clientMsg = fmt.Sprintf("%s - %s added successfully.\n", term.ID, term.Name)
+					term = coursesapi.Term{}
+				} else if errors.Is(err, coursesapi.ErrConflict) {
+					clientMsg = ">> Duplicate Term ID."
+				} else if errors.Is(err, coursesapi.ErrUnavailable) {
+					unavailable = true
+				} else {
+					logger(r.Context()).Error("error adding term", "termid", term.ID, "error", err)
+					clientMsg = ">> Error adding term. Please contact the system administrator."
+				}
+			}
+		}
+	}
+
+	list, err := api.ListTerms(r.Context()) // Get all terms
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving terms", "error", err)
+	}
+
+	data := struct {
+		Terms       []coursesapi.Term
+		Term        coursesapi.Term
+		ClientMsg   string
+		Unavailable bool
+	}{
+		list,
+		term,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "terms.gohtml", data)
+}
+
+// offering is the handler function for offering a course in a term and withdrawing the
+// offering on the course page. It redirects back to the course page with a message of the
+// outcome. SetOffering and DeleteOffering of the REST API are invoked.
+func offering(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	courseID := r.FormValue("courseid")
+	termID := r.FormValue("termid")
+
+	var msg string
+	var err error
+	if r.FormValue("action") == "withdraw" {
+		err = api.DeleteOffering(r.Context(), courseID, termID)
+		msg = "offeringdeleted"
+	} else if seats, capacityErr := parseCapacity(r.FormValue("capacity")); capacityErr != nil {
+		msg = "invalidcapacity"
+	} else {
+		err = api.SetOffering(r.Context(), courseID, termID, seats)
+		msg = "offered"
+	}
+
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		msg = "notfound"
+	} else if errors.Is(err, coursesapi.ErrConflict) {
+		msg = "offeringinuse"
+	} else if errors.Is(err, coursesapi.ErrInvalid) {
+		msg = "invalidcapacity"
+	} else if err != nil {
+		if !errors.Is(err, coursesapi.ErrUnavailable) {
+			logger(r.Context()).Error("error updating offering", "courseid", courseID,
+				"termid", termID, "error", err)
+		}
+		msg = "error"
+	}
+
+	v := url.Values{"courseid": {courseID}, "msg": {msg}}
+	http.Redirect(w, r, "/updcourse?"+v.Encode(), http.StatusSeeOther)
+}
+
+// unofferedTerms returns the terms in which the course is not among the offerings given.
+// ListTerms of the REST API is invoked.
+func unofferedTerms(r *http.Request, offerings []coursesapi.Offering) ([]coursesapi.Term, error) {
+	list, err := api.ListTerms(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	offered := make(map[string]bool)
+	for _, o := range offerings {
+		offered[o.TermID] = true
+	}
+
+	var unoffered []coursesapi.Term
+	for _, term := range list {
+		if !offered[term.ID] {
+			unoffered = append(unoffered, term)
+		}
+	}
+	return unoffered, nil
+}
+
+// validateTerm checks that user input for the term details is valid. Returns error type.
+func validateTerm(term coursesapi.Term) error {
+	if term.ID == "" {
+		return errors.New(">> Term ID cannot be blank")
+	} else if len(term.ID) > 20 {
+		return errors.New(">> Term ID cannot be greater than 20 characters")
+	}
+	if term.Name == "" {
+		return errors.New(">> Name cannot be blank")
+	} else if len(term.Name) > 45 {
+		return errors.New(">> Name cannot be greater than 45 characters")
+	}
+	start, err := time.Parse("2006-01-02", term.StartDate)
+	if err != nil {
+		return errors.New(">> Please enter the start date as YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", term.EndDate)
+	if err != nil || end.Before(start) {
+		return errors.New(">> Please enter an end date on or after the start date as YYYY-MM-DD")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// serviceName identifies the client in traces.
+const serviceName = "goschool-restclient"
+
+// initTracer configures the global tracer provider with the exporter named by the
+// OTEL_TRACES_EXPORTER environment variable:
+//
+//	otlp:   export over OTLP/HTTP, configured by the standard OTEL_EXPORTER_OTLP_* variables
+//	stdout: write spans as JSON to stdout, for local debugging
+//	none:   do not export spans (default)
+//
+// W3C traceparent headers are always propagated. Returns a function to flush and
+// shut down the provider.
+func initTracer(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch name := os.Getenv("OTEL_TRACES_EXPORTER"); name {
+	case "otlp":
+		exporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	slog.Info("tracing enabled", "exporter", os.Getenv("OTEL_TRACES_EXPORTER"))
+	return provider.Shutdown, nil
+}
+
+// accessKey is the context key of the access key taken off the URL of a request by
+// newTracingTransport.
+type accessKey struct{}
+
+// roundTripperFunc is an http.RoundTripper implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(request).
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+// newTracingTransport returns a transport which starts a client span for each call over
+// base and injects the W3C traceparent header. The access key is taken off the URL before
+// the span records it, and put back on before the request is sent over base.
+func newTracingTransport(base http.RoundTripper, opts ...otelhttp.Option) http.RoundTripper {
+	traced := otelhttp.NewTransport(roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		if key, ok := request.Context().Value(accessKey{}).(string); ok {
+			request = withKey(request.Context(), request, key)
+		}
+		return base.RoundTrip(request)
+	}), opts...)
+
+	return roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		key := request.URL.Query().Get("key")
+		if key == "" {
+			return traced.RoundTrip(request)
+		}
+		return traced.RoundTrip(withKey(context.WithValue(request.Context(), accessKey{}, key), request, ""))
+	})
+}
+
+// withKey returns a copy of the request with the context given, and the access key given
+// in the query of its URL, or none if key is "".
+func withKey(ctx context.Context, request *http.Request, key string) *http.Request {
+	request = request.Clone(ctx)
+	query := request.URL.Query()
+	if key == "" {
+		query.Del("key")
+	} else {
+		query.Set("key", key)
+	}
+	request.URL.RawQuery = query.Encode()
+	return request
+}
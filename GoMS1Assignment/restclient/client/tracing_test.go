@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracingTransportRedactsKey checks that the access key reaches the REST API but is
+// not recorded in the client span.
+func TestTracingTransportRedactsKey(t *testing.T) {
+	const secret = "0b6ee2a4-secret-key"
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query().Get("key")
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := &http.Client{Transport: newTracingTransport(http.DefaultTransport, otelhttp.WithTracerProvider(provider))}
+
+	response, err := client.Get(server.URL + "/api/v1/courses?key=" + secret + "&status=all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if received != secret {
+		t.Errorf("REST API received key %q, want %q", received, secret)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	var sawURL bool
+	for _, attr := range spans[0].Attributes() {
+		value := attr.Value.Emit()
+		if strings.Contains(value, secret) {
+			t.Errorf("span attribute %s = %q contains the access key", attr.Key, value)
+		}
+		if strings.Contains(value, "status=all") {
+			sawURL = true
+		}
+	}
+	if !sawURL {
+		t.Error("span has no attribute with the rest of the query")
+	}
+}
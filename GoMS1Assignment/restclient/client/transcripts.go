@@ -0,0 +1,126 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"GoMS1Assignment/coursesapi"
+
+	"github.com/kennygrant/sanitize"
+)
+
+// completionGrades are the grades a completion may be recorded with.
+var completionGrades = []string{"A+", "A", "A-", "B+", "B", "B-", "C+", "C", "C-", "D+", "D", "F", "P"}
+
+// transcript is the handler function for the transcript page of a student, which lists the
+// courses they completed by term with their GPA, and records or deletes completions.
+// Transcript, RecordCompletion and DeleteCompletion of the REST API are invoked.
+func transcript(w http.ResponseWriter, r *http.Request) {
+	clientMsg := "" // To display message to the user on the client
+	studentID := sanitize.Accents(r.FormValue("studentid"))
+	var form coursesapi.Completion
+	unavailable := false // Determine whether to show the service unavailable banner
+
+	var err error
+	if r.Method == http.MethodPost {
+		if r.FormValue("action") == "delete" {
+			err = api.DeleteCompletion(r.Context(), studentID, r.FormValue("termid"), r.FormValue("courseid"))
+			clientMsg = "Completion deleted successfully."
+		} else {
+			form = coursesapi.Completion{
+				TermID:   r.FormValue("termid"),
+				CourseID: r.FormValue("courseid"),
+				Grade:    r.FormValue("grade"),
+			}
+			form.Credits, err = strconv.ParseFloat(r.FormValue("credits"), 64)
+			if err != nil || form.Credits <= 0 {
+				clientMsg = "Please enter the credits as a number above 0."
+				err = nil
+			} else {
+				err = api.RecordCompletion(r.Context(), studentID, form)
+				clientMsg = fmt.Sprintf("%s recorded successfully.\n", form.CourseID)
+				if err == nil {
+					form = coursesapi.Completion{}
+				}
+			}
+		}
+
+		if errors.Is(err, coursesapi.ErrUnavailable) {
+			unavailable = true
+			clientMsg = ""
+		} else if errors.Is(err, coursesapi.ErrNotFound) {
+			clientMsg = ">> Student, course, term or completion not found."
+		} else if errors.Is(err, coursesapi.ErrInvalid) {
+			clientMsg = ">> Please select a grade and enter credits up to 999.9."
+		} else if err != nil {
+			logger(r.Context()).Error("error updating transcript", "studentid", studentID, "error", err)
+			clientMsg = ">> Error updating transcript."
+		}
+	}
+
+	t, err := api.Transcript(r.Context(), studentID) // Get the transcript
+	if errors.Is(err, coursesapi.ErrUnavailable) {
+		unavailable = true
+	} else if errors.Is(err, coursesapi.ErrNotFound) {
+		clientMsg = ">> Invalid Student ID"
+	} else if err != nil {
+		logger(r.Context()).Error("error retrieving transcript", "studentid", studentID, "error", err)
+	}
+	valid := err == nil
+
+	// Get the courses and terms for the completion form
+	courses, err := api.ListCourses(r.Context())
+	if err != nil && !errors.Is(err, coursesapi.ErrUnavailable) {
+		logger(r.Context()).Error("error retrieving courses", "error", err)
+	}
+	terms, err := api.ListTerms(r.Context())
+	if err != nil && !errors.Is(err, coursesapi.ErrUnavailable) {
+		logger(r.Context()).Error("error retrieving terms", "error", err)
+	}
+
+	data := struct {
+		StudentID   string
+		Valid       bool
+		Transcript  coursesapi.Transcript
+		Completion  coursesapi.Completion
+		Courses     []coursesapi.Course
+		Terms       []coursesapi.Term
+		Grades      []string
+		ClientMsg   string
+		Unavailable bool
+	}{
+		studentID,
+		valid,
+		t,
+		form,
+		courses,
+		terms,
+		completionGrades,
+		clientMsg,
+		unavailable,
+	}
+
+	tpl.ExecuteTemplate(w, "transcript.gohtml", data)
+}
+
+// transcriptPDF is the handler function to download the transcript of a student as a
+// printable PDF. TranscriptPDF of the REST API is invoked.
+func transcriptPDF(w http.ResponseWriter, r *http.Request) {
+	studentID := r.URL.Query().Get("studentid")
+
+	data, err := api.TranscriptPDF(r.Context(), studentID)
+	if errors.Is(err, coursesapi.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		logger(r.Context()).Error("error exporting transcript", "studentid", studentID, "error", err)
+		http.Error(w, "Error exporting transcript.", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="transcript.pdf"`)
+	w.Write(data)
+}
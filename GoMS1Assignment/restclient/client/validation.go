@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"GoMS1Assignment/coursesapi"
+	"GoMS1Assignment/validation"
+)
+
+// rulesRefresh is how often the rules are fetched from the REST API, so that the client
+// follows a change of configuration once the REST API is restarted.
+const rulesRefresh = time.Minute
+
+// rules are the rules the details of courses must meet, fetched from the REST API. The
+// default rules apply until they are first fetched; the REST API checks courses against
+// its own rules in any case.
+var rules atomic.Pointer[validation.Rules]
+
+// initRules sets the default rules and starts fetching the rules from the REST API,
+// every rulesRefresh.
+func initRules() {
+	defaults := validation.DefaultRules()
+	rules.Store(&defaults)
+
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := fetchRules(ctx); err != nil {
+				slog.Warn("error fetching validation rules from the REST API", "error", err)
+			}
+			cancel()
+			time.Sleep(rulesRefresh)
+		}
+	}()
+}
+
+// currentRules returns the rules last fetched from the REST API.
+func currentRules() validation.Rules {
+	return *rules.Load()
+}
+
+// fetchRules fetches the rules from the REST API. GetRules of the REST API is invoked.
+// Returns error type.
+func fetchRules(ctx context.Context) error {
+	r, err := api.GetRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	pattern, err := regexp.Compile(r.CourseIDPattern)
+	if err != nil {
+		return fmt.Errorf("invalid CourseIDPattern: %w", err)
+	}
+	rules.Store(&validation.Rules{
+		CourseIDPattern:      pattern,
+		CourseIDAllowed:      r.CourseIDAllowed,
+		CourseIDMaxLength:    r.CourseIDMaxLength,
+		TitleMaxLength:       r.TitleMaxLength,
+		DescriptionMaxLength: r.DescriptionMaxLength,
+		MaxTags:              r.MaxTags,
+		TagMaxLength:         r.TagMaxLength,
+	})
+	return nil
+}
+
+// invalidFieldsMsg is shown above a form when fields are marked with their errors.
+const invalidFieldsMsg = ">> Please correct the fields marked below."
+
+// apiFieldErrors returns the violations of the validation rules reported by the REST API
+// for the error given, or nil if there are none.
+func apiFieldErrors(err error) validation.Errors {
+	var apiErr *coursesapi.APIError
+	if errors.As(err, &apiErr) && len(apiErr.Fields) != 0 {
+		return apiErr.Fields
+	}
+	return nil
+}
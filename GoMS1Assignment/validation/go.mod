@@ -0,0 +1,5 @@
+module GoMS1Assignment/validation
+
+go 1.21
+
+require golang.org/x/text v0.16.0
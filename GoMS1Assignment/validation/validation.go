@@ -0,0 +1,285 @@
+/*
+Package validation implements the rules course details must meet, shared by the REST API
+and the client so that both accept the same courses.
+
+The rules default to those of the database schema and may be configured with environment
+variables, which the REST API reads from setup.env. The limits cannot exceed the sizes of the
+database columns the details are stored in. The client fetches the rules from the REST API,
+so that both services apply the rules the REST API is configured with:
+
+	COURSE_ID_PATTERN              characters allowed in course IDs, as a regular expression
+	COURSE_ID_MAX_LENGTH           maximum length of course IDs
+	COURSE_TITLE_MAX_LENGTH        maximum length of course titles
+	COURSE_DESCRIPTION_MAX_LENGTH  maximum length of course descriptions
+	COURSE_MAX_TAGS                maximum number of tags of a course
+	COURSE_TAG_MAX_LENGTH          maximum length of tags
+
+Course IDs are compared in their canonical form, given by CanonicalCourseID, so that
+"go101", " GO101 " and the full-width "ＧＯ１０１" are the same course. Both services
+canonicalise course IDs before checking them against the rules, and the REST API rejects a
+new course whose canonical ID is that of an existing course. Course IDs stored before they
+were canonicalised are reported by the courseids command of the REST API.
+
+Lengths are counted in characters. Violations are returned as Errors, keyed by the name of
+the field in the JSON of the REST API:
+
+	if errs := rules.ValidateNewCourse(course); len(errs) != 0 {
+		...
+	}
+*/
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fields of a course, as named in the JSON of the REST API.
+const (
+	CourseID    = "CourseID"
+	Title       = "Title"
+	Description = "Description"
+	Capacity    = "Capacity"
+	Tags        = "Tags"
+)
+
+// Rules are the constraints on the details of a course.
+type Rules struct {
+	CourseIDPattern      *regexp.Regexp // matched against the whole course ID
+	CourseIDAllowed      string         // describes what CourseIDPattern allows; the pattern is shown if ""
+	CourseIDMaxLength    int
+	TitleMaxLength       int
+	DescriptionMaxLength int
+	MaxTags              int
+	TagMaxLength         int
+}
+
+// Course is the details of a course checked by Rules.
+type Course struct {
+	ID          string
+	Title       string
+	Description string
+	Capacity    int // 0 for unlimited
+	Tags        []string
+}
+
+// Sizes in characters of the database columns the details of a course are stored in. The
+// limits of the rules cannot exceed them.
+const (
+	courseIDColumnSize    = 20    // VARCHAR(20)
+	titleColumnSize       = 45    // VARCHAR(45)
+	descriptionColumnSize = 16383 // TEXT of 65535 bytes, in 4 byte characters
+	tagColumnSize         = 30    // VARCHAR(30)
+)
+
+// DefaultRules returns the rules matching the database schema: course IDs of up to 6
+// letters, digits, - or _, titles of up to 45 characters, descriptions of up to 10000
+// characters and up to 20 tags of up to 30 characters.
+func DefaultRules() Rules {
+	return Rules{
+		CourseIDPattern:      regexp.MustCompile(`^[A-Za-z0-9_-]+$`),
+		CourseIDAllowed:      "letters, digits, - and _",
+		CourseIDMaxLength:    6,
+		TitleMaxLength:       45,
+		DescriptionMaxLength: 10000,
+		MaxTags:              20,
+		TagMaxLength:         30,
+	}
+}
+
+// FromEnv returns DefaultRules with the rules set in the environment, listed in the package
+// documentation, in place of the defaults. Returns an error naming the first variable which
+// is set to an invalid value, or to a limit greater than the size of its database column.
+func FromEnv() (Rules, error) {
+	rules := DefaultRules()
+
+	if pattern := os.Getenv("COURSE_ID_PATTERN"); pattern != "" {
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid COURSE_ID_PATTERN: %w", err)
+		}
+		rules.CourseIDPattern = re
+		rules.CourseIDAllowed = ""
+	}
+
+	limits := []struct {
+		name  string
+		value *int
+		max   int // 0 if there is no column to fit
+	}{
+		{"COURSE_ID_MAX_LENGTH", &rules.CourseIDMaxLength, courseIDColumnSize},
+		{"COURSE_TITLE_MAX_LENGTH", &rules.TitleMaxLength, titleColumnSize},
+		{"COURSE_DESCRIPTION_MAX_LENGTH", &rules.DescriptionMaxLength, descriptionColumnSize},
+		{"COURSE_MAX_TAGS", &rules.MaxTags, 0},
+		{"COURSE_TAG_MAX_LENGTH", &rules.TagMaxLength, tagColumnSize},
+	}
+	for _, limit := range limits {
+		s := os.Getenv(limit.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return Rules{}, fmt.Errorf("invalid %s: %q is not a positive number", limit.name, s)
+		}
+		if limit.max != 0 && n > limit.max {
+			return Rules{}, fmt.Errorf("invalid %s: %d is greater than the database column size of %d",
+				limit.name, n, limit.max)
+		}
+		*limit.value = n
+	}
+	return rules, nil
+}
+
+// rulesJSON is the json of Rules, with the course ID pattern as a string.
+type rulesJSON struct {
+	CourseIDPattern      string `json:"CourseIDPattern"`
+	CourseIDAllowed      string `json:"CourseIDAllowed,omitempty"`
+	CourseIDMaxLength    int    `json:"CourseIDMaxLength"`
+	TitleMaxLength       int    `json:"TitleMaxLength"`
+	DescriptionMaxLength int    `json:"DescriptionMaxLength"`
+	MaxTags              int    `json:"MaxTags"`
+	TagMaxLength         int    `json:"TagMaxLength"`
+}
+
+// MarshalJSON implements json.Marshaler, so the REST API can share its rules with the client.
+func (r Rules) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rulesJSON{r.CourseIDPattern.String(), r.CourseIDAllowed, r.CourseIDMaxLength,
+		r.TitleMaxLength, r.DescriptionMaxLength, r.MaxTags, r.TagMaxLength})
+}
+
+// CanonicalCourseID returns the canonical form of a course ID, under which course IDs are
+// stored and looked up: compatibility characters, such as full-width letters and digits,
+// replaced by their plain form (Unicode NFKC), surrounding spaces removed and letters in
+// upper case. Course IDs with the same canonical form name the same course.
+func CanonicalCourseID(id string) string {
+	return strings.ToUpper(strings.TrimSpace(norm.NFKC.String(id)))
+}
+
+// ValidateNewCourse checks the course ID and the details of a course being added.
+func (r Rules) ValidateNewCourse(course Course) Errors {
+	errs := r.ValidateCourse(course)
+	if msg := r.checkCourseID(course.ID); msg != "" {
+		errs.Add(CourseID, msg)
+	}
+	return errs
+}
+
+// ValidateCourseID checks the course ID of a course being added.
+func (r Rules) ValidateCourseID(id string) Errors {
+	errs := Errors{}
+	if msg := r.checkCourseID(id); msg != "" {
+		errs.Add(CourseID, msg)
+	}
+	return errs
+}
+
+// ValidateCourse checks the details of a course, other than its course ID, which is not
+// changed when a course is updated.
+func (r Rules) ValidateCourse(course Course) Errors {
+	errs := Errors{}
+	if msg := r.checkTitle(course.Title); msg != "" {
+		errs.Add(Title, msg)
+	}
+	if utf8.RuneCountInString(course.Description) > r.DescriptionMaxLength {
+		errs.Add(Description, fmt.Sprintf("cannot be greater than %d characters", r.DescriptionMaxLength))
+	}
+	if course.Capacity < 0 {
+		errs.Add(Capacity, "cannot be negative")
+	}
+	if len(course.Tags) > r.MaxTags {
+		errs.Add(Tags, fmt.Sprintf("cannot be more than %d", r.MaxTags))
+	}
+	for _, tag := range course.Tags {
+		if msg := r.checkTag(tag); msg != "" {
+			errs.Add(Tags, msg)
+			break
+		}
+	}
+	return errs
+}
+
+// ValidateTag checks a tag, which must be normalised to lower case with single spaces.
+func (r Rules) ValidateTag(tag string) Errors {
+	errs := Errors{}
+	if msg := r.checkTag(tag); msg != "" {
+		errs.Add(Tags, msg)
+	}
+	return errs
+}
+
+// checkCourseID returns the violation of the rules by a course ID, or "" if there is none.
+func (r Rules) checkCourseID(id string) string {
+	switch {
+	case id == "":
+		return "cannot be blank"
+	case utf8.RuneCountInString(id) > r.CourseIDMaxLength:
+		return fmt.Sprintf("cannot be greater than %d characters", r.CourseIDMaxLength)
+	case !r.CourseIDPattern.MatchString(id):
+		if r.CourseIDAllowed == "" {
+			return "must match " + r.CourseIDPattern.String()
+		}
+		return "may only contain " + r.CourseIDAllowed
+	}
+	return ""
+}
+
+// checkTitle returns the violation of the rules by a course title, or "" if there is none.
+func (r Rules) checkTitle(title string) string {
+	switch {
+	case strings.TrimSpace(title) == "":
+		return "cannot be blank"
+	case utf8.RuneCountInString(title) > r.TitleMaxLength:
+		return fmt.Sprintf("cannot be greater than %d characters", r.TitleMaxLength)
+	case strings.IndexFunc(title, unicode.IsControl) >= 0:
+		return "cannot contain control characters"
+	}
+	return ""
+}
+
+// checkTag returns the violation of the rules by a tag, or "" if there is none.
+func (r Rules) checkTag(tag string) string {
+	if tag == "" || utf8.RuneCountInString(tag) > r.TagMaxLength {
+		return fmt.Sprintf("must be 1 to %d characters", r.TagMaxLength)
+	}
+	for _, c := range tag {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune(" -_+#.", c) {
+			return "may only contain letters, digits, spaces and - _ + # ."
+		}
+	}
+	return ""
+}
+
+// Errors are the violations of the rules by the details of a course, keyed by field.
+type Errors map[string]string
+
+// Add records the violation of the field, keeping the first violation of each field.
+func (e Errors) Add(field string, message string) {
+	if _, ok := e[field]; !ok {
+		e[field] = message
+	}
+}
+
+// Error implements the error interface, listing the violations in order of field.
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	list := make([]string, len(fields))
+	for i, field := range fields {
+		list[i] = field + " " + e[field]
+	}
+	return strings.Join(list, "; ")
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(Rules) bool
+		wantErr string
+	}{
+		{"defaults", nil, func(r Rules) bool { return reflect.DeepEqual(r, DefaultRules()) }, ""},
+		{"limits", map[string]string{"COURSE_ID_MAX_LENGTH": "20", "COURSE_TITLE_MAX_LENGTH": "30", "COURSE_MAX_TAGS": "100"},
+			func(r Rules) bool { return r.CourseIDMaxLength == 20 && r.TitleMaxLength == 30 && r.MaxTags == 100 }, ""},
+		{"pattern", map[string]string{"COURSE_ID_PATTERN": "[A-Z]{2}[0-9]+"},
+			func(r Rules) bool {
+				return r.CourseIDPattern.MatchString("GO101") && !r.CourseIDPattern.MatchString("GO101X")
+			}, ""},
+		{"invalid pattern", map[string]string{"COURSE_ID_PATTERN": "[A-Z"}, nil, "COURSE_ID_PATTERN"},
+		{"not a number", map[string]string{"COURSE_TAG_MAX_LENGTH": "ten"}, nil, "COURSE_TAG_MAX_LENGTH"},
+		{"zero", map[string]string{"COURSE_MAX_TAGS": "0"}, nil, "COURSE_MAX_TAGS"},
+		{"course ID over column", map[string]string{"COURSE_ID_MAX_LENGTH": "21"}, nil, "COURSE_ID_MAX_LENGTH"},
+		{"title over column", map[string]string{"COURSE_TITLE_MAX_LENGTH": "46"}, nil, "COURSE_TITLE_MAX_LENGTH"},
+		{"description over column", map[string]string{"COURSE_DESCRIPTION_MAX_LENGTH": "16384"}, nil, "COURSE_DESCRIPTION_MAX_LENGTH"},
+		{"tag over column", map[string]string{"COURSE_TAG_MAX_LENGTH": "31"}, nil, "COURSE_TAG_MAX_LENGTH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"COURSE_ID_PATTERN", "COURSE_ID_MAX_LENGTH", "COURSE_TITLE_MAX_LENGTH",
+				"COURSE_DESCRIPTION_MAX_LENGTH", "COURSE_MAX_TAGS", "COURSE_TAG_MAX_LENGTH"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			rules, err := FromEnv()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FromEnv() error = %v, want one naming %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromEnv() error = %v", err)
+			}
+			if !tt.check(rules) {
+				t.Errorf("FromEnv() = %+v", rules)
+			}
+		})
+	}
+}
+
+func TestCanonicalCourseID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"GO101", "GO101"},
+		{"go101", "GO101"},
+		{" GO101 ", "GO101"},
+		{"ＧＯ１０１", "GO101"},
+		{"go-1_a", "GO-1_A"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CanonicalCourseID(tt.id); got != tt.want {
+			t.Errorf("CanonicalCourseID(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestValidateNewCourse(t *testing.T) {
+	t.Setenv("COURSE_ID_PATTERN", "[A-Z]{2}[0-9]{3}")
+	custom, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := Course{ID: "GO101", Title: "Go", Description: "Learn Go", Capacity: 10, Tags: []string{"go", "c++"}}
+	with := func(edit func(*Course)) Course {
+		c := valid
+		edit(&c)
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		rules  Rules
+		course Course
+		want   Errors
+	}{
+		{"valid", DefaultRules(), valid, Errors{}},
+		{"blank", DefaultRules(), Course{}, Errors{CourseID: "cannot be blank", Title: "cannot be blank"}},
+		{"long ID", DefaultRules(), with(func(c *Course) { c.ID = "GO10101" }),
+			Errors{CourseID: "cannot be greater than 6 characters"}},
+		{"ID characters", DefaultRules(), with(func(c *Course) { c.ID = "GO 10" }),
+			Errors{CourseID: "may only contain letters, digits, - and _"}},
+		{"ID pattern override", custom, with(func(c *Course) { c.ID = "GO10" }),
+			Errors{CourseID: "must match ^(?:[A-Z]{2}[0-9]{3})$"}},
+		{"title at limit", DefaultRules(), with(func(c *Course) { c.Title = strings.Repeat("é", 45) }), Errors{}},
+		{"long title", DefaultRules(), with(func(c *Course) { c.Title = strings.Repeat("a", 46) }),
+			Errors{Title: "cannot be greater than 45 characters"}},
+		{"control characters", DefaultRules(), with(func(c *Course) { c.Title = "Go\n101" }),
+			Errors{Title: "cannot contain control characters"}},
+		{"long description", DefaultRules(), with(func(c *Course) { c.Description = strings.Repeat("a", 10001) }),
+			Errors{Description: "cannot be greater than 10000 characters"}},
+		{"negative capacity", DefaultRules(), with(func(c *Course) { c.Capacity = -1 }),
+			Errors{Capacity: "cannot be negative"}},
+		{"too many tags", DefaultRules(), with(func(c *Course) { c.Tags = make([]string, 21) }),
+			Errors{Tags: "cannot be more than 20"}},
+		{"tag characters", DefaultRules(), with(func(c *Course) { c.Tags = []string{"go!"} }),
+			Errors{Tags: "may only contain letters, digits, spaces and - _ + # ."}},
+		{"long tag", DefaultRules(), with(func(c *Course) { c.Tags = []string{strings.Repeat("a", 31)} }),
+			Errors{Tags: "must be 1 to 30 characters"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.ValidateNewCourse(tt.course); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateNewCourse(%+v) = %v, want %v", tt.course, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorsError(t *testing.T) {
+	errs := Errors{Title: "cannot be blank", CourseID: "cannot be blank"}
+	errs.Add(Title, "ignored")
+	if got, want := errs.Error(), "CourseID cannot be blank; Title cannot be blank"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestRulesMarshalJSON(t *testing.T) {
+	t.Setenv("COURSE_ID_PATTERN", "[A-Z]{2}[0-9]{3}")
+	t.Setenv("COURSE_TITLE_MAX_LENGTH", "30")
+	rules, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"CourseIDPattern":"^(?:[A-Z]{2}[0-9]{3})$","CourseIDMaxLength":6,"TitleMaxLength":30,` +
+		`"DescriptionMaxLength":10000,"MaxTags":20,"TagMaxLength":30}`
+	if string(data) != want {
+		t.Errorf("json.Marshal(rules) = %s, want %s", data, want)
+	}
+}